@@ -6,14 +6,18 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -31,10 +35,20 @@ const (
 	// InitializationTimeout defines the maximum time allowed for cold start initialization
 	InitializationTimeout = 30 * time.Second
 
-	// ProcessingTimeout defines the maximum time allowed for processing a single file
-	ProcessingTimeout = 5 * time.Minute
+	// DefaultProcessingTimeout is the default maximum time allowed for processing a single file
+	DefaultProcessingTimeout = 5 * time.Minute
+
+	// DefaultRecordConcurrency is the default number of records processed at once
+	DefaultRecordConcurrency = 4
+
+	// DefaultObjectKeySuffixes are the default extensions of the object keys processed
+	DefaultObjectKeySuffixes = ".csv"
 )
 
+// errRecordSkipped is returned by processRecord for records filtered out by
+// the key filter. It's not a failure.
+var errRecordSkipped = errors.New("record skipped")
+
 // ApplicationDependencies encapsulates all dependencies needed by the processor.
 // This makes testing easier and dependency management more explicit.
 type ApplicationDependencies struct {
@@ -98,11 +112,27 @@ func buildProcessor() (application.TransactionProcessor, error) {
 }
 
 // initializeLogger creates and configures the application logger.
+// The LOG_LEVEL environment variable sets the lowest level logged (default: debug),
+// and LOG_FORMAT the output format, json or console (default: json).
 func initializeLogger() (blend.Logger, error) {
-	logger, err := blend.Default(os.Stdout)
+	format := blend.JSON
+	if name := os.Getenv("LOG_FORMAT"); name != "" {
+		format = blend.Format(name)
+	}
+
+	logger, err := blend.NewZerologLoggerWithFormat(os.Stdout, format)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create logger: %w", err)
 	}
+
+	if name := os.Getenv("LOG_LEVEL"); name != "" {
+		level, err := blend.ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LOG_LEVEL: %w", err)
+		}
+		logger.SetLevel(level)
+	}
+
 	return logger, nil
 }
 
@@ -134,11 +164,20 @@ func buildDependencies(ctx context.Context, logger blend.Logger) (*ApplicationDe
 
 	// 5) Build domain components.
 	logger.Debug(ctx, "Building domain components...")
-	storage := summaries.NewS3SummaryFilesStorage(s3Client)
+	storageCfg := summaries.DefaultS3SummaryFilesStorageConfig()
+	storageCfg.CanaryPath = os.Getenv("SELF_TEST_CANARY_PATH")
+	storage := summaries.NewS3SummaryFilesStorageWithConfig(s3Client, storageCfg)
 	loader := transactions.NewCSVTransactionLoader()
 	repo := transactions.NewDynamoTransactionsRepository(ddbClient, appCfg.TransactionsDynamoDB.TableName)
 	summarizer := summaries.NewDefaultSummarizer()
-	mailer := mailing.NewSMTPMailer(mailing.SMTPConfig(appCfg.EmailSMTP))
+	mailer := mailing.NewSMTPMailer(mailing.SMTPConfig{
+		Host:     appCfg.EmailSMTP.Host,
+		Port:     appCfg.EmailSMTP.Port,
+		Username: appCfg.EmailSMTP.Username,
+		Password: appCfg.EmailSMTP.Password,
+		From:     appCfg.EmailSMTP.From,
+		Logger:   logger,
+	})
 
 	return &ApplicationDependencies{
 		Logger:     logger,
@@ -152,61 +191,420 @@ func buildDependencies(ctx context.Context, logger blend.Logger) (*ApplicationDe
 }
 
 // ProcessingStats tracks processing statistics for better observability.
+// Records processed concurrently must update it through AddError and AddSuccess.
 type ProcessingStats struct {
+	mu sync.Mutex
+
 	TotalRecords   int
 	SuccessCount   int
 	FailureCount   int
+	SkippedCount   int
+	DuplicateCount int
+	BytesRead      int64
 	ProcessingTime time.Duration
 	Errors         []error
 }
 
 // AddError safely adds an error to the processing stats.
 func (s *ProcessingStats) AddError(recordIndex int, bucket, key string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.FailureCount++
 	wrappedErr := fmt.Errorf("record %d (s3://%s/%s): %w", recordIndex, bucket, key, err)
 	s.Errors = append(s.Errors, wrappedErr)
 }
 
+// HandlerResult is the outcome of an S3 event, returned by Handler as JSON.
+type HandlerResult struct {
+	// Summary is the human-readable summary, as logged.
+	Summary    string         `json:"summary"`
+	Total      int            `json:"total"`
+	Succeeded  int            `json:"succeeded"`
+	Failed     int            `json:"failed"`
+	Skipped    int            `json:"skipped"`
+	Duplicates int            `json:"duplicates"`
+	BytesRead  int64          `json:"bytes_read"`
+	DurationMS int64          `json:"duration_ms"`
+	Records    []RecordResult `json:"records"`
+}
+
+// RecordResult is the outcome of a single S3 record.
+type RecordResult struct {
+	Bucket     string `json:"bucket"`
+	Key        string `json:"key"`
+	Success    bool   `json:"success"`
+	Skipped    bool   `json:"skipped,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// AddSuccess safely counts a successfully processed record.
+func (s *ProcessingStats) AddSuccess() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.SuccessCount++
+}
+
+// AddBytesRead safely adds the bytes read from a processed file.
+func (s *ProcessingStats) AddBytesRead(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.BytesRead += n
+}
+
+// AddSkipped safely counts a record skipped by the key filter.
+func (s *ProcessingStats) AddSkipped() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.SkippedCount++
+}
+
 // Handler is the Lambda entrypoint for S3 "ObjectCreated:*" notifications.
 // It iterates over each record, validates input, and processes the corresponding
 // object. Failures are accumulated and reported with enhanced error handling
 // and observability.
-func Handler(ctx context.Context, event events.S3Event) (string, error) {
+//
+// Failed records don't fail the invocation (which would retrigger the entire
+// batch); they are reported in the returned HandlerResult instead.
+func Handler(ctx context.Context, event events.S3Event) (HandlerResult, error) {
 	startTime := time.Now()
 
 	// Get the processor instance (initialized once)
 	proc, err := getProcessor()
 	if err != nil {
-		return "", fmt.Errorf("failed to initialize processor: %w", err)
+		return HandlerResult{}, fmt.Errorf("failed to initialize processor: %w", err)
 	}
 
 	logger, _ := initializeLogger() // Safe to ignore error as getProcessor succeeded
+	result := handleS3Event(ctx, logger, proc, event, startTime, loadHandlerConfig(ctx, logger))
+
+	// Flush the logs before the Lambda environment is frozen
+	_ = logger.Sync(ctx)
+	return result, nil
+}
+
+// handlerConfig holds the configuration of the handlers, read from the
+// environment (see loadHandlerConfig).
+type handlerConfig struct {
+	// Concurrency is the maximum number of records processed at once.
+	Concurrency int
+
+	// KeyFilter selects the object keys to process; others are skipped.
+	KeyFilter keyFilter
+
+	// ProcessingTimeout is the maximum time allowed for processing a single
+	// file (non-positive: DefaultProcessingTimeout).
+	ProcessingTimeout time.Duration
+}
+
+// loadHandlerConfig reads the handler configuration from the environment,
+// falling back to the defaults.
+func loadHandlerConfig(ctx context.Context, logger blend.Logger) handlerConfig {
+	return handlerConfig{
+		Concurrency:       recordConcurrency(ctx, logger),
+		KeyFilter:         objectKeyFilter(),
+		ProcessingTimeout: recordProcessingTimeout(ctx, logger),
+	}
+}
+
+// keyFilter matches object keys by prefix and extension (suffix).
+type keyFilter struct {
+	// Prefix is the prefix keys must start with (empty: any).
+	Prefix string
+
+	// Suffixes are the suffixes keys may end with, case-insensitively (empty: any).
+	Suffixes []string
+}
+
+// matches reports whether the key passes the filter.
+func (f keyFilter) matches(key string) bool {
+	if !strings.HasPrefix(key, f.Prefix) {
+		return false
+	}
+	if len(f.Suffixes) == 0 {
+		return true
+	}
+
+	lowerKey := strings.ToLower(key)
+	for _, suffix := range f.Suffixes {
+		if strings.HasSuffix(lowerKey, strings.ToLower(suffix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// objectKeyFilter returns the key filter from the OBJECT_KEY_PREFIX (default:
+// none) and OBJECT_KEY_SUFFIXES (comma-separated, default: DefaultObjectKeySuffixes)
+// environment variables. OBJECT_KEY_SUFFIXES set but empty accepts any suffix.
+func objectKeyFilter() keyFilter {
+	suffixes, ok := os.LookupEnv("OBJECT_KEY_SUFFIXES")
+	if !ok {
+		suffixes = DefaultObjectKeySuffixes
+	}
+
+	filter := keyFilter{Prefix: os.Getenv("OBJECT_KEY_PREFIX")}
+	for _, suffix := range strings.Split(suffixes, ",") {
+		if suffix = strings.TrimSpace(suffix); suffix != "" {
+			filter.Suffixes = append(filter.Suffixes, suffix)
+		}
+	}
+	return filter
+}
+
+// recordConcurrency returns the number of records to process at once, from
+// the RECORD_CONCURRENCY environment variable (default: DefaultRecordConcurrency).
+func recordConcurrency(ctx context.Context, logger blend.Logger) int {
+	value := os.Getenv("RECORD_CONCURRENCY")
+	if value == "" {
+		return DefaultRecordConcurrency
+	}
+
+	concurrency, err := strconv.Atoi(value)
+	if err != nil || concurrency <= 0 {
+		logger.Warn(ctx, "Invalid RECORD_CONCURRENCY %q; using %d", value, DefaultRecordConcurrency)
+		return DefaultRecordConcurrency
+	}
+	return concurrency
+}
+
+// recordProcessingTimeout returns the maximum time allowed for processing a
+// single file, from the RECORD_PROCESSING_TIMEOUT environment variable as a Go
+// duration, e.g. "90s" or "2m30s" (default: DefaultProcessingTimeout).
+func recordProcessingTimeout(ctx context.Context, logger blend.Logger) time.Duration {
+	value := os.Getenv("RECORD_PROCESSING_TIMEOUT")
+	if value == "" {
+		return DefaultProcessingTimeout
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		logger.Warn(ctx, "Invalid RECORD_PROCESSING_TIMEOUT %q; using %s", value, DefaultProcessingTimeout)
+		return DefaultProcessingTimeout
+	}
+	return timeout
+}
+
+// handleS3Event processes the records of an S3 event, up to config.Concurrency
+// at once, returning the per-record outcomes. It's the testable core of Handler.
+func handleS3Event(ctx context.Context, logger blend.Logger, proc application.TransactionProcessor,
+	event events.S3Event, startTime time.Time, config handlerConfig) HandlerResult {
+
 	logger.Info(ctx, "Starting S3 event processing with %d records...", len(event.Records))
 
+	// The same object may be delivered twice in one event; process it once
+	records, duplicates := dedupeRecords(event.Records)
+	if duplicates > 0 {
+		logger.Warn(ctx, "Skipping %d duplicated records", duplicates)
+	}
+
 	stats := &ProcessingStats{
-		TotalRecords: len(event.Records),
-		Errors:       make([]error, 0),
+		TotalRecords:   len(records),
+		DuplicateCount: duplicates,
+		Errors:         make([]error, 0),
 	}
 
-	// Process each record with individual timeout and error handling
-	for i, rec := range event.Records {
-		if err := processRecord(ctx, logger, proc, i, rec, stats); err != nil {
-			// Error already logged and added to stats in processRecord
+	// Process each record with individual timeout and error handling, using a
+	// bounded pool of workers. Each worker writes only its records' results.
+	recordResults := make([]RecordResult, len(records))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(max(config.Concurrency, 1), len(records)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				recordStart := time.Now()
+				err := processRecord(ctx, logger, proc, i, records[i], stats, config)
+				recordResults[i] = newRecordResult(records[i], err, time.Since(recordStart))
+				if err != nil {
+					// Error (or skip) already logged and added to stats in processRecord
+					continue
+				}
+				stats.AddSuccess()
+			}
+		}()
+	}
+	for i := range records {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	stats.ProcessingTime = time.Since(startTime)
+	return HandlerResult{
+		Summary:    generateSummary(ctx, logger, stats),
+		Total:      stats.TotalRecords,
+		Succeeded:  stats.SuccessCount,
+		Failed:     stats.FailureCount,
+		Skipped:    stats.SkippedCount,
+		Duplicates: stats.DuplicateCount,
+		BytesRead:  stats.BytesRead,
+		DurationMS: stats.ProcessingTime.Milliseconds(),
+		Records:    recordResults,
+	}
+}
+
+// newRecordResult builds the outcome of a record from its processing error.
+func newRecordResult(rec events.S3EventRecord, err error, duration time.Duration) RecordResult {
+	result := RecordResult{
+		Bucket:     rec.S3.Bucket.Name,
+		Key:        rec.S3.Object.Key,
+		Success:    err == nil,
+		Skipped:    errors.Is(err, errRecordSkipped),
+		DurationMS: duration.Milliseconds(),
+	}
+	if err != nil && !result.Skipped {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// dedupeRecords removes the records of objects (bucket/key) already present
+// earlier in the list, keeping the first one, and returns how many were
+// removed. Records missing the bucket or key are kept, to be reported invalid.
+func dedupeRecords(records []events.S3EventRecord) (unique []events.S3EventRecord, duplicates int) {
+	seen := make(map[string]bool, len(records))
+	unique = make([]events.S3EventRecord, 0, len(records))
+	for _, rec := range records {
+		bucket, key := rec.S3.Bucket.Name, rec.S3.Object.Key
+		if bucket != "" && key != "" {
+			id := bucket + "/" + key
+			if seen[id] {
+				duplicates++
+				continue
+			}
+			seen[id] = true
+		}
+		unique = append(unique, rec)
+	}
+	return unique, duplicates
+}
+
+// SQSHandler is the Lambda entrypoint for S3 notifications delivered through
+// SQS (S3 -> SQS -> Lambda), an alternative to Handler selected with
+// LAMBDA_TRIGGER=sqs. Each message holds an S3 event; the messages with a
+// failed record are reported as batch item failures, so that only those are
+// retried (the event source mapping must enable ReportBatchItemFailures).
+func SQSHandler(ctx context.Context, event events.SQSEvent) (events.SQSEventResponse, error) {
+	startTime := time.Now()
+
+	// Get the processor instance (initialized once)
+	proc, err := getProcessor()
+	if err != nil {
+		return events.SQSEventResponse{}, fmt.Errorf("failed to initialize processor: %w", err)
+	}
+
+	logger, _ := initializeLogger() // Safe to ignore error as getProcessor succeeded
+	response := handleSQSEvent(ctx, logger, proc, event, startTime, loadHandlerConfig(ctx, logger))
+
+	// Flush the logs before the Lambda environment is frozen
+	_ = logger.Sync(ctx)
+	return response, nil
+}
+
+// handleSQSEvent processes the S3 events embedded in the messages of an SQS
+// event, returning the IDs of the messages that failed. It's the testable
+// core of SQSHandler.
+func handleSQSEvent(ctx context.Context, logger blend.Logger, proc application.TransactionProcessor,
+	event events.SQSEvent, startTime time.Time, config handlerConfig) events.SQSEventResponse {
+
+	logger.Info(ctx, "Starting SQS event processing with %d messages...", len(event.Records))
+
+	stats := &ProcessingStats{Errors: make([]error, 0)}
+	response := events.SQSEventResponse{BatchItemFailures: make([]events.SQSBatchItemFailure, 0)}
+
+	// Records are numbered across messages, so their correlation IDs are unique
+	recordIndex := 0
+	for _, msg := range event.Records {
+		var s3Event events.S3Event
+		if err := json.Unmarshal([]byte(msg.Body), &s3Event); err != nil {
+			logger.Error(ctx, "Failed to decode S3 event of message %s: %v", msg.MessageId, err)
+			stats.TotalRecords++
+			stats.AddError(recordIndex, "", "", fmt.Errorf("message %s: invalid S3 event: %w", msg.MessageId, err))
+			recordIndex++
+			response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: msg.MessageId})
 			continue
 		}
-		stats.SuccessCount++
+
+		failed := false
+		for _, rec := range s3Event.Records {
+			stats.TotalRecords++
+			switch err := processRecord(ctx, logger, proc, recordIndex, rec, stats, config); {
+			case errors.Is(err, errRecordSkipped):
+			case err != nil:
+				failed = true
+			default:
+				stats.AddSuccess()
+			}
+			recordIndex++
+		}
+
+		if failed {
+			response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: msg.MessageId})
+		}
 	}
 
 	stats.ProcessingTime = time.Since(startTime)
-	return generateSummary(ctx, logger, stats), nil
+	generateSummary(ctx, logger, stats)
+	return response
+}
+
+// SelfTestHandler is the Lambda entrypoint for checking the connectivity of
+// the dependencies (S3, DynamoDB and SMTP) without processing any file,
+// selected with LAMBDA_TRIGGER=selftest. The S3 check needs the path of a
+// canary object in SELF_TEST_CANARY_PATH.
+func SelfTestHandler(ctx context.Context) error {
+	// Get the processor instance (initialized once)
+	proc, err := getProcessor()
+	if err != nil {
+		return fmt.Errorf("failed to initialize processor: %w", err)
+	}
+
+	logger, _ := initializeLogger() // Safe to ignore error as getProcessor succeeded
+	err = runSelfTest(ctx, logger, proc)
+
+	// Flush the logs before the Lambda environment is frozen
+	_ = logger.Sync(ctx)
+	return err
+}
+
+// runSelfTest runs the self-test of the processor within the processing
+// timeout. It's the testable core of SelfTestHandler.
+func runSelfTest(ctx context.Context, logger blend.Logger, proc application.TransactionProcessor) error {
+	logger.Info(ctx, "Starting self-test...")
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultProcessingTimeout)
+	defer cancel()
+
+	if err := proc.SelfTest(ctx); err != nil {
+		logger.Error(ctx, "Self-test failed: %v", err)
+		return err
+	}
+
+	logger.Info(ctx, "Self-test passed")
+	return nil
 }
 
-// processRecord handles the processing of a single S3 record with proper error handling.
+// processRecord handles the processing of a single S3 record with proper error handling,
+// within the configured processing timeout.
+// Records whose key doesn't pass the key filter are skipped, returning errRecordSkipped.
 func processRecord(ctx context.Context, logger blend.Logger, proc application.TransactionProcessor,
-	recordIndex int, rec events.S3EventRecord, stats *ProcessingStats) error {
+	recordIndex int, rec events.S3EventRecord, stats *ProcessingStats, config handlerConfig) error {
+
+	// Tag every log line of this record with its own correlation ID
+	ctx = blend.ContextWithCorrelationID(ctx, recordCorrelationID(ctx, recordIndex))
 
 	// Create a timeout context for this specific record
-	recordCtx, cancel := context.WithTimeout(ctx, ProcessingTimeout)
+	timeout := config.ProcessingTimeout
+	if timeout <= 0 {
+		timeout = DefaultProcessingTimeout
+	}
+	recordCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	bucket, key, err := validateS3Record(rec)
@@ -216,25 +614,67 @@ func processRecord(ctx context.Context, logger blend.Logger, proc application.Tr
 		return err
 	}
 
+	if !config.KeyFilter.matches(key) {
+		logger.Info(ctx, "Skipping file s3://%s/%s, which doesn't match the object key filter", bucket, key)
+		stats.AddSkipped()
+		return errRecordSkipped
+	}
+
 	logger.Info(ctx, "Processing file from S3: s3://%s/%s...", bucket, key)
 
 	// Process the file with timeout context
-	if _, err := proc.ProcessFile(recordCtx, bucket, key); err != nil {
-		logger.Error(ctx, "Failed to process file s3://%s/%s: %v", bucket, key, err)
+	result, err := proc.ProcessFile(recordCtx, bucket, key)
+	if err != nil {
+		logger.Error(ctx, "Failed to process file s3://%s/%s (%s stage): %v", bucket, key, failedStage(err), err)
 		stats.AddError(recordIndex, bucket, key, err)
 		return err
 	}
+	stats.AddBytesRead(result.BytesRead)
 
-	logger.Info(ctx, "Successfully processed file s3://%s/%s", bucket, key)
+	logger.Info(ctx, "Successfully processed file s3://%s/%s (%d bytes, %d rows)", bucket, key, result.BytesRead, result.RowsParsed)
 	return nil
 }
 
+// failedStage names the pipeline stage a ProcessFile error comes from.
+func failedStage(err error) string {
+	switch {
+	case errors.Is(err, application.ErrFileLoad), errors.Is(err, application.ErrInvalidAccountID):
+		return "load"
+	case errors.Is(err, application.ErrParse):
+		return "parse"
+	case errors.Is(err, application.ErrTransform):
+		return "transform"
+	case errors.Is(err, application.ErrPersist):
+		return "persist"
+	case errors.Is(err, application.ErrMail):
+		return "mail"
+	default:
+		return "unknown"
+	}
+}
+
+// recordCorrelationID builds the correlation ID of a record from the Lambda
+// request ID and the record index (e.g. "c6af9ac6-7b61-11e6-9a41-93e8deadbeef/0").
+func recordCorrelationID(ctx context.Context, recordIndex int) string {
+	requestID := "local"
+	if lc, ok := lambdacontext.FromContext(ctx); ok && lc.AwsRequestID != "" {
+		requestID = lc.AwsRequestID
+	}
+	return fmt.Sprintf("%s/%d", requestID, recordIndex)
+}
+
 // generateSummary creates a comprehensive summary of the processing results.
 func generateSummary(ctx context.Context, logger blend.Logger, stats *ProcessingStats) string {
 	summary := fmt.Sprintf(
-		"S3 event processing completed: %d succeeded, %d failed (total: %d, duration: %v)",
-		stats.SuccessCount, stats.FailureCount, stats.TotalRecords, stats.ProcessingTime,
+		"S3 event processing completed: %d succeeded, %d failed (total: %d, bytes read: %d, duration: %v)",
+		stats.SuccessCount, stats.FailureCount, stats.TotalRecords, stats.BytesRead, stats.ProcessingTime,
 	)
+	if stats.SkippedCount > 0 {
+		summary += fmt.Sprintf(", %d filtered out", stats.SkippedCount)
+	}
+	if stats.DuplicateCount > 0 {
+		summary += fmt.Sprintf(", %d duplicates skipped", stats.DuplicateCount)
+	}
 
 	if len(stats.Errors) > 0 {
 		logger.Warn(ctx, "Processing completed with %d errors", len(stats.Errors))
@@ -265,6 +705,16 @@ func validateS3Record(rec events.S3EventRecord) (bucket string, key string, err
 	}
 }
 
+// main starts the Lambda with the handler for the configured trigger: direct
+// S3 notifications (default), with LAMBDA_TRIGGER=sqs, SQS messages, or with
+// LAMBDA_TRIGGER=selftest, the self-test of the dependencies.
 func main() {
-	lambda.Start(Handler)
+	switch os.Getenv("LAMBDA_TRIGGER") {
+	case "sqs":
+		lambda.Start(SQSHandler)
+	case "selftest":
+		lambda.Start(SelfTestHandler)
+	default:
+		lambda.Start(Handler)
+	}
 }
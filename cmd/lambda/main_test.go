@@ -0,0 +1,511 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"stori-challenge/internal/application"
+	"stori-challenge/pkg/blend"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProcessor is a TransactionProcessor that fails the configured keys and
+// records the keys it processes, their deadlines, and how many it processed at once.
+// Each processed file reports bytesRead bytes.
+type fakeProcessor struct {
+	mu          sync.Mutex
+	failKeys    map[string]bool
+	delay       time.Duration
+	processed   []string
+	deadlines   []time.Time
+	inFlight    int
+	maxInFlight int
+	bytesRead   int64
+	selfTestErr error
+}
+
+func (p *fakeProcessor) ProcessFile(ctx context.Context, bucket, key string) (*application.ProcessingResult, error) {
+	p.mu.Lock()
+	if deadline, ok := ctx.Deadline(); ok {
+		p.deadlines = append(p.deadlines, deadline)
+	}
+	p.inFlight++
+	p.maxInFlight = max(p.maxInFlight, p.inFlight)
+	p.mu.Unlock()
+
+	time.Sleep(p.delay)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inFlight--
+	p.processed = append(p.processed, bucket+"/"+key)
+	if p.failKeys[key] {
+		return nil, application.ErrParse
+	}
+	return &application.ProcessingResult{FilePath: "s3://" + bucket + "/" + key, BytesRead: p.bytesRead}, nil
+}
+
+func (p *fakeProcessor) ProcessFiles(_ context.Context, _ []application.FileRef) (*application.ProcessingResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *fakeProcessor) SelfTest(_ context.Context) error {
+	return p.selfTestErr
+}
+
+// newS3Record returns an S3 event record for the given object.
+func newS3Record(bucket, key string) events.S3EventRecord {
+	var rec events.S3EventRecord
+	rec.S3.Bucket.Name = bucket
+	rec.S3.Object.Key = key
+	return rec
+}
+
+// newSQSMessage returns an SQS message holding an S3 event with the given records.
+func newSQSMessage(t *testing.T, id string, records ...events.S3EventRecord) events.SQSMessage {
+	body, err := json.Marshal(events.S3Event{Records: records})
+	require.NoError(t, err)
+	return events.SQSMessage{MessageId: id, Body: string(body)}
+}
+
+func TestHandleS3Event(t *testing.T) {
+	t.Run("it should process a duplicated record once", func(t *testing.T) {
+		// Arrange
+		proc := &fakeProcessor{}
+		event := events.S3Event{Records: []events.S3EventRecord{
+			newS3Record("bucket", "july.csv"),
+			newS3Record("bucket", "august.csv"),
+			newS3Record("bucket", "july.csv"),
+			newS3Record("other-bucket", "july.csv"),
+		}}
+
+		// Act
+		result := handleS3Event(context.Background(), blend.NewDummyLogger(), proc, event, time.Now(), handlerConfig{Concurrency: 1})
+
+		// Assert
+		assert.Equal(t, []string{"bucket/july.csv", "bucket/august.csv", "other-bucket/july.csv"}, proc.processed)
+		assert.Contains(t, result.Summary, "3 succeeded, 0 failed (total: 3,")
+		assert.Contains(t, result.Summary, ", 1 duplicates skipped")
+		assert.Equal(t, 3, result.Total)
+		assert.Equal(t, 1, result.Duplicates)
+	})
+
+	t.Run("it should not mention duplicates when there are none", func(t *testing.T) {
+		// Arrange
+		proc := &fakeProcessor{failKeys: map[string]bool{"bad.csv": true}}
+		event := events.S3Event{Records: []events.S3EventRecord{
+			newS3Record("bucket", "july.csv"),
+			newS3Record("bucket", "bad.csv"),
+		}}
+
+		// Act
+		result := handleS3Event(context.Background(), blend.NewDummyLogger(), proc, event, time.Now(), handlerConfig{Concurrency: 1})
+
+		// Assert
+		assert.Contains(t, result.Summary, "1 succeeded, 1 failed (total: 2,")
+		assert.NotContains(t, result.Summary, "duplicates")
+	})
+
+	t.Run("it should add up the bytes read from the processed files", func(t *testing.T) {
+		// Arrange
+		proc := &fakeProcessor{failKeys: map[string]bool{"bad.csv": true}, bytesRead: 544}
+		event := events.S3Event{Records: []events.S3EventRecord{
+			newS3Record("bucket", "july.csv"),
+			newS3Record("bucket", "bad.csv"),
+			newS3Record("bucket", "august.csv"),
+		}}
+
+		// Act
+		result := handleS3Event(context.Background(), blend.NewDummyLogger(), proc, event, time.Now(), handlerConfig{Concurrency: 1})
+
+		// Assert
+		assert.Equal(t, int64(2*544), result.BytesRead)
+		assert.Contains(t, result.Summary, "bytes read: 1088,")
+	})
+
+	t.Run("it should return the outcome of every record as JSON", func(t *testing.T) {
+		// Arrange
+		proc := &fakeProcessor{failKeys: map[string]bool{"bad.csv": true}}
+		event := events.S3Event{Records: []events.S3EventRecord{
+			newS3Record("bucket", "july.csv"),
+			newS3Record("bucket", "bad.csv"),
+			newS3Record("bucket", ""),
+		}}
+
+		// Act
+		result := handleS3Event(context.Background(), blend.NewDummyLogger(), proc, event, time.Now(), handlerConfig{Concurrency: 1})
+		marshaled, err := json.Marshal(result)
+		require.NoError(t, err)
+
+		var actual struct {
+			Summary    string `json:"summary"`
+			Total      int    `json:"total"`
+			Succeeded  int    `json:"succeeded"`
+			Failed     int    `json:"failed"`
+			Duplicates int    `json:"duplicates"`
+			DurationMS *int64 `json:"duration_ms"`
+			Records    []struct {
+				Bucket     string  `json:"bucket"`
+				Key        string  `json:"key"`
+				Success    bool    `json:"success"`
+				Error      *string `json:"error"`
+				DurationMS *int64  `json:"duration_ms"`
+			} `json:"records"`
+		}
+		require.NoError(t, json.Unmarshal(marshaled, &actual))
+
+		// Assert
+		assert.Equal(t, result.Summary, actual.Summary)
+		assert.Equal(t, 3, actual.Total)
+		assert.Equal(t, 1, actual.Succeeded)
+		assert.Equal(t, 2, actual.Failed)
+		assert.Equal(t, 0, actual.Duplicates)
+		assert.NotNil(t, actual.DurationMS)
+		require.Len(t, actual.Records, 3)
+
+		assert.Equal(t, "bucket", actual.Records[0].Bucket)
+		assert.Equal(t, "july.csv", actual.Records[0].Key)
+		assert.True(t, actual.Records[0].Success)
+		assert.Nil(t, actual.Records[0].Error)
+		assert.NotNil(t, actual.Records[0].DurationMS)
+
+		assert.Equal(t, "bad.csv", actual.Records[1].Key)
+		assert.False(t, actual.Records[1].Success)
+		require.NotNil(t, actual.Records[1].Error)
+		assert.Equal(t, application.ErrParse.Error(), *actual.Records[1].Error)
+
+		assert.Equal(t, "", actual.Records[2].Key)
+		assert.False(t, actual.Records[2].Success)
+		require.NotNil(t, actual.Records[2].Error)
+		assert.Equal(t, "missing object key for bucket: bucket", *actual.Records[2].Error)
+	})
+}
+
+func TestHandleS3Event_KeyFilter(t *testing.T) {
+	t.Run("it should process matching keys and skip the others", func(t *testing.T) {
+		// Arrange
+		proc := &fakeProcessor{}
+		event := events.S3Event{Records: []events.S3EventRecord{
+			newS3Record("bucket", "july.csv"),
+			newS3Record("bucket", "notes.txt"),
+		}}
+		config := handlerConfig{Concurrency: 1, KeyFilter: keyFilter{Suffixes: []string{".csv"}}}
+
+		// Act
+		result := handleS3Event(context.Background(), blend.NewDummyLogger(), proc, event, time.Now(), config)
+
+		// Assert
+		assert.Equal(t, []string{"bucket/july.csv"}, proc.processed)
+		assert.Equal(t, 1, result.Succeeded)
+		assert.Equal(t, 0, result.Failed)
+		assert.Equal(t, 1, result.Skipped)
+		assert.Contains(t, result.Summary, "1 succeeded, 0 failed (total: 2,")
+		assert.Contains(t, result.Summary, ", 1 filtered out")
+		require.Len(t, result.Records, 2)
+		assert.True(t, result.Records[0].Success)
+		assert.False(t, result.Records[0].Skipped)
+		assert.False(t, result.Records[1].Success)
+		assert.True(t, result.Records[1].Skipped)
+		assert.Empty(t, result.Records[1].Error)
+	})
+}
+
+func TestKeyFilter_Matches(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   keyFilter
+		key      string
+		expected bool
+	}{
+		{name: "it should match any key without restrictions", filter: keyFilter{}, key: "notes.txt", expected: true},
+		{name: "it should match a configured suffix", filter: keyFilter{Suffixes: []string{".csv"}}, key: "july.csv", expected: true},
+		{name: "it should match a suffix case-insensitively", filter: keyFilter{Suffixes: []string{".csv"}}, key: "JULY.CSV", expected: true},
+		{name: "it should match any of the suffixes", filter: keyFilter{Suffixes: []string{".csv", ".tsv"}}, key: "july.tsv", expected: true},
+		{name: "it should not match another suffix", filter: keyFilter{Suffixes: []string{".csv"}}, key: "notes.txt", expected: false},
+		{name: "it should match a configured prefix", filter: keyFilter{Prefix: "incoming/"}, key: "incoming/july.csv", expected: true},
+		{name: "it should not match another prefix", filter: keyFilter{Prefix: "incoming/"}, key: "archive/july.csv", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual := tt.filter.matches(tt.key)
+
+			// Assert
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestObjectKeyFilter(t *testing.T) {
+	t.Run("it should default to CSV files with any prefix", func(t *testing.T) {
+		// Act
+		actual := objectKeyFilter()
+
+		// Assert
+		assert.Equal(t, keyFilter{Suffixes: []string{".csv"}}, actual)
+	})
+
+	t.Run("it should read the configured prefix and suffixes", func(t *testing.T) {
+		// Arrange
+		t.Setenv("OBJECT_KEY_PREFIX", "incoming/")
+		t.Setenv("OBJECT_KEY_SUFFIXES", ".csv, .tsv,")
+
+		// Act
+		actual := objectKeyFilter()
+
+		// Assert
+		assert.Equal(t, keyFilter{Prefix: "incoming/", Suffixes: []string{".csv", ".tsv"}}, actual)
+	})
+
+	t.Run("it should accept any suffix when the suffixes are set empty", func(t *testing.T) {
+		// Arrange
+		t.Setenv("OBJECT_KEY_SUFFIXES", "")
+
+		// Act
+		actual := objectKeyFilter()
+
+		// Assert
+		assert.Equal(t, keyFilter{}, actual)
+	})
+}
+
+func TestHandleS3Event_Concurrency(t *testing.T) {
+	t.Run("it should process every record with a bounded pool of workers", func(t *testing.T) {
+		// Arrange
+		var records []events.S3EventRecord
+		expected := make([]string, 0, 20)
+		for i := range 20 {
+			key := fmt.Sprintf("file-%02d.csv", i)
+			records = append(records, newS3Record("bucket", key))
+			expected = append(expected, "bucket/"+key)
+		}
+		proc := &fakeProcessor{failKeys: map[string]bool{"file-03.csv": true, "file-17.csv": true}, delay: 5 * time.Millisecond}
+
+		// Act
+		result := handleS3Event(context.Background(), blend.NewDummyLogger(), proc, events.S3Event{Records: records}, time.Now(), handlerConfig{Concurrency: 4})
+
+		// Assert
+		assert.ElementsMatch(t, expected, proc.processed)
+		assert.LessOrEqual(t, proc.maxInFlight, 4)
+		assert.Greater(t, proc.maxInFlight, 1)
+		assert.Equal(t, 20, result.Total)
+		assert.Equal(t, 18, result.Succeeded)
+		assert.Equal(t, 2, result.Failed)
+		require.Len(t, result.Records, 20)
+		for i, record := range result.Records {
+			assert.Equal(t, fmt.Sprintf("file-%02d.csv", i), record.Key)
+			assert.Equal(t, i != 3 && i != 17, record.Success)
+		}
+	})
+
+	t.Run("it should handle an event without records", func(t *testing.T) {
+		// Act
+		result := handleS3Event(context.Background(), blend.NewDummyLogger(), &fakeProcessor{}, events.S3Event{}, time.Now(), handlerConfig{Concurrency: 4})
+
+		// Assert
+		assert.Equal(t, 0, result.Total)
+		assert.Empty(t, result.Records)
+	})
+}
+
+func TestRecordConcurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected int
+	}{
+		{name: "it should default when unset", value: "", expected: DefaultRecordConcurrency},
+		{name: "it should read the configured value", value: "8", expected: 8},
+		{name: "it should default on a non-positive value", value: "0", expected: DefaultRecordConcurrency},
+		{name: "it should default on a malformed value", value: "many", expected: DefaultRecordConcurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("RECORD_CONCURRENCY", tt.value)
+
+			// Act
+			actual := recordConcurrency(context.Background(), blend.NewDummyLogger())
+
+			// Assert
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestRecordProcessingTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "it should default when unset", value: "", expected: DefaultProcessingTimeout},
+		{name: "it should read the configured duration", value: "90s", expected: 90 * time.Second},
+		{name: "it should read a compound duration", value: "2m30s", expected: 150 * time.Second},
+		{name: "it should default on a non-positive duration", value: "0s", expected: DefaultProcessingTimeout},
+		{name: "it should default on a duration without unit", value: "300", expected: DefaultProcessingTimeout},
+		{name: "it should default on a malformed duration", value: "five minutes", expected: DefaultProcessingTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("RECORD_PROCESSING_TIMEOUT", tt.value)
+
+			// Act
+			actual := recordProcessingTimeout(context.Background(), blend.NewDummyLogger())
+
+			// Assert
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestHandleS3Event_ProcessingTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		expected time.Duration
+	}{
+		{name: "it should process each record within the configured timeout", timeout: time.Minute, expected: time.Minute},
+		{name: "it should default when the timeout is unset", timeout: 0, expected: DefaultProcessingTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			proc := &fakeProcessor{}
+			event := events.S3Event{Records: []events.S3EventRecord{newS3Record("bucket", "a.csv")}}
+			config := handlerConfig{Concurrency: 1, ProcessingTimeout: tt.timeout}
+
+			// Act
+			start := time.Now()
+			handleS3Event(context.Background(), blend.NewDummyLogger(), proc, event, start, config)
+
+			// Assert
+			require.Len(t, proc.deadlines, 1)
+			assert.WithinDuration(t, start.Add(tt.expected), proc.deadlines[0], time.Second)
+		})
+	}
+}
+
+func TestDedupeRecords(t *testing.T) {
+	t.Run("it should keep the first record of each object and invalid records", func(t *testing.T) {
+		// Arrange
+		records := []events.S3EventRecord{
+			newS3Record("bucket", "july.csv"),
+			newS3Record("", ""),
+			newS3Record("bucket", "july.csv"),
+			newS3Record("", ""),
+			newS3Record("bucket", "july.csv"),
+		}
+
+		// Act
+		unique, duplicates := dedupeRecords(records)
+
+		// Assert
+		assert.Equal(t, []events.S3EventRecord{records[0], records[1], records[3]}, unique)
+		assert.Equal(t, 2, duplicates)
+	})
+}
+
+func TestHandleSQSEvent(t *testing.T) {
+	t.Run("it should report the messages with a failed record", func(t *testing.T) {
+		// Arrange
+		proc := &fakeProcessor{failKeys: map[string]bool{"bad.csv": true}}
+		event := events.SQSEvent{Records: []events.SQSMessage{
+			newSQSMessage(t, "msg-1", newS3Record("bucket", "ok-1.csv")),
+			newSQSMessage(t, "msg-2", newS3Record("bucket", "ok-2.csv"), newS3Record("bucket", "bad.csv")),
+			newSQSMessage(t, "msg-3", newS3Record("bucket", "ok-3.csv")),
+			{MessageId: "msg-4", Body: "not json"},
+			newSQSMessage(t, "msg-5", newS3Record("", "")),
+		}}
+
+		// Act
+		response := handleSQSEvent(context.Background(), blend.NewDummyLogger(), proc, event, time.Now(), handlerConfig{Concurrency: 1})
+
+		// Assert
+		assert.Equal(t, []events.SQSBatchItemFailure{
+			{ItemIdentifier: "msg-2"},
+			{ItemIdentifier: "msg-4"},
+			{ItemIdentifier: "msg-5"},
+		}, response.BatchItemFailures)
+		assert.Equal(t, []string{"bucket/ok-1.csv", "bucket/ok-2.csv", "bucket/bad.csv", "bucket/ok-3.csv"}, proc.processed)
+	})
+
+	t.Run("it should report no failures when every message succeeds", func(t *testing.T) {
+		// Arrange
+		proc := &fakeProcessor{}
+		event := events.SQSEvent{Records: []events.SQSMessage{
+			newSQSMessage(t, "msg-1", newS3Record("bucket", "ok-1.csv")),
+			newSQSMessage(t, "msg-2"),
+		}}
+
+		// Act
+		response := handleSQSEvent(context.Background(), blend.NewDummyLogger(), proc, event, time.Now(), handlerConfig{Concurrency: 1})
+
+		// Assert
+		assert.Empty(t, response.BatchItemFailures)
+		assert.Equal(t, []string{"bucket/ok-1.csv"}, proc.processed)
+	})
+
+	t.Run("it should not report skipped records as failures", func(t *testing.T) {
+		// Arrange
+		proc := &fakeProcessor{}
+		event := events.SQSEvent{Records: []events.SQSMessage{newSQSMessage(t, "msg-1", newS3Record("bucket", "notes.txt"))}}
+		config := handlerConfig{Concurrency: 1, KeyFilter: keyFilter{Suffixes: []string{".csv"}}}
+
+		// Act
+		response := handleSQSEvent(context.Background(), blend.NewDummyLogger(), proc, event, time.Now(), config)
+
+		// Assert
+		assert.Empty(t, response.BatchItemFailures)
+		assert.Empty(t, proc.processed)
+	})
+
+	t.Run("it should marshal the failures as Lambda expects", func(t *testing.T) {
+		// Arrange
+		proc := &fakeProcessor{failKeys: map[string]bool{"bad.csv": true}}
+		event := events.SQSEvent{Records: []events.SQSMessage{newSQSMessage(t, "msg-1", newS3Record("bucket", "bad.csv"))}}
+
+		// Act
+		response := handleSQSEvent(context.Background(), blend.NewDummyLogger(), proc, event, time.Now(), handlerConfig{Concurrency: 1})
+		actual, err := json.Marshal(response)
+
+		// Assert
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"batchItemFailures":[{"itemIdentifier":"msg-1"}]}`, string(actual))
+	})
+}
+
+func TestRunSelfTest(t *testing.T) {
+	t.Run("it should pass when the dependencies are reachable", func(t *testing.T) {
+		// Act
+		err := runSelfTest(context.Background(), blend.NewDummyLogger(), &fakeProcessor{})
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("it should fail when a dependency is unreachable", func(t *testing.T) {
+		// Arrange
+		failure := fmt.Errorf("%w: mailer: dial tcp: connection refused", application.ErrSelfTest)
+		proc := &fakeProcessor{selfTestErr: failure}
+
+		// Act
+		err := runSelfTest(context.Background(), blend.NewDummyLogger(), proc)
+
+		// Assert
+		assert.ErrorIs(t, err, application.ErrSelfTest)
+	})
+}
@@ -2,56 +2,90 @@ package application
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidConfig is returned when a loaded configuration value is invalid.
+	ErrInvalidConfig = errors.New("invalid configuration")
 )
 
 // TransactionsDynamoDBConfig holds the configuration details for connecting to DynamoDB.
 type TransactionsDynamoDBConfig struct {
 	// TableName is the name of the DynamoDB table.
-	TableName string
+	TableName string `json:"table_name"`
 }
 
 // SMTPConfig holds the configuration details for connecting to an SMTP server.
 type SMTPConfig struct {
 	// Host is the SMTP server host.
-	Host string
+	Host string `json:"host"`
 
 	// Port is the SMTP server port.
-	Port int
+	Port int `json:"port"`
 
 	// Username is the SMTP server username.
-	Username string
+	Username string `json:"username"`
 
 	// Password is the SMTP server password.
-	Password string
+	Password string `json:"password"`
 
 	// From is the default "from" email address.
-	From string
+	From string `json:"from"`
 }
 
 // ApplicationConfig holds the configuration for the application.
 type ApplicationConfig struct {
 	// TransactionsDynamoDB holds the configuration for the transactions DynamoDB.
-	TransactionsDynamoDB TransactionsDynamoDBConfig
+	TransactionsDynamoDB TransactionsDynamoDBConfig `json:"transactions_dynamodb"`
 
 	// EmailSMTP holds the configuration for the SMTP server used for sending emails.
-	EmailSMTP SMTPConfig
+	EmailSMTP SMTPConfig `json:"email_smtp"`
+}
+
+// Defaults holds the fallback values for optional configuration values,
+// used when their source is absent (e.g. the secret doesn't exist).
+type Defaults struct {
+	// SMTPPort is the SMTP server port used when SMTP_PORT is absent.
+	SMTPPort int
 }
 
-// Load loads application configuration from providers.
+// DefaultDefaults returns the default fallback values for optional
+// configuration values.
+func DefaultDefaults() Defaults {
+	return Defaults{
+		SMTPPort: 587,
+	}
+}
+
+// Load loads application configuration from providers, falling back to
+// DefaultDefaults for optional values.
 // Returns an error if any critical value is missing.
 func (config *ApplicationConfig) Load(ctx context.Context, env EnvProvider, secrets SecretsProvider) error {
+	return config.LoadWithDefaults(ctx, env, secrets, DefaultDefaults())
+}
+
+// LoadWithDefaults loads application configuration from providers, falling
+// back to the given defaults for optional values whose source is absent.
+// Returns an error if any critical value is missing.
+func (config *ApplicationConfig) LoadWithDefaults(ctx context.Context, env EnvProvider, secrets SecretsProvider, defaults Defaults) error {
 	// DynamoDB table name (must be present)
 	table, err := env.GetEnv("DYNAMODB_TABLE_NAME")
 	if err != nil {
 		return err
 	}
 
-	// SMTP configuration (all must be present)
+	// SMTP configuration (all but the port must be present)
 	host, err := secrets.GetString(ctx, "SMTP_HOST")
 	if err != nil {
 		return err
 	}
 	port, err := secrets.GetInt(ctx, "SMTP_PORT")
+	if errors.Is(err, ErrSecretNotFound) {
+		port, err = defaults.SMTPPort, nil
+	}
 	if err != nil {
 		return err
 	}
@@ -77,5 +111,57 @@ func (config *ApplicationConfig) Load(ctx context.Context, env EnvProvider, secr
 		Password: pass,
 		From:     from,
 	}
+	return config.validate()
+}
+
+// LoadFromJSON loads application configuration from a single secret holding
+// a JSON document, as an alternative to Load's one secret per value.
+// Optional values absent from the document fall back to DefaultDefaults:
+//
+//	{
+//	  "transactions_dynamodb": {"table_name": "transactions"},
+//	  "email_smtp": {"host": "smtp.example.com", "port": 587, "username": "...", "password": "...", "from": "..."}
+//	}
+//
+// Returns an error if the secret is missing, malformed, or invalid.
+func (config *ApplicationConfig) LoadFromJSON(ctx context.Context, secrets SecretsProvider, key string) error {
+	document, err := secrets.GetString(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	// Decode into a copy, so a malformed document leaves the config untouched.
+	// The copy starts with the defaults, which the document's fields override.
+	defaults := DefaultDefaults()
+	loaded := ApplicationConfig{
+		EmailSMTP: SMTPConfig{Port: defaults.SMTPPort},
+	}
+	if err := json.Unmarshal([]byte(document), &loaded); err != nil {
+		return fmt.Errorf("%w: secret %s is not a valid JSON document: %v", ErrInvalidConfig, key, err)
+	}
+	if err := loaded.validate(); err != nil {
+		return err
+	}
+
+	*config = loaded
+	return nil
+}
+
+// validate checks that the loaded configuration values are usable.
+func (config *ApplicationConfig) validate() error {
+	if config.TransactionsDynamoDB.TableName == "" {
+		return fmt.Errorf("%w: DYNAMODB_TABLE_NAME must not be empty", ErrInvalidConfig)
+	}
+
+	smtp := config.EmailSMTP
+	if smtp.Port < 1 || smtp.Port > 65535 {
+		return fmt.Errorf("%w: SMTP_PORT must be between 1 and 65535, got %d", ErrInvalidConfig, smtp.Port)
+	}
+	if smtp.Host == "" {
+		return fmt.Errorf("%w: SMTP_HOST must not be empty", ErrInvalidConfig)
+	}
+	if smtp.From == "" {
+		return fmt.Errorf("%w: SMTP_FROM must not be empty", ErrInvalidConfig)
+	}
 	return nil
 }
@@ -0,0 +1,266 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEnvProvider is an EnvProvider backed by a map.
+type fakeEnvProvider map[string]string
+
+func (p fakeEnvProvider) GetEnv(key string) (string, error) {
+	value, ok := p[key]
+	if !ok {
+		return "", ErrEnvVarNotSet
+	}
+	return value, nil
+}
+
+// newTestSecrets returns a complete set of SMTP secrets.
+func newTestSecrets() map[string]string {
+	return map[string]string{
+		"SMTP_HOST":     "smtp.example.com",
+		"SMTP_PORT":     "587",
+		"SMTP_USERNAME": "user",
+		"SMTP_PASSWORD": "secret",
+		"SMTP_FROM":     "noreply@example.com",
+	}
+}
+
+func TestApplicationConfig_Load(t *testing.T) {
+	env := fakeEnvProvider{"DYNAMODB_TABLE_NAME": "transactions"}
+
+	t.Run("it should load a valid configuration", func(t *testing.T) {
+		// Arrange
+		var config ApplicationConfig
+
+		// Act
+		err := config.Load(context.Background(), env, NewMapSecretsProvider(newTestSecrets()))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "transactions", config.TransactionsDynamoDB.TableName)
+		assert.Equal(t, SMTPConfig{
+			Host:     "smtp.example.com",
+			Port:     587,
+			Username: "user",
+			Password: "secret",
+			From:     "noreply@example.com",
+		}, config.EmailSMTP)
+	})
+
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr string
+	}{
+		{name: "it should reject a zero port", key: "SMTP_PORT", value: "0", wantErr: "SMTP_PORT must be between 1 and 65535, got 0"},
+		{name: "it should reject a negative port", key: "SMTP_PORT", value: "-25", wantErr: "SMTP_PORT must be between 1 and 65535, got -25"},
+		{name: "it should reject a port above 65535", key: "SMTP_PORT", value: "65536", wantErr: "SMTP_PORT must be between 1 and 65535, got 65536"},
+		{name: "it should reject an empty host", key: "SMTP_HOST", value: "", wantErr: "SMTP_HOST must not be empty"},
+		{name: "it should reject an empty from address", key: "SMTP_FROM", value: "", wantErr: "SMTP_FROM must not be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var config ApplicationConfig
+			secrets := newTestSecrets()
+			secrets[tt.key] = tt.value
+
+			// Act
+			err := config.Load(context.Background(), env, NewMapSecretsProvider(secrets))
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("it should return the secret error when a secret is missing", func(t *testing.T) {
+		// Arrange
+		var config ApplicationConfig
+		secrets := newTestSecrets()
+		delete(secrets, "SMTP_HOST")
+
+		// Act
+		err := config.Load(context.Background(), env, NewMapSecretsProvider(secrets))
+
+		// Assert
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+	})
+}
+
+func TestApplicationConfig_LoadFromJSON(t *testing.T) {
+	t.Run("it should load a well-formed JSON secret", func(t *testing.T) {
+		// Arrange
+		var config ApplicationConfig
+		secrets := NewMapSecretsProvider(map[string]string{"APP_CONFIG": `{
+			"transactions_dynamodb": {"table_name": "transactions"},
+			"email_smtp": {
+				"host": "smtp.example.com",
+				"port": 587,
+				"username": "user",
+				"password": "secret",
+				"from": "noreply@example.com"
+			}
+		}`})
+
+		// Act
+		err := config.LoadFromJSON(context.Background(), secrets, "APP_CONFIG")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "transactions", config.TransactionsDynamoDB.TableName)
+		assert.Equal(t, SMTPConfig{
+			Host:     "smtp.example.com",
+			Port:     587,
+			Username: "user",
+			Password: "secret",
+			From:     "noreply@example.com",
+		}, config.EmailSMTP)
+	})
+
+	tests := []struct {
+		name     string
+		document string
+		wantErr  string
+	}{
+		{name: "it should reject a malformed JSON secret", document: `{"email_smtp": {"host": `, wantErr: "secret APP_CONFIG is not a valid JSON document"},
+		{name: "it should reject a port of the wrong type", document: `{"email_smtp": {"port": "587"}}`, wantErr: "secret APP_CONFIG is not a valid JSON document"},
+		{name: "it should reject a missing table name", document: `{"email_smtp": {"host": "smtp.example.com", "port": 587, "from": "noreply@example.com"}}`, wantErr: "DYNAMODB_TABLE_NAME must not be empty"},
+		{name: "it should reject an out-of-range port", document: `{"transactions_dynamodb": {"table_name": "transactions"}, "email_smtp": {"host": "smtp.example.com", "port": 70000, "from": "noreply@example.com"}}`, wantErr: "SMTP_PORT must be between 1 and 65535, got 70000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			config := ApplicationConfig{TransactionsDynamoDB: TransactionsDynamoDBConfig{TableName: "previous"}}
+			secrets := NewMapSecretsProvider(map[string]string{"APP_CONFIG": tt.document})
+
+			// Act
+			err := config.LoadFromJSON(context.Background(), secrets, "APP_CONFIG")
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+			assert.ErrorContains(t, err, tt.wantErr)
+			assert.Equal(t, "previous", config.TransactionsDynamoDB.TableName)
+		})
+	}
+
+	t.Run("it should return the secret error when the secret is missing", func(t *testing.T) {
+		// Arrange
+		var config ApplicationConfig
+
+		// Act
+		err := config.LoadFromJSON(context.Background(), NewMapSecretsProvider(nil), "APP_CONFIG")
+
+		// Assert
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+	})
+}
+
+func TestApplicationConfig_LoadWithDefaults(t *testing.T) {
+	env := fakeEnvProvider{"DYNAMODB_TABLE_NAME": "transactions"}
+
+	t.Run("it should use the default port when the secret is absent", func(t *testing.T) {
+		// Arrange
+		var config ApplicationConfig
+		secrets := newTestSecrets()
+		delete(secrets, "SMTP_PORT")
+
+		// Act
+		err := config.LoadWithDefaults(context.Background(), env, NewMapSecretsProvider(secrets), Defaults{SMTPPort: 2525})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 2525, config.EmailSMTP.Port)
+	})
+
+	t.Run("it should prefer the secret over the default port", func(t *testing.T) {
+		// Arrange
+		var config ApplicationConfig
+
+		// Act
+		err := config.LoadWithDefaults(context.Background(), env, NewMapSecretsProvider(newTestSecrets()), Defaults{SMTPPort: 2525})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 587, config.EmailSMTP.Port)
+	})
+
+	t.Run("it should not use the default port when the secret is malformed", func(t *testing.T) {
+		// Arrange
+		var config ApplicationConfig
+		secrets := newTestSecrets()
+		secrets["SMTP_PORT"] = "smtp"
+
+		// Act
+		err := config.LoadWithDefaults(context.Background(), env, NewMapSecretsProvider(secrets), Defaults{SMTPPort: 2525})
+
+		// Assert
+		assert.ErrorIs(t, err, ErrSecretTypeMismatch)
+	})
+
+	t.Run("it should not use the default port when Secrets Manager fails", func(t *testing.T) {
+		// Arrange
+		var config ApplicationConfig
+		clientErr := errors.New("ThrottlingException")
+		client := newFakeSecretsManagerClient(newTestSecrets())
+		client.errs = map[string]error{"SMTP_PORT": clientErr}
+		secrets := newAWSSecretsProvider(client, DefaultAWSSecretsProviderConfig())
+
+		// Act
+		err := config.LoadWithDefaults(context.Background(), env, secrets, Defaults{SMTPPort: 2525})
+
+		// Assert
+		assert.ErrorIs(t, err, clientErr)
+		assert.Zero(t, config.EmailSMTP.Port)
+	})
+
+	t.Run("it should still require the table name", func(t *testing.T) {
+		// Arrange
+		var config ApplicationConfig
+
+		// Act
+		err := config.LoadWithDefaults(context.Background(), fakeEnvProvider{}, NewMapSecretsProvider(newTestSecrets()), DefaultDefaults())
+
+		// Assert
+		assert.ErrorIs(t, err, ErrEnvVarNotSet)
+	})
+
+	t.Run("it should default to port 587 on Load", func(t *testing.T) {
+		// Arrange
+		var config ApplicationConfig
+		secrets := newTestSecrets()
+		delete(secrets, "SMTP_PORT")
+
+		// Act
+		err := config.Load(context.Background(), env, NewMapSecretsProvider(secrets))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 587, config.EmailSMTP.Port)
+	})
+
+	t.Run("it should use the default port when the JSON document omits it", func(t *testing.T) {
+		// Arrange
+		var config ApplicationConfig
+		secrets := NewMapSecretsProvider(map[string]string{"APP_CONFIG": `{
+			"transactions_dynamodb": {"table_name": "transactions"},
+			"email_smtp": {"host": "smtp.example.com", "from": "noreply@example.com"}
+		}`})
+
+		// Act
+		err := config.LoadFromJSON(context.Background(), secrets, "APP_CONFIG")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 587, config.EmailSMTP.Port)
+	})
+}
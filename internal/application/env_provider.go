@@ -15,14 +15,35 @@ type EnvProvider interface {
 	GetEnv(key string) (string, error)
 }
 
-// DefaultEnvProvider is the default implementation using os.Getenv.
+// DefaultEnvProvider is the default implementation using os.LookupEnv.
 type DefaultEnvProvider struct{}
 
 // GetEnv returns the value of an env var or an error if not set.
+// A variable set to the empty string is returned as is, without an error.
 func (p *DefaultEnvProvider) GetEnv(key string) (string, error) {
-	value := os.Getenv(key)
-	if value == "" {
+	value, ok := os.LookupEnv(key)
+	if !ok {
 		return "", ErrEnvVarNotSet
 	}
 	return value, nil
 }
+
+// PrefixEnvProvider wraps another EnvProvider, prepending a prefix to every
+// key before the lookup. It's useful to run several instances side by side
+// with namespaced variables, e.g. STORI_A_DYNAMODB_TABLE_NAME.
+type PrefixEnvProvider struct {
+	prefix  string
+	wrapped EnvProvider
+}
+
+// NewPrefixEnvProvider constructs a PrefixEnvProvider looking up the keys
+// prefixed with prefix (as is, without adding a separator) in wrapped.
+func NewPrefixEnvProvider(prefix string, wrapped EnvProvider) *PrefixEnvProvider {
+	return &PrefixEnvProvider{prefix: prefix, wrapped: wrapped}
+}
+
+// GetEnv returns the value of the prefixed env var, or the wrapped
+// provider's error.
+func (p *PrefixEnvProvider) GetEnv(key string) (string, error) {
+	return p.wrapped.GetEnv(p.prefix + key)
+}
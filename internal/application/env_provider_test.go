@@ -0,0 +1,94 @@
+package application
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultEnvProvider_GetEnv(t *testing.T) {
+	const key = "STORI_CHALLENGE_TEST_ENV_VAR"
+
+	t.Run("it should return a non-empty variable", func(t *testing.T) {
+		// Arrange
+		t.Setenv(key, "value")
+		provider := &DefaultEnvProvider{}
+
+		// Act
+		value, err := provider.GetEnv(key)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+
+	t.Run("it should return an empty variable without an error", func(t *testing.T) {
+		// Arrange
+		t.Setenv(key, "")
+		provider := &DefaultEnvProvider{}
+
+		// Act
+		value, err := provider.GetEnv(key)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, value)
+	})
+
+	t.Run("it should fail on an unset variable", func(t *testing.T) {
+		// Arrange
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+		provider := &DefaultEnvProvider{}
+
+		// Act
+		value, err := provider.GetEnv(key)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrEnvVarNotSet)
+		assert.Empty(t, value)
+	})
+}
+
+func TestPrefixEnvProvider_GetEnv(t *testing.T) {
+	t.Run("it should prepend the prefix to the key", func(t *testing.T) {
+		// Arrange
+		provider := NewPrefixEnvProvider("STORI_A_", fakeEnvProvider{
+			"DYNAMODB_TABLE_NAME":         "unprefixed",
+			"STORI_A_DYNAMODB_TABLE_NAME": "transactions-a",
+		})
+
+		// Act
+		value, err := provider.GetEnv("DYNAMODB_TABLE_NAME")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "transactions-a", value)
+	})
+
+	t.Run("it should pass the wrapped provider's errors through", func(t *testing.T) {
+		// Arrange
+		provider := NewPrefixEnvProvider("STORI_A_", fakeEnvProvider{"DYNAMODB_TABLE_NAME": "unprefixed"})
+
+		// Act
+		value, err := provider.GetEnv("DYNAMODB_TABLE_NAME")
+
+		// Assert
+		assert.ErrorIs(t, err, ErrEnvVarNotSet)
+		assert.Empty(t, value)
+	})
+
+	t.Run("it should compose with DefaultEnvProvider", func(t *testing.T) {
+		// Arrange
+		t.Setenv("STORI_B_DYNAMODB_TABLE_NAME", "transactions-b")
+		provider := NewPrefixEnvProvider("STORI_B_", &DefaultEnvProvider{})
+
+		// Act
+		value, err := provider.GetEnv("DYNAMODB_TABLE_NAME")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "transactions-b", value)
+	})
+}
@@ -0,0 +1,54 @@
+package application
+
+import (
+	"context"
+	"maps"
+	"strconv"
+)
+
+// MapSecretsProvider is a SecretsProvider backed by an in-memory map, meant
+// for tests and local runs. It mirrors AWSSecretsProvider's error semantics.
+type MapSecretsProvider struct {
+	secrets map[string]string
+}
+
+// NewMapSecretsProvider constructs a MapSecretsProvider holding a copy of
+// the given secrets.
+func NewMapSecretsProvider(secrets map[string]string) *MapSecretsProvider {
+	return &MapSecretsProvider{secrets: maps.Clone(secrets)}
+}
+
+// GetString retrieves a secret as string.
+func (p *MapSecretsProvider) GetString(_ context.Context, key string) (string, error) {
+	value, ok := p.secrets[key]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// GetInt retrieves a secret as int.
+func (p *MapSecretsProvider) GetInt(ctx context.Context, key string) (int, error) {
+	val, err := p.GetString(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	intVal, convErr := strconv.Atoi(val)
+	if convErr != nil {
+		return 0, ErrSecretTypeMismatch
+	}
+	return intVal, nil
+}
+
+// GetFloat retrieves a secret as float64.
+func (p *MapSecretsProvider) GetFloat(ctx context.Context, key string) (float64, error) {
+	val, err := p.GetString(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	floatVal, convErr := strconv.ParseFloat(val, 64)
+	if convErr != nil {
+		return 0, ErrSecretTypeMismatch
+	}
+	return floatVal, nil
+}
@@ -0,0 +1,105 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapSecretsProvider(t *testing.T) {
+	provider := NewMapSecretsProvider(map[string]string{
+		"SMTP_HOST": "smtp.example.com",
+		"SMTP_PORT": "587",
+		"EMPTY":     "",
+		"RATE":      "17.25",
+	})
+
+	t.Run("GetString", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			key     string
+			want    string
+			wantErr error
+		}{
+			{name: "it should return a present secret", key: "SMTP_HOST", want: "smtp.example.com"},
+			{name: "it should return an empty secret", key: "EMPTY", want: ""},
+			{name: "it should fail on a missing secret", key: "SMTP_USERNAME", wantErr: ErrSecretNotFound},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Act
+				got, err := provider.GetString(context.Background(), tt.key)
+
+				// Assert
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.want, got)
+			})
+		}
+	})
+
+	t.Run("GetInt", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			key     string
+			want    int
+			wantErr error
+		}{
+			{name: "it should return a present secret", key: "SMTP_PORT", want: 587},
+			{name: "it should fail on a missing secret", key: "SMTP_USERNAME", wantErr: ErrSecretNotFound},
+			{name: "it should fail on a non-integer secret", key: "SMTP_HOST", wantErr: ErrSecretTypeMismatch},
+			{name: "it should fail on an empty secret", key: "EMPTY", wantErr: ErrSecretTypeMismatch},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Act
+				got, err := provider.GetInt(context.Background(), tt.key)
+
+				// Assert
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.want, got)
+			})
+		}
+	})
+
+	t.Run("GetFloat", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			key     string
+			want    float64
+			wantErr error
+		}{
+			{name: "it should return a decimal secret", key: "RATE", want: 17.25},
+			{name: "it should return an integer secret", key: "SMTP_PORT", want: 587},
+			{name: "it should fail on a missing secret", key: "SMTP_USERNAME", wantErr: ErrSecretNotFound},
+			{name: "it should fail on a non-numeric secret", key: "SMTP_HOST", wantErr: ErrSecretTypeMismatch},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Act
+				got, err := provider.GetFloat(context.Background(), tt.key)
+
+				// Assert
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.want, got)
+			})
+		}
+	})
+
+	t.Run("it should not be affected by changes to the source map", func(t *testing.T) {
+		// Arrange
+		secrets := map[string]string{"SMTP_HOST": "smtp.example.com"}
+		provider := NewMapSecretsProvider(secrets)
+
+		// Act
+		secrets["SMTP_HOST"] = "changed"
+		got, err := provider.GetString(context.Background(), "SMTP_HOST")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "smtp.example.com", got)
+	})
+}
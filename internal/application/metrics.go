@@ -0,0 +1,46 @@
+package application
+
+import "time"
+
+// Metric names recorded by DefaultProcessor.
+const (
+	// MetricTransactionsProcessed counts the transactions of the processed files.
+	MetricTransactionsProcessed = "transactions_processed"
+
+	// MetricBytesRead counts the bytes read from the summary files.
+	MetricBytesRead = "bytes_read"
+
+	// MetricLoadDuration times obtaining a summary file from storage.
+	MetricLoadDuration = "load_duration"
+
+	// MetricParseDuration times parsing the transactions of a summary file.
+	MetricParseDuration = "parse_duration"
+
+	// MetricPersistDuration times persisting the transactions.
+	MetricPersistDuration = "persist_duration"
+
+	// MetricSummarizeDuration times calculating the summary.
+	MetricSummarizeDuration = "summarize_duration"
+
+	// MetricMailDuration times sending the summary email.
+	MetricMailDuration = "mail_duration"
+)
+
+// Metrics defines an interface for recording processing metrics, such as
+// CloudWatch metrics.
+type Metrics interface {
+	// Count adds n to the counter with the given name.
+	Count(name string, n int)
+
+	// Duration records a duration for the timer with the given name.
+	Duration(name string, d time.Duration)
+}
+
+// NoopMetrics is a Metrics implementation that discards every metric.
+type NoopMetrics struct{}
+
+// Count discards the counter.
+func (NoopMetrics) Count(string, int) {}
+
+// Duration discards the duration.
+func (NoopMetrics) Duration(string, time.Duration) {}
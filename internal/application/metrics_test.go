@@ -0,0 +1,100 @@
+package application
+
+import (
+	"context"
+	"stori-challenge/internal/transactions"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spyMetrics records the counters it receives, and the names of the timers.
+type spyMetrics struct {
+	counts    map[string]int
+	durations []string
+}
+
+func newSpyMetrics() *spyMetrics {
+	return &spyMetrics{counts: make(map[string]int)}
+}
+
+func (m *spyMetrics) Count(name string, n int) {
+	m.counts[name] += n
+}
+
+func (m *spyMetrics) Duration(name string, _ time.Duration) {
+	m.durations = append(m.durations, name)
+}
+
+func TestDefaultProcessor_ProcessFile_Metrics(t *testing.T) {
+	t.Run("it should record the metrics of every stage", func(t *testing.T) {
+		// Arrange
+		loader := &fakeTransactionLoader{transactions: []transactions.Transaction{{ID: 0, Amount: 60_50}, {ID: 1, Amount: -10_30}}}
+		metrics := newSpyMetrics()
+		processor, _ := newTestProcessor(loader, &fakeMailer{})
+		processor.config.Metrics = metrics
+
+		// Act
+		_, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			MetricLoadDuration,
+			MetricParseDuration,
+			MetricPersistDuration,
+			MetricSummarizeDuration,
+			MetricMailDuration,
+		}, metrics.durations)
+		assert.Equal(t, map[string]int{
+			MetricBytesRead:             len("Id,Date,Transaction\n0,7/15,+60.5\n"),
+			MetricTransactionsProcessed: 2,
+		}, metrics.counts)
+	})
+
+	t.Run("it should only record the stages that ran in dry-run mode", func(t *testing.T) {
+		// Arrange
+		loader := &fakeTransactionLoader{transactions: []transactions.Transaction{{ID: 0, Amount: 60_50}}}
+		metrics := newSpyMetrics()
+		processor, _ := newTestProcessor(loader, &fakeMailer{})
+		processor.config.Metrics = metrics
+		processor.config.DryRun = true
+
+		// Act
+		_, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{MetricLoadDuration, MetricParseDuration, MetricSummarizeDuration}, metrics.durations)
+		assert.Equal(t, 1, metrics.counts[MetricTransactionsProcessed])
+	})
+
+	t.Run("it should not count the transactions of a failed file", func(t *testing.T) {
+		// Arrange
+		loader := &fakeTransactionLoader{transactions: []transactions.Transaction{{ID: 0, Amount: 60_50}}}
+		metrics := newSpyMetrics()
+		processor, _ := newTestProcessor(loader, &fakeMailer{})
+		processor.config.Metrics = metrics
+		processor.repository = &failingTransactionsRepository{err: assert.AnError}
+
+		// Act
+		_, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		assert.ErrorIs(t, err, ErrPersist)
+		assert.Equal(t, []string{MetricLoadDuration, MetricParseDuration, MetricPersistDuration}, metrics.durations)
+		assert.NotContains(t, metrics.counts, MetricTransactionsProcessed)
+	})
+}
+
+func TestNewProcessorWithConfig(t *testing.T) {
+	t.Run("it should fall back to no-op metrics", func(t *testing.T) {
+		// Act
+		processor := NewProcessorWithConfig(nil, nil, nil, nil, nil, nil, ProcessorConfig{})
+
+		// Assert
+		assert.Equal(t, NoopMetrics{}, processor.config.Metrics)
+	})
+}
@@ -3,9 +3,13 @@ package application
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
 )
 
 var (
@@ -20,29 +24,108 @@ var (
 type SecretsProvider interface {
 	GetString(ctx context.Context, key string) (string, error)
 	GetInt(ctx context.Context, key string) (int, error)
+	GetFloat(ctx context.Context, key string) (float64, error)
+}
+
+// secretsManagerClient abstracts the Secrets Manager operations used by the
+// provider so the client can be replaced in tests.
+type secretsManagerClient interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// AWSSecretsProviderConfig holds the configuration of an AWSSecretsProvider.
+type AWSSecretsProviderConfig struct {
+	// CacheTTL is how long a fetched secret is kept in memory before being
+	// fetched again (default: 0, i.e. secrets are cached for the provider's lifetime)
+	CacheTTL time.Duration
+}
+
+// DefaultAWSSecretsProviderConfig returns the default configuration.
+func DefaultAWSSecretsProviderConfig() AWSSecretsProviderConfig {
+	return AWSSecretsProviderConfig{}
+}
+
+// cachedSecret is a secret value kept in memory by AWSSecretsProvider.
+type cachedSecret struct {
+	value     string
+	fetchedAt time.Time
 }
 
 // AWSSecretsProvider uses AWS Secrets Manager.
+// Fetched secrets are cached in memory, so repeated lookups of the same key
+// don't hit Secrets Manager again. It's safe for concurrent use.
 type AWSSecretsProvider struct {
-	client *secretsmanager.Client
+	client secretsManagerClient
+	config AWSSecretsProviderConfig
+
+	// now returns the current time, and is replaced in tests.
+	now func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedSecret
 }
 
 // NewAWSSecretsProvider constructs an AWSSecretsProvider.
 func NewAWSSecretsProvider(client *secretsmanager.Client) *AWSSecretsProvider {
-	return &AWSSecretsProvider{client: client}
+	return NewAWSSecretsProviderWithConfig(client, DefaultAWSSecretsProviderConfig())
+}
+
+// NewAWSSecretsProviderWithConfig constructs an AWSSecretsProvider with custom configuration.
+// Negative values fall back to the defaults.
+func NewAWSSecretsProviderWithConfig(client *secretsmanager.Client, config AWSSecretsProviderConfig) *AWSSecretsProvider {
+	return newAWSSecretsProvider(client, config)
+}
+
+// newAWSSecretsProvider constructs a provider on top of any secretsManagerClient.
+func newAWSSecretsProvider(client secretsManagerClient, config AWSSecretsProviderConfig) *AWSSecretsProvider {
+	if config.CacheTTL < 0 {
+		config.CacheTTL = DefaultAWSSecretsProviderConfig().CacheTTL
+	}
+
+	return &AWSSecretsProvider{
+		client: client,
+		config: config,
+		now:    time.Now,
+		cache:  make(map[string]cachedSecret),
+	}
 }
 
 // GetString retrieves a secret as string.
+// Returns ErrSecretNotFound only when the secret doesn't exist (or has no
+// string value); any other Secrets Manager failure is returned wrapped.
+// The lookup holds the cache lock, so concurrent lookups of the same key
+// result in a single Secrets Manager call.
 func (p *AWSSecretsProvider) GetString(ctx context.Context, key string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if cached, ok := p.cache[key]; ok && !p.expired(cached) {
+		return cached.value, nil
+	}
+
 	secret, err := p.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
 		SecretId: &key,
 	})
-	if err != nil || secret.SecretString == nil {
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
 		return "", ErrSecretNotFound
 	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %s: %w", key, err)
+	}
+	if secret.SecretString == nil {
+		return "", ErrSecretNotFound
+	}
+
+	p.cache[key] = cachedSecret{value: *secret.SecretString, fetchedAt: p.now()}
 	return *secret.SecretString, nil
 }
 
+// expired reports whether a cached secret is older than the cache TTL.
+func (p *AWSSecretsProvider) expired(cached cachedSecret) bool {
+	return p.config.CacheTTL > 0 && p.now().Sub(cached.fetchedAt) >= p.config.CacheTTL
+}
+
 // GetInt retrieves a secret as int.
 func (p *AWSSecretsProvider) GetInt(ctx context.Context, key string) (int, error) {
 	val, err := p.GetString(ctx, key)
@@ -55,3 +138,16 @@ func (p *AWSSecretsProvider) GetInt(ctx context.Context, key string) (int, error
 	}
 	return intVal, nil
 }
+
+// GetFloat retrieves a secret as float64.
+func (p *AWSSecretsProvider) GetFloat(ctx context.Context, key string) (float64, error) {
+	val, err := p.GetString(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	floatVal, convErr := strconv.ParseFloat(val, 64)
+	if convErr != nil {
+		return 0, ErrSecretTypeMismatch
+	}
+	return floatVal, nil
+}
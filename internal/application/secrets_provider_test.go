@@ -0,0 +1,209 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSecretsManagerClient is an in-memory secretsManagerClient that counts
+// the calls made for each secret. Lookups of the keys in errs fail with the
+// given error.
+type fakeSecretsManagerClient struct {
+	mu      sync.Mutex
+	secrets map[string]string
+	errs    map[string]error
+	calls   map[string]int
+}
+
+func newFakeSecretsManagerClient(secrets map[string]string) *fakeSecretsManagerClient {
+	return &fakeSecretsManagerClient{secrets: secrets, calls: make(map[string]int)}
+}
+
+func (c *fakeSecretsManagerClient) GetSecretValue(_ context.Context, params *secretsmanager.GetSecretValueInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := aws.ToString(params.SecretId)
+	c.calls[key]++
+	if err, ok := c.errs[key]; ok {
+		return nil, err
+	}
+	value, ok := c.secrets[key]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("secret not found")}
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(value)}, nil
+}
+
+func (c *fakeSecretsManagerClient) callsFor(key string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[key]
+}
+
+func TestAWSSecretsProvider_Cache(t *testing.T) {
+	secrets := map[string]string{"SMTP_HOST": "smtp.example.com", "SMTP_PORT": "587"}
+
+	t.Run("it should call the client only once per key", func(t *testing.T) {
+		// Arrange
+		client := newFakeSecretsManagerClient(secrets)
+		provider := newAWSSecretsProvider(client, DefaultAWSSecretsProviderConfig())
+
+		// Act
+		for range 3 {
+			host, err := provider.GetString(context.Background(), "SMTP_HOST")
+			require.NoError(t, err)
+			assert.Equal(t, "smtp.example.com", host)
+
+			port, err := provider.GetInt(context.Background(), "SMTP_PORT")
+			require.NoError(t, err)
+			assert.Equal(t, 587, port)
+		}
+
+		// Assert
+		assert.Equal(t, 1, client.callsFor("SMTP_HOST"))
+		assert.Equal(t, 1, client.callsFor("SMTP_PORT"))
+	})
+
+	t.Run("it should call the client only once per key under concurrent lookups", func(t *testing.T) {
+		// Arrange
+		client := newFakeSecretsManagerClient(secrets)
+		provider := newAWSSecretsProvider(client, DefaultAWSSecretsProviderConfig())
+
+		// Act
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = provider.GetString(context.Background(), "SMTP_HOST")
+			}()
+		}
+		wg.Wait()
+
+		// Assert
+		assert.Equal(t, 1, client.callsFor("SMTP_HOST"))
+	})
+
+	t.Run("it should not cache missing secrets", func(t *testing.T) {
+		// Arrange
+		client := newFakeSecretsManagerClient(secrets)
+		provider := newAWSSecretsProvider(client, DefaultAWSSecretsProviderConfig())
+
+		// Act
+		_, firstErr := provider.GetString(context.Background(), "SMTP_FROM")
+		_, secondErr := provider.GetString(context.Background(), "SMTP_FROM")
+
+		// Assert
+		assert.ErrorIs(t, firstErr, ErrSecretNotFound)
+		assert.ErrorIs(t, secondErr, ErrSecretNotFound)
+		assert.Equal(t, 2, client.callsFor("SMTP_FROM"))
+	})
+
+	t.Run("it should fetch the secret again once the TTL has passed", func(t *testing.T) {
+		// Arrange
+		client := newFakeSecretsManagerClient(secrets)
+		provider := newAWSSecretsProvider(client, AWSSecretsProviderConfig{CacheTTL: time.Minute})
+		now := time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC)
+		provider.now = func() time.Time { return now }
+
+		// Act
+		_, _ = provider.GetString(context.Background(), "SMTP_HOST")
+		now = now.Add(30 * time.Second)
+		_, _ = provider.GetString(context.Background(), "SMTP_HOST")
+		callsWithinTTL := client.callsFor("SMTP_HOST")
+		now = now.Add(30 * time.Second)
+		_, _ = provider.GetString(context.Background(), "SMTP_HOST")
+
+		// Assert
+		assert.Equal(t, 1, callsWithinTTL)
+		assert.Equal(t, 2, client.callsFor("SMTP_HOST"))
+	})
+
+	t.Run("it should fall back to the default TTL when negative", func(t *testing.T) {
+		// Act
+		provider := newAWSSecretsProvider(newFakeSecretsManagerClient(secrets), AWSSecretsProviderConfig{CacheTTL: -time.Second})
+
+		// Assert
+		assert.Equal(t, DefaultAWSSecretsProviderConfig(), provider.config)
+	})
+}
+
+func TestAWSSecretsProvider_GetFloat(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    float64
+		wantErr error
+	}{
+		{name: "it should parse a decimal value", value: "17.25", want: 17.25},
+		{name: "it should parse a negative value", value: "-0.5", want: -0.5},
+		{name: "it should parse an exponent", value: "1e3", want: 1000},
+		{name: "it should fail on a non-numeric value", value: "seventeen", wantErr: ErrSecretTypeMismatch},
+		{name: "it should fail on a decimal comma", value: "17,25", wantErr: ErrSecretTypeMismatch},
+		{name: "it should fail on an empty value", value: "", wantErr: ErrSecretTypeMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			client := newFakeSecretsManagerClient(map[string]string{"RATE": tt.value})
+			provider := newAWSSecretsProvider(client, DefaultAWSSecretsProviderConfig())
+
+			// Act
+			got, err := provider.GetFloat(context.Background(), "RATE")
+
+			// Assert
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("it should fail on a missing secret", func(t *testing.T) {
+		// Arrange
+		provider := newAWSSecretsProvider(newFakeSecretsManagerClient(nil), DefaultAWSSecretsProviderConfig())
+
+		// Act
+		_, err := provider.GetFloat(context.Background(), "RATE")
+
+		// Assert
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+	})
+}
+
+func TestAWSSecretsProvider_GetString(t *testing.T) {
+	t.Run("it should return ErrSecretNotFound when the secret doesn't exist", func(t *testing.T) {
+		// Arrange
+		provider := newAWSSecretsProvider(newFakeSecretsManagerClient(nil), DefaultAWSSecretsProviderConfig())
+
+		// Act
+		_, err := provider.GetString(context.Background(), "SMTP_HOST")
+
+		// Assert
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+	})
+
+	t.Run("it should return the client error as is when the lookup fails", func(t *testing.T) {
+		// Arrange
+		clientErr := errors.New("AccessDeniedException")
+		client := newFakeSecretsManagerClient(nil)
+		client.errs = map[string]error{"SMTP_HOST": clientErr}
+		provider := newAWSSecretsProvider(client, DefaultAWSSecretsProviderConfig())
+
+		// Act
+		_, err := provider.GetString(context.Background(), "SMTP_HOST")
+
+		// Assert
+		assert.ErrorIs(t, err, clientErr)
+		assert.NotErrorIs(t, err, ErrSecretNotFound)
+	})
+}
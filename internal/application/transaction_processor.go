@@ -1,17 +1,125 @@
 package application
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"path"
+	"sort"
 	"stori-challenge/internal/summaries"
 	"stori-challenge/internal/summaries/mailing"
 	"stori-challenge/internal/transactions"
 	"stori-challenge/pkg/blend"
+	"strings"
+	"time"
 )
 
+var (
+	// ErrFileLoad is returned when the summary file can't be loaded from storage.
+	ErrFileLoad = errors.New("failed to load file")
+
+	// ErrParse is returned when the transactions of the summary file can't be parsed.
+	ErrParse = errors.New("failed to parse transactions")
+
+	// ErrTransform is returned when the transactions can't be transformed.
+	ErrTransform = errors.New("failed to transform transactions")
+
+	// ErrPersist is returned when the transactions can't be persisted, along
+	// with a *PersistError reporting those committed before the failure.
+	ErrPersist = errors.New("failed to persist transactions")
+
+	// ErrMail is returned when the summary email can't be sent.
+	ErrMail = errors.New("failed to send email")
+
+	// ErrAccountMismatch is returned when files processed together belong to different accounts.
+	ErrAccountMismatch = errors.New("files belong to different accounts")
+
+	// ErrNoFiles is returned when there are no files to process.
+	ErrNoFiles = errors.New("no files to process")
+
+	// ErrInvalidAccountID is returned when a summary file has an empty (or
+	// whitespace-only) account ID, so its transactions can't be attributed.
+	ErrInvalidAccountID = errors.New("invalid account ID")
+
+	// ErrSelfTest is returned when a dependency fails the self-test.
+	ErrSelfTest = errors.New("self-test failed")
+)
+
+// PersistError is the cause of a persistence failure (see ErrPersist),
+// reporting the transactions committed before it.
+type PersistError struct {
+	// PersistedCount is the number of leading transactions committed before
+	// the failure (0 when the repository doesn't report it). Reprocessing the
+	// file overwrites them, as their keys are derived from the transactions.
+	PersistedCount int
+
+	// Err is the reason why persisting failed.
+	Err error
+}
+
+// Error returns the reason why persisting failed.
+func (e *PersistError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying reason, so it can be inspected with errors.Is/As.
+func (e *PersistError) Unwrap() error {
+	return e.Err
+}
+
 // TransactionProcessor defines the pipeline contract.
 type TransactionProcessor interface {
 	ProcessFile(ctx context.Context, bucket, key string) (*ProcessingResult, error)
+	ProcessFiles(ctx context.Context, refs []FileRef) (*ProcessingResult, error)
+	SelfTest(ctx context.Context) error
+}
+
+// Transformer transforms the loaded transactions before they are persisted
+// and summarized, e.g. to normalize amounts in cents to dollars.
+type Transformer func(ctx context.Context, txns []transactions.Transaction) ([]transactions.Transaction, error)
+
+// ProcessorConfig holds the configuration of a DefaultProcessor.
+type ProcessorConfig struct {
+	// LenientMail makes email failures non-fatal: once the transactions are
+	// persisted, a failure to send the summary email is logged and reported in
+	// ProcessingResult.EmailError instead of failing the file (default: false,
+	// i.e. email failures fail the file)
+	LenientMail bool
+
+	// DryRun validates the files (parse and summarize) without persisting the
+	// transactions or sending the summary email (default: false)
+	DryRun bool
+
+	// Metrics records the processing metrics, such as the duration of each
+	// stage (default: NoopMetrics, i.e. metrics are discarded)
+	Metrics Metrics
+
+	// Transformer transforms the transactions after loading them and before
+	// persisting them (default: nil, i.e. transactions are left untouched)
+	Transformer Transformer
+
+	// SkipEmptyEmail skips the summary email when there are no transactions,
+	// e.g. for a statement file with only a header (default: false, i.e. a
+	// "no activity" summary email is sent)
+	SkipEmptyEmail bool
+
+	// AttachStatement attaches the original statement file(s) to the summary
+	// email, as read from storage (default: false)
+	AttachStatement bool
+
+	// SortBeforeSave persists the transactions sorted by date and then by ID,
+	// for consumers reading them in insertion order (default: false, i.e.
+	// they're persisted in file order)
+	SortBeforeSave bool
+}
+
+// DefaultProcessorConfig returns the default configuration.
+func DefaultProcessorConfig() ProcessorConfig {
+	return ProcessorConfig{
+		Metrics: NoopMetrics{},
+	}
 }
 
 // DefaultProcessor implements TransactionProcessor.
@@ -22,6 +130,7 @@ type DefaultProcessor struct {
 	summarizer summaries.Summarizer
 	mailer     mailing.Mailer
 	logger     blend.Logger
+	config     ProcessorConfig
 }
 
 // NewProcessor creates a new DefaultProcessor instance.
@@ -33,6 +142,24 @@ func NewProcessor(
 	summarizer summaries.Summarizer,
 	mailer mailing.Mailer,
 ) *DefaultProcessor {
+	return NewProcessorWithConfig(logger, storage, loader, repository, summarizer, mailer, DefaultProcessorConfig())
+}
+
+// NewProcessorWithConfig creates a new DefaultProcessor instance with custom configuration.
+// Nil values fall back to the defaults.
+func NewProcessorWithConfig(
+	logger blend.Logger,
+	storage summaries.SummaryFilesStorage,
+	loader transactions.TransactionLoader,
+	repository transactions.TransactionsRepository,
+	summarizer summaries.Summarizer,
+	mailer mailing.Mailer,
+	config ProcessorConfig,
+) *DefaultProcessor {
+	if config.Metrics == nil {
+		config.Metrics = DefaultProcessorConfig().Metrics
+	}
+
 	return &DefaultProcessor{
 		logger:     logger,
 		storage:    storage,
@@ -40,76 +167,450 @@ func NewProcessor(
 		repository: repository,
 		summarizer: summarizer,
 		mailer:     mailer,
+		config:     config,
 	}
 }
 
-// ProcessingResult contains the outcome of one file.
+// statsSaver is implemented by repositories that report save statistics.
+type statsSaver interface {
+	SaveWithStats(ctx context.Context, transactions []transactions.Transaction) (transactions.SaveResult, error)
+}
+
+// persistTransactions saves the transactions, returning how many of them were
+// committed and logging the save statistics when the repository reports them.
+// On failure, the error is a *PersistError reporting the recovery boundary.
+func (tp *DefaultProcessor) persistTransactions(ctx context.Context, txns []transactions.Transaction) (int, error) {
+	saver, ok := tp.repository.(statsSaver)
+	if !ok {
+		if err := tp.repository.Save(ctx, txns); err != nil {
+			return 0, &PersistError{Err: err}
+		}
+		return len(txns), nil
+	}
+
+	result, err := saver.SaveWithStats(ctx, txns)
+	tp.logger.Info(ctx, "Save statistics: %d written, %d retried, %d failed, %d skipped", result.Written, result.Retried, result.Failed, result.Skipped)
+	if err != nil {
+		tp.logger.Warn(ctx, "Persisted the first %d of %d transactions before failing; reprocessing the file overwrites them", result.Committed, len(txns))
+		return result.Committed, &PersistError{PersistedCount: result.Committed, Err: err}
+	}
+	return result.Committed, nil
+}
+
+// pinger is implemented by dependencies that can check their connectivity.
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// SelfTest checks the connectivity of the dependencies (storage, repository
+// and mailer) without processing any file, e.g. before processing real
+// traffic. Dependencies that can't check their connectivity (e.g. in-memory
+// ones) are skipped. Every dependency is checked even when one fails, and the
+// failures are returned together, wrapped with ErrSelfTest.
+func (tp *DefaultProcessor) SelfTest(ctx context.Context) error {
+	dependencies := []struct {
+		name       string
+		dependency any
+	}{
+		{name: "storage", dependency: tp.storage},
+		{name: "repository", dependency: tp.repository},
+		{name: "mailer", dependency: tp.mailer},
+	}
+
+	var errs []error
+	for _, dep := range dependencies {
+		checker, ok := dep.dependency.(pinger)
+		if !ok {
+			tp.logger.Debug(ctx, "Self-test: skipping the %s, which can't check its connectivity", dep.name)
+			continue
+		}
+
+		if err := checker.Ping(ctx); err != nil {
+			tp.logger.Error(ctx, "Self-test: %s failed: %v", dep.name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", dep.name, err))
+			continue
+		}
+		tp.logger.Info(ctx, "Self-test: %s is reachable", dep.name)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrSelfTest, errors.Join(errs...))
+	}
+	return nil
+}
+
+// FileRef references a summary file in storage.
+type FileRef struct {
+	Bucket string
+	Key    string
+}
+
+// Path returns the storage path of the file ("s3://bucket/key").
+func (ref FileRef) Path() string {
+	return fmt.Sprintf("s3://%s/%s", ref.Bucket, ref.Key)
+}
+
+// ProcessingResult contains the outcome of one file, or of several files
+// processed together (see ProcessFiles).
 type ProcessingResult struct {
-	FilePath         string
+	// FilePath is the path of the (first) processed file.
+	FilePath string
+
+	// FilePaths are the paths of all the processed files.
+	FilePaths []string
+
 	AccountID        string
 	AccountEmail     string
 	TransactionCount int
 	Summary          summaries.Summary
+
+	// EmailSent reports whether the summary email was sent.
+	EmailSent bool
+
+	// EmailError is the error sending the summary email, when it failed in
+	// lenient mode (see ProcessorConfig.LenientMail).
+	EmailError error
+
+	// NoActivity reports whether there were no transactions. The summary
+	// email was then either a "no activity" one or skipped, as reported by
+	// EmailSent (see ProcessorConfig.SkipEmptyEmail).
+	NoActivity bool
+
+	// BytesRead is the size of the content read from storage.
+	BytesRead int64
+
+	// RowsParsed is the number of transactions parsed by the loader, before
+	// they are transformed (see ProcessorConfig.Transformer).
+	RowsParsed int
+
+	// PersistedCount is the number of transactions persisted (0 in dry-run
+	// mode). On a persistence failure, it's the number of leading transactions
+	// committed before it, i.e. the boundary to recover from.
+	PersistedCount int
 }
 
 // ProcessFile executes the entire pipeline strictly.
-// Any failure aborts processing with an error.
+// Any failure aborts processing with an error wrapping the failed stage's
+// sentinel (ErrFileLoad, ErrParse, ErrTransform, ErrPersist or ErrMail), see errors.Is.
+// A file without an account ID is rejected with ErrInvalidAccountID before
+// its transactions are parsed.
+// A persistence failure returns a partial result along with the error, whose
+// PersistedCount is the number of transactions committed before it (also
+// reported by a *PersistError, see errors.As).
+// In lenient mail mode, email failures are reported in the result instead.
 func (tp *DefaultProcessor) ProcessFile(ctx context.Context, bucket, key string) (*ProcessingResult, error) {
-	path := fmt.Sprintf("s3://%s/%s", bucket, key)
+	path := FileRef{Bucket: bucket, Key: key}.Path()
 
+	file, err := tp.loadFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachments []mailing.Attachment
+	if file.statement != nil {
+		attachments = append(attachments, statementAttachment(file.Path, file.statement))
+	}
+
+	result, err := tp.processTransactions(ctx, file.AccountID, file.AccountEmail, file.txns, attachments)
+	if result == nil {
+		return nil, err
+	}
+	result.FilePath = file.Path
+	result.FilePaths = []string{file.Path}
+	result.BytesRead = file.bytesRead
+	result.RowsParsed = len(file.txns)
+	if err != nil {
+		return result, err
+	}
+
+	// Successfully processed
+	tp.logger.Info(ctx, "File %s processed successfully", path)
+	return result, nil
+}
+
+// ProcessFiles executes the entire pipeline over several files of the same
+// account (e.g. a statement split across several objects), as if they were a
+// single file: their transactions are persisted and summarized together, and
+// a single summary email is sent.
+// Files of different accounts abort processing with ErrAccountMismatch; any
+// other failure is reported as in ProcessFile.
+func (tp *DefaultProcessor) ProcessFiles(ctx context.Context, refs []FileRef) (*ProcessingResult, error) {
+	if len(refs) == 0 {
+		return nil, ErrNoFiles
+	}
+
+	var (
+		accountID, accountEmail string
+		paths                   []string
+		txns                    []transactions.Transaction
+		attachments             []mailing.Attachment
+		bytesRead               int64
+	)
+	for _, ref := range refs {
+		file, err := tp.loadFile(ctx, ref.Path())
+		if err != nil {
+			return nil, err
+		}
+
+		if accountID == "" {
+			accountID = file.AccountID
+		} else if file.AccountID != accountID {
+			tp.logger.Error(ctx, "File %s belongs to account %s, not %s", file.Path, file.AccountID, accountID)
+			return nil, fmt.Errorf("%w: %s belongs to account %s, not %s", ErrAccountMismatch, file.Path, file.AccountID, accountID)
+		}
+		if accountEmail == "" {
+			accountEmail = file.AccountEmail
+		}
+
+		paths = append(paths, file.Path)
+		txns = append(txns, file.txns...)
+		bytesRead += file.bytesRead
+		if file.statement != nil {
+			attachments = append(attachments, statementAttachment(file.Path, file.statement))
+		}
+	}
+	rowsParsed := len(txns)
+
+	result, err := tp.processTransactions(ctx, accountID, accountEmail, txns, attachments)
+	if result == nil {
+		return nil, err
+	}
+	result.FilePath = paths[0]
+	result.FilePaths = paths
+	result.BytesRead = bytesRead
+	result.RowsParsed = rowsParsed
+	if err != nil {
+		return result, err
+	}
+
+	// Successfully processed
+	tp.logger.Info(ctx, "%d files of account %s processed successfully", len(paths), accountID)
+	return result, nil
+}
+
+// loadedFile is a summary file loaded by loadFile, along with its parsed
+// transactions.
+type loadedFile struct {
+	*summaries.SummaryFile
+
+	txns []transactions.Transaction
+
+	// statement is the raw content of the file, kept only when the statement
+	// is attached to the email (nil otherwise).
+	statement []byte
+
+	// bytesRead is the size of the content read from storage.
+	bytesRead int64
+}
+
+// loadFile obtains a summary file and parses its transactions, assigning
+// them the file's account. The file's content is closed before returning.
+func (tp *DefaultProcessor) loadFile(ctx context.Context, path string) (*loadedFile, error) {
 	// Obtain the summary file
 	tp.logger.Info(ctx, "Obtaining summary file content from %s...", path)
+	started := time.Now()
 	summaryFile, err := tp.storage.Get(ctx, path)
+	tp.observeStage(ctx, "load", MetricLoadDuration, started)
 	if err != nil {
 		tp.logger.Error(ctx, "Failed to load summary file: %v", err)
-		return nil, fmt.Errorf("failed to load file: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrFileLoad, err)
+	}
+	defer summaryFile.Content.Close()
+
+	// Transactions without an account would corrupt the per-account queries
+	if strings.TrimSpace(summaryFile.AccountID) == "" {
+		tp.logger.Error(ctx, "Summary file %s has no account ID", path)
+		return nil, fmt.Errorf("%w: %s has no account ID", ErrInvalidAccountID, path)
 	}
 	tp.logger.Info(ctx, "Successfully loaded summary file for account %s", summaryFile.AccountID)
 
 	// Parse transactions
 	tp.logger.Info(ctx, "Parsing transactions...")
-	txns, err := tp.loader.LoadTransactions(ctx, summaryFile.Content)
+	started = time.Now()
+	content := &countingReader{reader: summaryFile.Content}
+	var (
+		reader    io.Reader = content
+		statement *bytes.Buffer
+	)
+	if tp.config.AttachStatement {
+		// Keep a copy of the raw content while it's parsed
+		statement = &bytes.Buffer{}
+		reader = io.TeeReader(content, statement)
+	}
+	txns, err := tp.loader.LoadTransactions(ctx, reader)
+	tp.observeStage(ctx, "parse", MetricParseDuration, started)
+	tp.config.Metrics.Count(MetricBytesRead, int(content.count))
 	if err != nil {
 		tp.logger.Error(ctx, "Failed to parse transactions: %v", err)
-		return nil, fmt.Errorf("failed to parse transactions: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrParse, err)
 	}
 	for i := range txns {
 		txns[i].AccountID = summaryFile.AccountID
 	}
 	tp.logger.Info(ctx, "Transactions parsed successfully (%d transactions)", len(txns))
 
+	file := &loadedFile{SummaryFile: summaryFile, txns: txns, bytesRead: content.count}
+	if statement != nil {
+		file.statement = statement.Bytes()
+	}
+	return file, nil
+}
+
+// statementAttachment returns the email attachment of a statement file,
+// named after its storage path.
+func statementAttachment(filePath string, content []byte) mailing.Attachment {
+	contentType := "text/csv"
+	if bytes.HasPrefix(content, []byte{0x1f, 0x8b}) {
+		contentType = "application/gzip"
+	}
+
+	return mailing.Attachment{
+		Filename:    path.Base(filePath),
+		ContentType: contentType,
+		Content:     content,
+	}
+}
+
+// processTransactions transforms, persists and summarizes the transactions of
+// an account, and emails the summary (with the given attachments) when an
+// address is provided. A persistence failure returns a partial result
+// reporting the transactions persisted before it, along with the error.
+func (tp *DefaultProcessor) processTransactions(ctx context.Context, accountID, accountEmail string, txns []transactions.Transaction, attachments []mailing.Attachment) (*ProcessingResult, error) {
+	// Transform transactions
+	if tp.config.Transformer != nil {
+		tp.logger.Info(ctx, "Transforming transactions...")
+		transformed, err := tp.config.Transformer(ctx, txns)
+		if err != nil {
+			tp.logger.Error(ctx, "Failed to transform transactions: %v", err)
+			return nil, fmt.Errorf("%w: %w", ErrTransform, err)
+		}
+		txns = transformed
+		tp.logger.Info(ctx, "Transactions transformed successfully (%d transactions)", len(txns))
+	}
+
 	// Persist transactions
-	tp.logger.Info(ctx, "Persisting transactions to repository...")
-	if err := tp.repository.Save(ctx, txns); err != nil {
-		tp.logger.Error(ctx, "Failed to persist transactions: %v", err)
-		return nil, fmt.Errorf("failed to persist transactions: %w", err)
+	var persisted int
+	if tp.config.DryRun {
+		tp.logger.Info(ctx, "Dry run; skipping persisting %d transactions...", len(txns))
+	} else {
+		if tp.config.SortBeforeSave {
+			txns = sortChronologically(txns)
+		}
+
+		tp.logger.Info(ctx, "Persisting transactions to repository...")
+		started := time.Now()
+		var err error
+		persisted, err = tp.persistTransactions(ctx, txns)
+		tp.observeStage(ctx, "persist", MetricPersistDuration, started)
+		if err != nil {
+			tp.logger.Error(ctx, "Failed to persist transactions (persisted %d of %d transactions): %v", persisted, len(txns), err)
+			return &ProcessingResult{
+				AccountID:        accountID,
+				AccountEmail:     accountEmail,
+				TransactionCount: len(txns),
+				PersistedCount:   persisted,
+			}, fmt.Errorf("%w: %w", ErrPersist, err)
+		}
+		tp.logger.Info(ctx, "Successfully persisted %d transactions", len(txns))
 	}
-	tp.logger.Info(ctx, "Successfully persisted %d transactions", len(txns))
 
 	// Calculate summary
 	tp.logger.Info(ctx, "Calculating summary...")
+	started := time.Now()
 	summaryData := tp.summarizer.CalculateSummary(ctx, txns)
-	tp.logger.Info(ctx, "Calculated summary for account: $(%s)", summaryFile.AccountID)
+	tp.observeStage(ctx, "summarize", MetricSummarizeDuration, started)
+	tp.logger.Info(ctx, "Calculated summary for account: $(%s)", accountID)
+
+	result := &ProcessingResult{
+		AccountID:        accountID,
+		AccountEmail:     accountEmail,
+		TransactionCount: len(txns),
+		Summary:          summaryData,
+		NoActivity:       len(txns) == 0,
+		PersistedCount:   persisted,
+	}
 
 	// Send email if address is provided
-	if summaryFile.AccountEmail != "" {
-		tp.logger.Info(ctx, "Sending summary email to %s...", summaryFile.AccountEmail)
-		if err := tp.mailer.Send(ctx, summaryFile.AccountEmail, summaryData); err != nil {
-			tp.logger.Error(ctx, "Failed to send email: %v", err)
-			return nil, fmt.Errorf("failed to send email: %w", err)
+	switch {
+	case tp.config.DryRun:
+		tp.logger.Info(ctx, "Dry run; skipping email sending...")
+	case result.NoActivity && tp.config.SkipEmptyEmail:
+		tp.logger.Info(ctx, "No transactions for account %s; skipping email sending...", accountID)
+	case accountEmail != "":
+		tp.logger.Info(ctx, "Sending summary email to %s...", accountEmail)
+		started = time.Now()
+		err := tp.sendEmail(ctx, accountID, accountEmail, summaryData, attachments)
+		tp.observeStage(ctx, "mail", MetricMailDuration, started)
+		if err != nil {
+			if !tp.config.LenientMail {
+				return nil, err
+			}
+			tp.logger.Warn(ctx, "Continuing without the summary email (lenient mail mode)")
+			result.EmailError = err
+		} else {
+			tp.logger.Info(ctx, "Sent summary email to %s", accountEmail)
+			result.EmailSent = true
 		}
-		tp.logger.Info(ctx, "Sent summary email to %s", summaryFile.AccountEmail)
-	} else {
+	default:
 		tp.logger.Info(ctx, "No account email provided; skipping email sending...")
 	}
 
-	// Successfully processed
-	tp.logger.Info(ctx, "File %s processed successfully", path)
-	return &ProcessingResult{
-		FilePath:         summaryFile.Path,
-		AccountID:        summaryFile.AccountID,
-		AccountEmail:     summaryFile.AccountEmail,
-		TransactionCount: len(txns),
-		Summary:          summaryData,
-	}, nil
+	tp.config.Metrics.Count(MetricTransactionsProcessed, len(txns))
+	return result, nil
+}
+
+// observeStage records the duration of a pipeline stage started at started,
+// both as a metric and in the logs, to find the slow stages.
+func (tp *DefaultProcessor) observeStage(ctx context.Context, stage, metric string, started time.Time) {
+	duration := time.Since(started)
+	tp.config.Metrics.Duration(metric, duration)
+	tp.logger.Info(ctx, "Stage %s finished (duration=%v)", stage, duration)
+}
+
+// sortChronologically returns a copy of the transactions sorted by date and
+// then by ID. The sort is stable, so identical ones keep their file order.
+func sortChronologically(txns []transactions.Transaction) []transactions.Transaction {
+	// Sort a copy to keep the caller's slice untouched
+	sorted := make([]transactions.Transaction, len(txns))
+	copy(sorted, txns)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].Date.Equal(sorted[j].Date) {
+			return sorted[i].Date.Before(sorted[j].Date)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
+
+// countingReader is a reader that counts the bytes read through it.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (n int, err error) {
+	n, err = r.reader.Read(p)
+	r.count += int64(n)
+	return
+}
+
+// sendEmail sends the summary email of an account, logging and wrapping the
+// failure with ErrMail.
+func (tp *DefaultProcessor) sendEmail(ctx context.Context, accountID, accountEmail string, summaryData summaries.Summary, attachments []mailing.Attachment) error {
+	var opts []mailing.SendOption
+	if len(attachments) > 0 {
+		opts = append(opts, mailing.WithAttachments(attachments...))
+	}
+
+	err := tp.mailer.Send(ctx, accountEmail, summaryData, opts...)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, mailing.ErrInvalidRecipient):
+		tp.logger.Error(ctx, "Invalid account email %q for account %s: %v", accountEmail, accountID, err)
+		return fmt.Errorf("%w: invalid account email for account %s: %w", ErrMail, accountID, err)
+	default:
+		tp.logger.Error(ctx, "Failed to send email: %v", err)
+		return fmt.Errorf("%w: %w", ErrMail, err)
+	}
 }
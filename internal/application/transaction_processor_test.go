@@ -0,0 +1,1051 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"stori-challenge/internal/summaries"
+	"stori-challenge/internal/summaries/mailing"
+	"stori-challenge/internal/transactions"
+	"stori-challenge/pkg/blend"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closeRecorder is a reader that records whether Close was invoked.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+// fakeSummaryFilesStorage returns the same file (or error) for every path.
+type fakeSummaryFilesStorage struct {
+	file *summaries.SummaryFile
+	err  error
+}
+
+func (s *fakeSummaryFilesStorage) Get(_ context.Context, _ string) (*summaries.SummaryFile, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.file, nil
+}
+
+// failingTransactionsRepository is a TransactionsRepository that always fails.
+type failingTransactionsRepository struct {
+	transactions.TransactionsRepository
+	err error
+}
+
+func (r *failingTransactionsRepository) Save(_ context.Context, _ []transactions.Transaction) error {
+	return r.err
+}
+
+// batchingTransactionsRepository is a TransactionsRepository that saves the
+// transactions in batches of batchSize, reporting save statistics, and fails
+// on the failBatch-th batch (1-based, 0 never fails).
+type batchingTransactionsRepository struct {
+	transactions.TransactionsRepository
+	batchSize int
+	failBatch int
+	err       error
+	saved     []transactions.Transaction
+}
+
+func (r *batchingTransactionsRepository) SaveWithStats(_ context.Context, txns []transactions.Transaction) (transactions.SaveResult, error) {
+	var result transactions.SaveResult
+	for batch, start := 1, 0; start < len(txns); batch, start = batch+1, start+r.batchSize {
+		end := min(start+r.batchSize, len(txns))
+		if batch == r.failBatch {
+			result.Failed = len(txns) - start
+			return result, r.err
+		}
+		r.saved = append(r.saved, txns[start:end]...)
+		result.Written += end - start
+		result.Committed = end
+	}
+	return result, nil
+}
+
+// spyLogger records the formatted info messages, discarding the others.
+type spyLogger struct {
+	*blend.DummyLogger
+	infos []string
+}
+
+func (l *spyLogger) Info(_ context.Context, message string, args ...any) error {
+	l.infos = append(l.infos, fmt.Sprintf(message, args...))
+	return nil
+}
+
+// stageDurations returns the stages whose duration was logged, in order.
+func (l *spyLogger) stageDurations(t *testing.T) []string {
+	pattern := regexp.MustCompile(`^Stage (\w+) finished \(duration=(\S+)\)$`)
+
+	var stages []string
+	for _, message := range l.infos {
+		match := pattern.FindStringSubmatch(message)
+		if match == nil {
+			continue
+		}
+		_, err := time.ParseDuration(match[2])
+		require.NoError(t, err, "duration of %q", message)
+		stages = append(stages, match[1])
+	}
+	return stages
+}
+
+// fakeTransactionLoader drains the reader and returns the configured result.
+type fakeTransactionLoader struct {
+	transactions []transactions.Transaction
+	err          error
+}
+
+func (l *fakeTransactionLoader) LoadTransactions(_ context.Context, reader io.Reader) ([]transactions.Transaction, error) {
+	if _, err := io.ReadAll(reader); err != nil {
+		return nil, err
+	}
+	return l.transactions, l.err
+}
+
+// spyTransactionsRepository records the transactions it's asked to save.
+type spyTransactionsRepository struct {
+	transactions.TransactionsRepository
+	saved [][]transactions.Transaction
+}
+
+func (r *spyTransactionsRepository) Save(_ context.Context, txns []transactions.Transaction) error {
+	r.saved = append(r.saved, txns)
+	return nil
+}
+
+// fakeMailer records the recipients and attachments of the sent emails, returning err.
+type fakeMailer struct {
+	recipients  []string
+	attachments [][]mailing.Attachment
+	err         error
+}
+
+func (m *fakeMailer) Send(_ context.Context, to string, _ summaries.Summary, opts ...mailing.SendOption) error {
+	m.recipients = append(m.recipients, to)
+	m.attachments = append(m.attachments, mailing.NewSendOptions(opts...).Attachments)
+	return m.err
+}
+
+// newTestProcessor returns a processor reading the given file, along with
+// the file's content so tests can check whether it was closed.
+func newTestProcessor(loader transactions.TransactionLoader, mailer *fakeMailer) (*DefaultProcessor, *closeRecorder) {
+	content := &closeRecorder{Reader: strings.NewReader("Id,Date,Transaction\n0,7/15,+60.5\n")}
+	storage := &fakeSummaryFilesStorage{file: &summaries.SummaryFile{
+		Path:         "s3://bucket/file.csv",
+		AccountID:    "ACC123",
+		AccountEmail: "john@example.com",
+		Content:      content,
+	}}
+
+	processor := NewProcessor(
+		blend.NewDummyLogger(),
+		storage,
+		loader,
+		transactions.NewMemoryTransactionsRepository(),
+		summaries.NewDefaultSummarizer(),
+		mailer,
+	)
+	return processor, content
+}
+
+func TestDefaultProcessor_ProcessFile(t *testing.T) {
+	t.Run("it should close the file content after processing", func(t *testing.T) {
+		// Arrange
+		mailer := &fakeMailer{}
+		loader := &fakeTransactionLoader{transactions: []transactions.Transaction{{ID: 0, Amount: 60_50}}}
+		processor, content := newTestProcessor(loader, mailer)
+
+		// Act
+		result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.TransactionCount)
+		assert.Equal(t, []string{"john@example.com"}, mailer.recipients)
+		assert.True(t, result.EmailSent)
+		assert.NoError(t, result.EmailError)
+		assert.True(t, content.closed)
+	})
+
+	t.Run("it should close the file content when processing fails", func(t *testing.T) {
+		// Arrange
+		loader := &fakeTransactionLoader{err: errors.New("malformed CSV")}
+		processor, content := newTestProcessor(loader, &fakeMailer{})
+
+		// Act
+		_, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		assert.ErrorContains(t, err, "failed to parse transactions")
+		assert.True(t, content.closed)
+	})
+}
+
+func TestDefaultProcessor_ProcessFile_Errors(t *testing.T) {
+	failure := errors.New("boom")
+
+	tests := []struct {
+		name        string
+		setup       func(processor *DefaultProcessor, loader *fakeTransactionLoader, mailer *fakeMailer)
+		expectedErr error
+		cause       error
+	}{
+		{
+			name: "it should identify a storage failure",
+			setup: func(processor *DefaultProcessor, _ *fakeTransactionLoader, _ *fakeMailer) {
+				processor.storage = &fakeSummaryFilesStorage{err: failure}
+			},
+			expectedErr: ErrFileLoad,
+			cause:       failure,
+		},
+		{
+			name: "it should identify a parse failure",
+			setup: func(_ *DefaultProcessor, loader *fakeTransactionLoader, _ *fakeMailer) {
+				loader.err = failure
+			},
+			expectedErr: ErrParse,
+			cause:       failure,
+		},
+		{
+			name: "it should identify a transform failure",
+			setup: func(processor *DefaultProcessor, _ *fakeTransactionLoader, _ *fakeMailer) {
+				processor.config.Transformer = func(context.Context, []transactions.Transaction) ([]transactions.Transaction, error) {
+					return nil, failure
+				}
+			},
+			expectedErr: ErrTransform,
+			cause:       failure,
+		},
+		{
+			name: "it should identify a persistence failure",
+			setup: func(processor *DefaultProcessor, _ *fakeTransactionLoader, _ *fakeMailer) {
+				processor.repository = &failingTransactionsRepository{err: failure}
+			},
+			expectedErr: ErrPersist,
+			cause:       failure,
+		},
+		{
+			name: "it should identify a mail failure",
+			setup: func(_ *DefaultProcessor, _ *fakeTransactionLoader, mailer *fakeMailer) {
+				mailer.err = failure
+			},
+			expectedErr: ErrMail,
+			cause:       failure,
+		},
+		{
+			name: "it should identify an invalid recipient as a mail failure",
+			setup: func(_ *DefaultProcessor, _ *fakeTransactionLoader, mailer *fakeMailer) {
+				mailer.err = mailing.ErrInvalidRecipient
+			},
+			expectedErr: ErrMail,
+			cause:       mailing.ErrInvalidRecipient,
+		},
+	}
+
+	stages := []error{ErrFileLoad, ErrParse, ErrTransform, ErrPersist, ErrMail}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			loader := &fakeTransactionLoader{transactions: []transactions.Transaction{{ID: 0, Amount: 60_50}}}
+			mailer := &fakeMailer{}
+			processor, _ := newTestProcessor(loader, mailer)
+			tt.setup(processor, loader, mailer)
+
+			// Act
+			result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+			// Assert
+			if tt.expectedErr == ErrPersist {
+				// A persistence failure reports the recovery boundary in a partial result
+				require.NotNil(t, result)
+				assert.Zero(t, result.PersistedCount)
+			} else {
+				assert.Nil(t, result)
+			}
+			assert.ErrorIs(t, err, tt.cause)
+			for _, stage := range stages {
+				assert.Equal(t, stage == tt.expectedErr, errors.Is(err, stage), "errors.Is(err, %v)", stage)
+			}
+		})
+	}
+}
+
+func TestDefaultProcessor_ProcessFile_AccountID(t *testing.T) {
+	tests := []struct {
+		name        string
+		accountID   string
+		expectedErr error
+	}{
+		{name: "it should reject an empty account ID", accountID: "", expectedErr: ErrInvalidAccountID},
+		{name: "it should reject a whitespace account ID", accountID: " \t ", expectedErr: ErrInvalidAccountID},
+		{name: "it should process a valid account ID", accountID: "ACC123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			loader := &fakeTransactionLoader{transactions: []transactions.Transaction{{ID: 0, Amount: 60_50}}}
+			repository := &spyTransactionsRepository{}
+			mailer := &fakeMailer{}
+			processor, content := newTestProcessor(loader, mailer)
+			processor.storage.(*fakeSummaryFilesStorage).file.AccountID = tt.accountID
+			processor.repository = repository
+
+			// Act
+			result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+			// Assert
+			assert.True(t, content.closed)
+			if tt.expectedErr != nil {
+				assert.Nil(t, result)
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.ErrorContains(t, err, "s3://bucket/file.csv has no account ID")
+				assert.Empty(t, repository.saved, "should not persist transactions without an account")
+				assert.Empty(t, mailer.recipients)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.accountID, result.AccountID)
+			require.Len(t, repository.saved, 1)
+			assert.Equal(t, tt.accountID, repository.saved[0][0].AccountID)
+		})
+	}
+
+	t.Run("it should reject a file without an account ID among several", func(t *testing.T) {
+		// Arrange
+		storage := mapSummaryFilesStorage{
+			"s3://bucket/july.csv":   newTestFile("s3://bucket/july.csv", "ACC123", "john@example.com", "Id,Date,Transaction\n0,7/15/2024,+60.5\n"),
+			"s3://bucket/august.csv": newTestFile("s3://bucket/august.csv", "", "john@example.com", "Id,Date,Transaction\n1,8/2/2024,-20.46\n"),
+		}
+		repository := transactions.NewMemoryTransactionsRepository()
+		processor := NewProcessor(blend.NewDummyLogger(), storage, transactions.NewCSVTransactionLoader(), repository, summaries.NewDefaultSummarizer(), &fakeMailer{})
+
+		// Act
+		result, err := processor.ProcessFiles(context.Background(), []FileRef{{Bucket: "bucket", Key: "july.csv"}, {Bucket: "bucket", Key: "august.csv"}})
+
+		// Assert
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrInvalidAccountID)
+		assert.Empty(t, repository.All())
+	})
+}
+
+func TestDefaultProcessor_ProcessFile_LenientMail(t *testing.T) {
+	failure := errors.New("smtp: 421 service not available")
+
+	t.Run("it should fail the file on a mail failure in strict mode", func(t *testing.T) {
+		// Arrange
+		loader := &fakeTransactionLoader{transactions: []transactions.Transaction{{ID: 0, Amount: 60_50}}}
+		mailer := &fakeMailer{err: failure}
+		processor, _ := newTestProcessor(loader, mailer)
+
+		// Act
+		result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrMail)
+	})
+
+	t.Run("it should report a mail failure in the result in lenient mode", func(t *testing.T) {
+		// Arrange
+		loader := &fakeTransactionLoader{transactions: []transactions.Transaction{{ID: 0, Amount: 60_50}}}
+		mailer := &fakeMailer{err: failure}
+		processor, _ := newTestProcessor(loader, mailer)
+		processor.config.LenientMail = true
+
+		// Act
+		result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.TransactionCount)
+		assert.False(t, result.EmailSent)
+		assert.ErrorIs(t, result.EmailError, ErrMail)
+		assert.ErrorIs(t, result.EmailError, failure)
+	})
+
+	t.Run("it should report a sent email in lenient mode", func(t *testing.T) {
+		// Arrange
+		loader := &fakeTransactionLoader{transactions: []transactions.Transaction{{ID: 0, Amount: 60_50}}}
+		processor, _ := newTestProcessor(loader, &fakeMailer{})
+		processor.config.LenientMail = true
+
+		// Act
+		result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, result.EmailSent)
+		assert.NoError(t, result.EmailError)
+	})
+
+	t.Run("it should still fail the file on a persistence failure in lenient mode", func(t *testing.T) {
+		// Arrange
+		loader := &fakeTransactionLoader{transactions: []transactions.Transaction{{ID: 0, Amount: 60_50}}}
+		mailer := &fakeMailer{}
+		processor, _ := newTestProcessor(loader, mailer)
+		processor.config.LenientMail = true
+		processor.repository = &failingTransactionsRepository{err: failure}
+
+		// Act
+		result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		assert.ErrorIs(t, err, ErrPersist)
+		require.NotNil(t, result)
+		assert.False(t, result.EmailSent)
+		assert.Empty(t, mailer.recipients)
+	})
+}
+
+func TestDefaultProcessor_ProcessFile_PartialPersistence(t *testing.T) {
+	// newTransactions returns count transactions with consecutive IDs.
+	newTransactions := func(count int) []transactions.Transaction {
+		txns := make([]transactions.Transaction, count)
+		for i := range txns {
+			txns[i] = transactions.Transaction{ID: uint(i), Amount: 10_00}
+		}
+		return txns
+	}
+
+	t.Run("it should report the transactions committed before the failed batch", func(t *testing.T) {
+		// Arrange
+		failure := errors.New("ProvisionedThroughputExceededException")
+		repository := &batchingTransactionsRepository{batchSize: 25, failBatch: 2, err: failure}
+		mailer := &fakeMailer{}
+		processor, _ := newTestProcessor(&fakeTransactionLoader{transactions: newTransactions(60)}, mailer)
+		processor.repository = repository
+
+		// Act
+		result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		assert.ErrorIs(t, err, ErrPersist)
+		assert.ErrorIs(t, err, failure)
+		require.NotNil(t, result, "should report the recovery boundary")
+		assert.Equal(t, 25, result.PersistedCount, "should count the first batch only")
+		assert.Equal(t, 60, result.TransactionCount)
+		assert.Equal(t, "ACC123", result.AccountID)
+		var persistErr *PersistError
+		require.ErrorAs(t, err, &persistErr)
+		assert.Equal(t, 25, persistErr.PersistedCount)
+		assert.Len(t, repository.saved, 25)
+		assert.Empty(t, mailer.recipients)
+	})
+
+	t.Run("it should report every transaction as persisted on success", func(t *testing.T) {
+		// Arrange
+		repository := &batchingTransactionsRepository{batchSize: 25}
+		processor, _ := newTestProcessor(&fakeTransactionLoader{transactions: newTransactions(60)}, &fakeMailer{})
+		processor.repository = repository
+
+		// Act
+		result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 60, result.PersistedCount)
+	})
+
+	t.Run("it should report no transactions persisted when the repository doesn't report them", func(t *testing.T) {
+		// Arrange
+		failure := errors.New("boom")
+		processor, _ := newTestProcessor(&fakeTransactionLoader{transactions: newTransactions(3)}, &fakeMailer{})
+		processor.repository = &failingTransactionsRepository{err: failure}
+
+		// Act
+		_, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		var persistErr *PersistError
+		require.ErrorAs(t, err, &persistErr)
+		assert.Zero(t, persistErr.PersistedCount)
+		assert.ErrorIs(t, err, failure)
+	})
+}
+
+func TestDefaultProcessor_ProcessFile_DryRun(t *testing.T) {
+	t.Run("it should neither persist nor send email in dry-run mode", func(t *testing.T) {
+		// Arrange
+		loader := &fakeTransactionLoader{transactions: []transactions.Transaction{
+			{ID: 0, Date: time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50},
+			{ID: 1, Date: time.Date(2024, time.July, 28, 0, 0, 0, 0, time.UTC), Amount: -10_30},
+		}}
+		mailer := &fakeMailer{}
+		repository := &spyTransactionsRepository{}
+		processor, content := newTestProcessor(loader, mailer)
+		processor.repository = repository
+		processor.config.DryRun = true
+
+		// Act
+		result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Empty(t, repository.saved)
+		assert.Empty(t, mailer.recipients)
+		assert.False(t, result.EmailSent)
+		assert.Equal(t, "ACC123", result.AccountID)
+		assert.Equal(t, 2, result.TransactionCount)
+		assert.Zero(t, result.PersistedCount)
+		assert.Equal(t, summaries.NewDefaultSummarizer().CalculateSummary(context.Background(), loader.transactions), result.Summary)
+		assert.True(t, content.closed)
+	})
+
+	t.Run("it should persist and send email outside dry-run mode", func(t *testing.T) {
+		// Arrange
+		loader := &fakeTransactionLoader{transactions: []transactions.Transaction{{ID: 0, Amount: 60_50}}}
+		mailer := &fakeMailer{}
+		repository := &spyTransactionsRepository{}
+		processor, _ := newTestProcessor(loader, mailer)
+		processor.repository = repository
+
+		// Act
+		_, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Len(t, repository.saved, 1)
+		assert.Len(t, mailer.recipients, 1)
+	})
+}
+
+func TestDefaultProcessor_ProcessFile_EmptyFile(t *testing.T) {
+	tests := []struct {
+		name               string
+		skipEmptyEmail     bool
+		expectedRecipients []string
+	}{
+		{
+			name:               "it should send a no activity email by default",
+			skipEmptyEmail:     false,
+			expectedRecipients: []string{"john@example.com"},
+		},
+		{
+			name:               "it should skip the email when configured to",
+			skipEmptyEmail:     true,
+			expectedRecipients: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mailer := &fakeMailer{}
+			processor, _ := newTestProcessor(transactions.NewCSVTransactionLoader(), mailer)
+			processor.storage = &fakeSummaryFilesStorage{file: newTestFile("s3://bucket/file.csv", "ACC123", "john@example.com", "Id,Date,Transaction\n")}
+			processor.config.SkipEmptyEmail = tt.skipEmptyEmail
+
+			// Act
+			result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, 0, result.TransactionCount)
+			assert.True(t, result.NoActivity)
+			assert.Equal(t, tt.expectedRecipients, mailer.recipients)
+			assert.Equal(t, !tt.skipEmptyEmail, result.EmailSent)
+		})
+	}
+
+	t.Run("it should report activity for a file with transactions", func(t *testing.T) {
+		// Arrange
+		mailer := &fakeMailer{}
+		processor, _ := newTestProcessor(transactions.NewCSVTransactionLoader(), mailer)
+		processor.config.SkipEmptyEmail = true
+
+		// Act
+		result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.False(t, result.NoActivity)
+		assert.True(t, result.EmailSent)
+	})
+}
+
+// mapSummaryFilesStorage returns the file stored under each path.
+type mapSummaryFilesStorage map[string]*summaries.SummaryFile
+
+func (s mapSummaryFilesStorage) Get(_ context.Context, path string) (*summaries.SummaryFile, error) {
+	file, ok := s[path]
+	if !ok {
+		return nil, errors.New("no such file")
+	}
+	return file, nil
+}
+
+// newTestFile returns a summary file of the given account with a CSV content.
+func newTestFile(path, accountID, accountEmail, content string) *summaries.SummaryFile {
+	return &summaries.SummaryFile{
+		Path:         path,
+		AccountID:    accountID,
+		AccountEmail: accountEmail,
+		Content:      &closeRecorder{Reader: strings.NewReader(content)},
+	}
+}
+
+func TestDefaultProcessor_ProcessFiles(t *testing.T) {
+	refs := []FileRef{{Bucket: "bucket", Key: "july.csv"}, {Bucket: "bucket", Key: "august.csv"}}
+
+	t.Run("it should combine the files of an account into one summary", func(t *testing.T) {
+		// Arrange
+		storage := mapSummaryFilesStorage{
+			"s3://bucket/july.csv":   newTestFile("s3://bucket/july.csv", "ACC123", "john@example.com", "Id,Date,Transaction\n0,7/15/2024,+60.5\n1,7/28/2024,-10.3\n"),
+			"s3://bucket/august.csv": newTestFile("s3://bucket/august.csv", "ACC123", "", "Id,Date,Transaction\n2,8/2/2024,-20.46\n"),
+		}
+		repository := transactions.NewMemoryTransactionsRepository()
+		mailer := &fakeMailer{}
+		processor := NewProcessor(blend.NewDummyLogger(), storage, transactions.NewCSVTransactionLoader(), repository, summaries.NewDefaultSummarizer(), mailer)
+
+		// Act
+		result, err := processor.ProcessFiles(context.Background(), refs)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "ACC123", result.AccountID)
+		assert.Equal(t, "john@example.com", result.AccountEmail)
+		assert.Equal(t, "s3://bucket/july.csv", result.FilePath)
+		assert.Equal(t, []string{"s3://bucket/july.csv", "s3://bucket/august.csv"}, result.FilePaths)
+		assert.Equal(t, 3, result.TransactionCount)
+		assert.Equal(t, int64(56+38), result.BytesRead)
+		assert.Equal(t, 3, result.RowsParsed)
+		assert.InDelta(t, 29.74, result.Summary.TotalBalance, 0.001)
+		assert.Len(t, repository.All(), 3)
+		assert.Equal(t, []string{"john@example.com"}, mailer.recipients)
+		assert.True(t, result.EmailSent)
+		for _, file := range storage {
+			assert.True(t, file.Content.(*closeRecorder).closed)
+		}
+	})
+
+	t.Run("it should reject files of different accounts", func(t *testing.T) {
+		// Arrange
+		storage := mapSummaryFilesStorage{
+			"s3://bucket/july.csv":   newTestFile("s3://bucket/july.csv", "ACC123", "john@example.com", "Id,Date,Transaction\n0,7/15/2024,+60.5\n"),
+			"s3://bucket/august.csv": newTestFile("s3://bucket/august.csv", "ACC456", "jane@example.com", "Id,Date,Transaction\n0,8/2/2024,-20.46\n"),
+		}
+		repository := transactions.NewMemoryTransactionsRepository()
+		mailer := &fakeMailer{}
+		processor := NewProcessor(blend.NewDummyLogger(), storage, transactions.NewCSVTransactionLoader(), repository, summaries.NewDefaultSummarizer(), mailer)
+
+		// Act
+		result, err := processor.ProcessFiles(context.Background(), refs)
+
+		// Assert
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrAccountMismatch)
+		assert.ErrorContains(t, err, "s3://bucket/august.csv belongs to account ACC456, not ACC123")
+		assert.Empty(t, repository.All())
+		assert.Empty(t, mailer.recipients)
+	})
+
+	t.Run("it should reject an empty list of files", func(t *testing.T) {
+		// Arrange
+		processor, _ := newTestProcessor(&fakeTransactionLoader{}, &fakeMailer{})
+
+		// Act
+		result, err := processor.ProcessFiles(context.Background(), nil)
+
+		// Assert
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrNoFiles)
+	})
+
+	t.Run("it should identify a failure loading one of the files", func(t *testing.T) {
+		// Arrange
+		storage := mapSummaryFilesStorage{
+			"s3://bucket/july.csv": newTestFile("s3://bucket/july.csv", "ACC123", "john@example.com", "Id,Date,Transaction\n0,7/15/2024,+60.5\n"),
+		}
+		processor := NewProcessor(blend.NewDummyLogger(), storage, transactions.NewCSVTransactionLoader(), transactions.NewMemoryTransactionsRepository(), summaries.NewDefaultSummarizer(), &fakeMailer{})
+
+		// Act
+		_, err := processor.ProcessFiles(context.Background(), refs)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrFileLoad)
+	})
+}
+
+func TestDefaultProcessor_ProcessFile_Throughput(t *testing.T) {
+	const content = "Id,Date,Transaction\n0,7/15/2024,+60.5\n1,7/28/2024,-10.3\n"
+
+	t.Run("it should report the bytes read and the rows parsed", func(t *testing.T) {
+		// Arrange
+		processor, _ := newTestProcessor(transactions.NewCSVTransactionLoader(), &fakeMailer{})
+		processor.storage = &fakeSummaryFilesStorage{file: newTestFile("s3://bucket/file.csv", "ACC123", "john@example.com", content)}
+
+		// Act
+		result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(56), result.BytesRead)
+		assert.Equal(t, 2, result.RowsParsed)
+	})
+
+	t.Run("it should count the rows parsed before they are transformed", func(t *testing.T) {
+		// Arrange
+		processor, _ := newTestProcessor(transactions.NewCSVTransactionLoader(), &fakeMailer{})
+		processor.storage = &fakeSummaryFilesStorage{file: newTestFile("s3://bucket/file.csv", "ACC123", "john@example.com", content)}
+		processor.config.Transformer = func(_ context.Context, txns []transactions.Transaction) ([]transactions.Transaction, error) {
+			return txns[:1], nil
+		}
+
+		// Act
+		result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.TransactionCount)
+		assert.Equal(t, 2, result.RowsParsed)
+	})
+}
+
+func TestDefaultProcessor_ProcessFile_SortBeforeSave(t *testing.T) {
+	july15 := time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC)
+	july28 := time.Date(2024, time.July, 28, 0, 0, 0, 0, time.UTC)
+	august2 := time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC)
+	shuffled := []transactions.Transaction{
+		{ID: 3, Date: august2, Amount: -20_46},
+		{ID: 2, Date: july28, Amount: 10_00},
+		{ID: 1, Date: july28, Amount: -10_30},
+		{ID: 0, Date: july15, Amount: 60_50},
+		{ID: 1, Date: july28, Amount: -5_00},
+	}
+
+	tests := []struct {
+		name           string
+		sortBeforeSave bool
+		expected       []transactions.Transaction
+	}{
+		{
+			name:           "it should persist the transactions sorted by date and ID",
+			sortBeforeSave: true,
+			expected: []transactions.Transaction{
+				{ID: 0, Date: july15, Amount: 60_50, AccountID: "ACC123"},
+				{ID: 1, Date: july28, Amount: -10_30, AccountID: "ACC123"},
+				{ID: 1, Date: july28, Amount: -5_00, AccountID: "ACC123"},
+				{ID: 2, Date: july28, Amount: 10_00, AccountID: "ACC123"},
+				{ID: 3, Date: august2, Amount: -20_46, AccountID: "ACC123"},
+			},
+		},
+		{
+			name:           "it should persist the transactions in file order by default",
+			sortBeforeSave: false,
+			expected: []transactions.Transaction{
+				{ID: 3, Date: august2, Amount: -20_46, AccountID: "ACC123"},
+				{ID: 2, Date: july28, Amount: 10_00, AccountID: "ACC123"},
+				{ID: 1, Date: july28, Amount: -10_30, AccountID: "ACC123"},
+				{ID: 0, Date: july15, Amount: 60_50, AccountID: "ACC123"},
+				{ID: 1, Date: july28, Amount: -5_00, AccountID: "ACC123"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			loader := &fakeTransactionLoader{transactions: slices.Clone(shuffled)}
+			repository := &spyTransactionsRepository{}
+			processor, _ := newTestProcessor(loader, &fakeMailer{})
+			processor.repository = repository
+			processor.config.SortBeforeSave = tt.sortBeforeSave
+
+			// Act
+			result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+			// Assert
+			require.NoError(t, err)
+			require.Len(t, repository.saved, 1)
+			assert.Equal(t, tt.expected, repository.saved[0])
+			assert.Equal(t, 5, result.PersistedCount)
+		})
+	}
+}
+
+func TestDefaultProcessor_ProcessFile_StageDurations(t *testing.T) {
+	t.Run("it should log the duration of every stage", func(t *testing.T) {
+		// Arrange
+		logger := &spyLogger{DummyLogger: blend.NewDummyLogger()}
+		loader := &fakeTransactionLoader{transactions: []transactions.Transaction{{ID: 0, Amount: 60_50}}}
+		processor, _ := newTestProcessor(loader, &fakeMailer{})
+		processor.logger = logger
+
+		// Act
+		_, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"load", "parse", "persist", "summarize", "mail"}, logger.stageDurations(t))
+	})
+
+	t.Run("it should only log the duration of the stages that ran in dry-run mode", func(t *testing.T) {
+		// Arrange
+		logger := &spyLogger{DummyLogger: blend.NewDummyLogger()}
+		loader := &fakeTransactionLoader{transactions: []transactions.Transaction{{ID: 0, Amount: 60_50}}}
+		processor, _ := newTestProcessor(loader, &fakeMailer{})
+		processor.logger = logger
+		processor.config.DryRun = true
+
+		// Act
+		_, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"load", "parse", "summarize"}, logger.stageDurations(t))
+	})
+
+	t.Run("it should log the duration of a failed stage", func(t *testing.T) {
+		// Arrange
+		logger := &spyLogger{DummyLogger: blend.NewDummyLogger()}
+		processor, _ := newTestProcessor(&fakeTransactionLoader{err: errors.New("malformed CSV")}, &fakeMailer{})
+		processor.logger = logger
+
+		// Act
+		_, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		assert.ErrorIs(t, err, ErrParse)
+		assert.Equal(t, []string{"load", "parse"}, logger.stageDurations(t))
+	})
+}
+
+func TestDefaultProcessor_ProcessFile_Transformer(t *testing.T) {
+	t.Run("it should persist and summarize the transformed transactions", func(t *testing.T) {
+		// Arrange
+		loader := &fakeTransactionLoader{transactions: []transactions.Transaction{{ID: 0, Amount: 6050_00}, {ID: 1, Amount: -1030_00}}}
+		repository := &spyTransactionsRepository{}
+		processor, _ := newTestProcessor(loader, &fakeMailer{})
+		processor.repository = repository
+		processor.config.Transformer = func(_ context.Context, txns []transactions.Transaction) ([]transactions.Transaction, error) {
+			for i := range txns {
+				txns[i].Amount /= 100
+			}
+			return txns, nil
+		}
+
+		// Act
+		result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, repository.saved, 1)
+		assert.Equal(t, []transactions.Transaction{
+			{ID: 0, Amount: 60_50, AccountID: "ACC123"},
+			{ID: 1, Amount: -10_30, AccountID: "ACC123"},
+		}, repository.saved[0])
+		assert.InDelta(t, 50.2, result.Summary.TotalBalance, 0.001)
+	})
+
+	t.Run("it should abort the pipeline when the transformer fails", func(t *testing.T) {
+		// Arrange
+		loader := &fakeTransactionLoader{transactions: []transactions.Transaction{{ID: 0, Amount: 6050_00}}}
+		repository := &spyTransactionsRepository{}
+		mailer := &fakeMailer{}
+		processor, _ := newTestProcessor(loader, mailer)
+		processor.repository = repository
+		processor.config.Transformer = func(context.Context, []transactions.Transaction) ([]transactions.Transaction, error) {
+			return nil, errors.New("unknown currency")
+		}
+
+		// Act
+		result, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrTransform)
+		assert.ErrorContains(t, err, "unknown currency")
+		assert.Empty(t, repository.saved)
+		assert.Empty(t, mailer.recipients)
+	})
+}
+
+func TestDefaultProcessor_ProcessFile_AttachStatement(t *testing.T) {
+	t.Run("it should attach the original statement to the email", func(t *testing.T) {
+		// Arrange
+		mailer := &fakeMailer{}
+		processor, _ := newTestProcessor(transactions.NewCSVTransactionLoader(), mailer)
+		processor.config.AttachStatement = true
+
+		// Act
+		_, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, mailer.attachments, 1)
+		assert.Equal(t, []mailing.Attachment{{
+			Filename:    "file.csv",
+			ContentType: "text/csv",
+			Content:     []byte("Id,Date,Transaction\n0,7/15,+60.5\n"),
+		}}, mailer.attachments[0])
+	})
+
+	t.Run("it should attach every statement of the account", func(t *testing.T) {
+		// Arrange
+		storage := mapSummaryFilesStorage{
+			"s3://bucket/july.csv":   newTestFile("s3://bucket/july.csv", "ACC123", "john@example.com", "Id,Date,Transaction\n0,7/15/2024,+60.5\n"),
+			"s3://bucket/august.csv": newTestFile("s3://bucket/august.csv", "ACC123", "", "Id,Date,Transaction\n1,8/2/2024,-20.46\n"),
+		}
+		mailer := &fakeMailer{}
+		config := DefaultProcessorConfig()
+		config.AttachStatement = true
+		processor := NewProcessorWithConfig(blend.NewDummyLogger(), storage, transactions.NewCSVTransactionLoader(), transactions.NewMemoryTransactionsRepository(), summaries.NewDefaultSummarizer(), mailer, config)
+
+		// Act
+		_, err := processor.ProcessFiles(context.Background(), []FileRef{{Bucket: "bucket", Key: "july.csv"}, {Bucket: "bucket", Key: "august.csv"}})
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, mailer.attachments, 1)
+		require.Len(t, mailer.attachments[0], 2)
+		assert.Equal(t, "july.csv", mailer.attachments[0][0].Filename)
+		assert.Equal(t, []byte("Id,Date,Transaction\n1,8/2/2024,-20.46\n"), mailer.attachments[0][1].Content)
+	})
+
+	t.Run("it should label a compressed statement as gzip", func(t *testing.T) {
+		// Act
+		attachment := statementAttachment("s3://bucket/file.csv.gz", []byte{0x1f, 0x8b, 0x08})
+
+		// Assert
+		assert.Equal(t, "file.csv.gz", attachment.Filename)
+		assert.Equal(t, "application/gzip", attachment.ContentType)
+	})
+
+	t.Run("it should not attach the statement by default", func(t *testing.T) {
+		// Arrange
+		mailer := &fakeMailer{}
+		processor, _ := newTestProcessor(transactions.NewCSVTransactionLoader(), mailer)
+
+		// Act
+		_, err := processor.ProcessFile(context.Background(), "bucket", "file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, mailer.attachments, 1)
+		assert.Empty(t, mailer.attachments[0])
+	})
+}
+
+// fakePinger counts the connectivity checks, returning err.
+type fakePinger struct {
+	pings int
+	err   error
+}
+
+func (p *fakePinger) Ping(_ context.Context) error {
+	p.pings++
+	return p.err
+}
+
+// pingingStorage, pingingRepository and pingingMailer are dependencies that
+// can check their connectivity.
+type (
+	pingingStorage struct {
+		*fakeSummaryFilesStorage
+		*fakePinger
+	}
+	pingingRepository struct {
+		transactions.TransactionsRepository
+		*fakePinger
+	}
+	pingingMailer struct {
+		*fakeMailer
+		*fakePinger
+	}
+)
+
+func TestDefaultProcessor_SelfTest(t *testing.T) {
+	// newSelfTestProcessor returns a processor whose dependencies check their
+	// connectivity with the given pingers.
+	newSelfTestProcessor := func(storage, repository, mailer *fakePinger) *DefaultProcessor {
+		return NewProcessor(
+			blend.NewDummyLogger(),
+			pingingStorage{&fakeSummaryFilesStorage{}, storage},
+			&fakeTransactionLoader{},
+			pingingRepository{transactions.NewMemoryTransactionsRepository(), repository},
+			summaries.NewDefaultSummarizer(),
+			pingingMailer{&fakeMailer{}, mailer},
+		)
+	}
+
+	t.Run("it should pass when every dependency is reachable", func(t *testing.T) {
+		// Arrange
+		storage, repository, mailer := &fakePinger{}, &fakePinger{}, &fakePinger{}
+		processor := newSelfTestProcessor(storage, repository, mailer)
+
+		// Act
+		err := processor.SelfTest(context.Background())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, storage.pings)
+		assert.Equal(t, 1, repository.pings)
+		assert.Equal(t, 1, mailer.pings)
+	})
+
+	t.Run("it should report the dependency that is unreachable", func(t *testing.T) {
+		// Arrange
+		failure := errors.New("ResourceNotFoundException: Requested resource not found")
+		storage, repository, mailer := &fakePinger{}, &fakePinger{err: failure}, &fakePinger{}
+		processor := newSelfTestProcessor(storage, repository, mailer)
+
+		// Act
+		err := processor.SelfTest(context.Background())
+
+		// Assert
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrSelfTest)
+		assert.ErrorIs(t, err, failure)
+		assert.ErrorContains(t, err, "repository: ResourceNotFoundException")
+		assert.NotContains(t, err.Error(), "storage")
+		assert.Equal(t, 1, mailer.pings, "should check the remaining dependencies")
+	})
+
+	t.Run("it should report every unreachable dependency", func(t *testing.T) {
+		// Arrange
+		s3Failure := errors.New("Forbidden: Access Denied")
+		smtpFailure := errors.New("dial tcp: connection refused")
+		processor := newSelfTestProcessor(&fakePinger{err: s3Failure}, &fakePinger{}, &fakePinger{err: smtpFailure})
+
+		// Act
+		err := processor.SelfTest(context.Background())
+
+		// Assert
+		assert.ErrorIs(t, err, s3Failure)
+		assert.ErrorIs(t, err, smtpFailure)
+		assert.ErrorContains(t, err, "storage: Forbidden")
+		assert.ErrorContains(t, err, "mailer: dial tcp")
+	})
+
+	t.Run("it should skip the dependencies that can't check their connectivity", func(t *testing.T) {
+		// Arrange
+		processor, _ := newTestProcessor(&fakeTransactionLoader{}, &fakeMailer{})
+
+		// Act
+		err := processor.SelfTest(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+	})
+}
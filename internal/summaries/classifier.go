@@ -0,0 +1,43 @@
+package summaries
+
+// Kind represents how a transaction amount is accounted for in a summary.
+type Kind string
+
+const (
+	// KindDebit is used for amounts that count as debits (money going out).
+	KindDebit Kind = "debit"
+
+	// KindCredit is used for amounts that count as credits (money coming in).
+	KindCredit Kind = "credit"
+
+	// KindNone is used for amounts that count as neither debits nor credits.
+	KindNone Kind = "none"
+)
+
+// Classifier defines the interface for deciding whether a transaction amount
+// is a debit, a credit or neither.
+type Classifier interface {
+	// Classify returns the Kind of the given transaction amount.
+	Classify(amount float64) Kind
+}
+
+// SignClassifier is the default Classifier, which classifies amounts by their sign:
+// negative amounts are debits, positive amounts are credits and zero is neither.
+type SignClassifier struct{}
+
+// NewSignClassifier creates a new instance of SignClassifier.
+func NewSignClassifier() *SignClassifier {
+	return &SignClassifier{}
+}
+
+// Classify implements the Classifier interface by looking at the sign of the amount.
+func (c *SignClassifier) Classify(amount float64) Kind {
+	switch {
+	case amount < 0:
+		return KindDebit
+	case amount > 0:
+		return KindCredit
+	default:
+		return KindNone
+	}
+}
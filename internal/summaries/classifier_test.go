@@ -0,0 +1,44 @@
+package summaries
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignClassifier_Classify(t *testing.T) {
+	// Arrange
+	classifier := NewSignClassifier()
+
+	tests := []struct {
+		name     string
+		amount   float64
+		expected Kind
+	}{
+		{
+			name:     "it should classify negative amounts as debits",
+			amount:   -50.00,
+			expected: KindDebit,
+		},
+		{
+			name:     "it should classify positive amounts as credits",
+			amount:   100.00,
+			expected: KindCredit,
+		},
+		{
+			name:     "it should classify zero as neither",
+			amount:   0,
+			expected: KindNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := classifier.Classify(tt.amount)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
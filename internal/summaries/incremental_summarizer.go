@@ -0,0 +1,207 @@
+package summaries
+
+import (
+	"slices"
+	"stori-challenge/internal/transactions"
+	"time"
+)
+
+// IncrementalSummarizer summarizes transactions added one at a time (e.g. as
+// they are streamed from a file), producing the same Summary as
+// DefaultSummarizer.CalculateSummary for the same transactions.
+// Only the aggregates are kept, not the transactions: the amounts of each
+// month and week (needed for the medians) and the net amount of each day.
+//
+// Note that keeping the amounts for the medians still takes memory linear in
+// the number of transactions, 8 bytes per amount (twice with weekly
+// granularity) instead of a whole Transaction. The rest of the aggregates
+// only grow with the number of periods and days.
+// It's not safe for concurrent use.
+type IncrementalSummarizer struct {
+	// summarizer holds the configuration and the calculations shared with the batch summaries
+	summarizer *DefaultSummarizer
+
+	// currencies holds the aggregates of each currency
+	currencies map[string]*currencyAggregate
+}
+
+// currencyAggregate holds the aggregates of the transactions of a currency.
+type currencyAggregate struct {
+	count   int
+	balance transactions.Money
+	months  map[SummaryYear]map[time.Month]*periodAggregate
+	weeks   map[SummaryYear]map[SummaryWeek]*periodAggregate
+	days    map[time.Time]transactions.Money
+}
+
+// periodAggregate holds the aggregates of the transactions of a period (a month or a week).
+// The debits and credits are kept to calculate the medians, see IncrementalSummarizer.
+type periodAggregate struct {
+	count               int
+	debits, credits     []transactions.Money
+	minDebit, maxCredit transactions.Transaction
+	hasDebit, hasCredit bool
+}
+
+// NewIncrementalSummarizer creates a new IncrementalSummarizer with the default configuration.
+func NewIncrementalSummarizer() *IncrementalSummarizer {
+	return NewIncrementalSummarizerWithConfig(DefaultSummarizerConfig())
+}
+
+// NewIncrementalSummarizerWithConfig creates a new IncrementalSummarizer with
+// custom configuration, as NewDefaultSummarizerWithConfig.
+func NewIncrementalSummarizerWithConfig(config SummarizerConfig) *IncrementalSummarizer {
+	return &IncrementalSummarizer{
+		summarizer: NewDefaultSummarizerWithConfig(config),
+		currencies: make(map[string]*currencyAggregate),
+	}
+}
+
+// Add adds a transaction to the summary.
+func (is *IncrementalSummarizer) Add(txn transactions.Transaction) {
+	aggregate, ok := is.currencies[txn.Currency]
+	if !ok {
+		aggregate = &currencyAggregate{
+			months: make(map[SummaryYear]map[time.Month]*periodAggregate),
+			weeks:  make(map[SummaryYear]map[SummaryWeek]*periodAggregate),
+			days:   make(map[time.Time]transactions.Money),
+		}
+		is.currencies[txn.Currency] = aggregate
+	}
+
+	aggregate.count++
+	aggregate.balance += txn.Amount
+
+	year, month, day := txn.Date.Date()
+	aggregate.days[time.Date(year, month, day, 0, 0, 0, 0, txn.Date.Location())] += txn.Amount
+
+	summaryYear := SummaryYear(year)
+	if aggregate.months[summaryYear] == nil {
+		aggregate.months[summaryYear] = make(map[time.Month]*periodAggregate)
+	}
+	monthData := aggregate.months[summaryYear][month]
+	if monthData == nil {
+		monthData = &periodAggregate{}
+		aggregate.months[summaryYear][month] = monthData
+	}
+	is.addToPeriod(monthData, txn)
+
+	if !is.summarizer.weekly {
+		return
+	}
+
+	// The ISO year may differ from the calendar year around New Year
+	isoYear, isoWeek := txn.Date.ISOWeek()
+	weekYear, week := SummaryYear(isoYear), SummaryWeek(isoWeek)
+	if aggregate.weeks[weekYear] == nil {
+		aggregate.weeks[weekYear] = make(map[SummaryWeek]*periodAggregate)
+	}
+	weekData := aggregate.weeks[weekYear][week]
+	if weekData == nil {
+		weekData = &periodAggregate{}
+		aggregate.weeks[weekYear][week] = weekData
+	}
+	is.addToPeriod(weekData, txn)
+}
+
+// addToPeriod adds a transaction to the aggregates of its period. Extremes are
+// tracked as DefaultSummarizer.findExtremes does: on ties, the first one wins.
+func (is *IncrementalSummarizer) addToPeriod(periodData *periodAggregate, txn transactions.Transaction) {
+	periodData.count++
+	switch is.summarizer.classifier.Classify(txn.AmountFloat()) {
+	case KindDebit:
+		periodData.debits = append(periodData.debits, txn.Amount)
+		if !periodData.hasDebit || txn.Amount < periodData.minDebit.Amount {
+			periodData.minDebit, periodData.hasDebit = txn, true
+		}
+	case KindCredit:
+		periodData.credits = append(periodData.credits, txn.Amount)
+		if !periodData.hasCredit || txn.Amount > periodData.maxCredit.Amount {
+			periodData.maxCredit, periodData.hasCredit = txn, true
+		}
+	}
+}
+
+// summary calculates the aggregated data of the period.
+func (pa *periodAggregate) summary(ds *DefaultSummarizer) MonthlySummary {
+	return ds.monthlySummary(pa.count, pa.debits, pa.credits, pa.minDebit, pa.maxCredit)
+}
+
+// Finalize returns the summary of the transactions added so far.
+// Transactions of several currencies are summarized separately, into the
+// Currencies segments of the summary, as in DefaultSummarizer.CalculateSummary.
+func (is *IncrementalSummarizer) Finalize() Summary {
+	if len(is.currencies) == 0 {
+		return Summary{
+			YearlyData:    make(YearlyData),
+			WeeklyData:    is.summarizer.emptyWeeklyData(),
+			DailyBalances: make([]DailyBalance, 0),
+		}
+	}
+
+	if len(is.currencies) == 1 {
+		for currency, aggregate := range is.currencies {
+			return is.summarize(currency, aggregate)
+		}
+	}
+
+	// Amounts of different currencies can't be added up, only counted
+	summary := Summary{
+		YearlyData:    make(YearlyData),
+		WeeklyData:    is.summarizer.emptyWeeklyData(),
+		DailyBalances: make([]DailyBalance, 0),
+		Currencies:    make(map[string]Summary, len(is.currencies)),
+	}
+	for currency, aggregate := range is.currencies {
+		summary.TotalTransactionCount += aggregate.count
+		summary.Currencies[currency] = is.summarize(currency, aggregate)
+	}
+	return summary
+}
+
+// summarize calculates the summary of the aggregates of a single currency.
+func (is *IncrementalSummarizer) summarize(currency string, aggregate *currencyAggregate) Summary {
+	ds := is.summarizer
+
+	yearlyData := make(YearlyData, len(aggregate.months))
+	for year, months := range aggregate.months {
+		yearlyData[year] = make(MonthlyData, len(months))
+		for month, monthData := range months {
+			yearlyData[year][month] = monthData.summary(ds)
+		}
+	}
+
+	var weeklyData WeeklyData
+	if ds.weekly {
+		weeklyData = make(WeeklyData, len(aggregate.weeks))
+		for year, weeks := range aggregate.weeks {
+			weeklyData[year] = make(map[SummaryWeek]MonthlySummary, len(weeks))
+			for week, weekData := range weeks {
+				weeklyData[year][week] = weekData.summary(ds)
+			}
+		}
+	}
+
+	// Accumulate the running balance day by day
+	days := make([]time.Time, 0, len(aggregate.days))
+	for day := range aggregate.days {
+		days = append(days, day)
+	}
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+
+	dailyBalances := make([]DailyBalance, 0, len(days))
+	var balance transactions.Money
+	for _, day := range days {
+		balance += aggregate.days[day]
+		dailyBalances = append(dailyBalances, DailyBalance{Date: day, Balance: ds.round(balance.Float64())})
+	}
+
+	return Summary{
+		TotalBalance:          ds.round(aggregate.balance.Float64()),
+		TotalTransactionCount: aggregate.count,
+		YearlyData:            yearlyData,
+		WeeklyData:            weeklyData,
+		DailyBalances:         dailyBalances,
+		Currency:              currency,
+	}
+}
@@ -0,0 +1,144 @@
+package summaries
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/transactions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncrementalSummarizer_Finalize(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       SummarizerConfig
+		transactions []transactions.Transaction
+	}{
+		{
+			name:         "it should match the batch summary for no transactions",
+			config:       DefaultSummarizerConfig(),
+			transactions: []transactions.Transaction{},
+		},
+		{
+			name:   "it should match the batch summary for a single transaction",
+			config: DefaultSummarizerConfig(),
+			transactions: []transactions.Transaction{
+				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100_50},
+			},
+		},
+		{
+			name:   "it should match the batch summary across months and years",
+			config: DefaultSummarizerConfig(),
+			transactions: []transactions.Transaction{
+				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50},
+				{ID: 2, Date: time.Date(2024, time.January, 3, 0, 0, 0, 0, time.UTC), Amount: -10_30},
+				{ID: 3, Date: time.Date(2023, time.July, 28, 0, 0, 0, 0, time.UTC), Amount: -20_46},
+				{ID: 4, Date: time.Date(2023, time.August, 2, 0, 0, 0, 0, time.UTC), Amount: 10_00},
+				{ID: 5, Date: time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC), Amount: 15_00},
+			},
+		},
+		{
+			name:   "it should match the batch summary with outliers, medians and raw averages",
+			config: SummarizerConfig{RoundAmounts: false},
+			transactions: []transactions.Transaction{
+				{ID: 1, Date: time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC), Amount: -10_00},
+				{ID: 2, Date: time.Date(2023, time.July, 2, 0, 0, 0, 0, time.UTC), Amount: -1000_00},
+				{ID: 3, Date: time.Date(2023, time.July, 3, 0, 0, 0, 0, time.UTC), Amount: -20_00},
+				{ID: 4, Date: time.Date(2023, time.July, 4, 0, 0, 0, 0, time.UTC), Amount: 10_00},
+				{ID: 5, Date: time.Date(2023, time.July, 5, 0, 0, 0, 0, time.UTC), Amount: 25_00},
+				{ID: 6, Date: time.Date(2023, time.July, 6, 0, 0, 0, 0, time.UTC), Amount: 5000_00},
+				{ID: 7, Date: time.Date(2023, time.July, 7, 0, 0, 0, 0, time.UTC), Amount: 25_00},
+			},
+		},
+		{
+			name:   "it should match the batch summary with zero amounts and ties",
+			config: DefaultSummarizerConfig(),
+			transactions: []transactions.Transaction{
+				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 0},
+				{ID: 2, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: 100_00},
+				{ID: 3, Date: time.Date(2023, time.July, 21, 0, 0, 0, 0, time.UTC), Amount: 100_00},
+				{ID: 4, Date: time.Date(2023, time.July, 22, 0, 0, 0, 0, time.UTC), Amount: -40_00},
+				{ID: 5, Date: time.Date(2023, time.July, 23, 0, 0, 0, 0, time.UTC), Amount: -40_00},
+			},
+		},
+		{
+			name:   "it should match the batch summary with several transactions a day",
+			config: DefaultSummarizerConfig(),
+			transactions: []transactions.Transaction{
+				{ID: 1, Date: time.Date(2023, time.July, 15, 18, 0, 0, 0, time.UTC), Amount: 60_50},
+				{ID: 2, Date: time.Date(2023, time.July, 15, 9, 0, 0, 0, time.UTC), Amount: -10_30},
+				{ID: 3, Date: time.Date(2023, time.July, 14, 0, 0, 0, 0, time.UTC), Amount: 5_00},
+			},
+		},
+		{
+			name:   "it should match the batch summary with several currencies",
+			config: DefaultSummarizerConfig(),
+			transactions: []transactions.Transaction{
+				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100_00, Currency: "USD"},
+				{ID: 2, Date: time.Date(2023, time.July, 16, 0, 0, 0, 0, time.UTC), Amount: 2000_00, Currency: "MXN"},
+				{ID: 3, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: -40_00, Currency: "USD"},
+			},
+		},
+		{
+			name:   "it should match the batch summary with weekly data",
+			config: SummarizerConfig{RoundAmounts: true, Granularity: GranularityMonthly | GranularityWeekly},
+			transactions: []transactions.Transaction{
+				{ID: 1, Date: time.Date(2024, time.December, 29, 0, 0, 0, 0, time.UTC), Amount: 50_00},
+				{ID: 2, Date: time.Date(2024, time.December, 30, 0, 0, 0, 0, time.UTC), Amount: -20_00},
+				{ID: 3, Date: time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC), Amount: 100_00, Currency: "USD"},
+				{ID: 4, Date: time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC), Amount: -5_50},
+			},
+		},
+		{
+			name:         "it should match the batch summary with weekly data and no transactions",
+			config:       SummarizerConfig{Granularity: GranularityWeekly},
+			transactions: []transactions.Transaction{},
+		},
+		{
+			name:   "it should match the batch summary with a custom classifier",
+			config: SummarizerConfig{Classifier: &zeroAsCreditClassifier{}, RoundAmounts: true},
+			transactions: []transactions.Transaction{
+				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 0},
+				{ID: 2, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: 100_00},
+				{ID: 3, Date: time.Date(2023, time.July, 25, 0, 0, 0, 0, time.UTC), Amount: -40_00},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			expected := NewDefaultSummarizerWithConfig(tt.config).CalculateSummary(context.Background(), tt.transactions)
+			summarizer := NewIncrementalSummarizerWithConfig(tt.config)
+
+			// Act
+			for _, txn := range tt.transactions {
+				summarizer.Add(txn)
+			}
+			actual := summarizer.Finalize()
+
+			// Assert
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestIncrementalSummarizer_Add(t *testing.T) {
+	t.Run("it should keep adding transactions after finalizing", func(t *testing.T) {
+		// Arrange
+		summarizer := NewIncrementalSummarizer()
+		summarizer.Add(transactions.Transaction{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50})
+		first := summarizer.Finalize()
+
+		// Act
+		summarizer.Add(transactions.Transaction{ID: 2, Date: time.Date(2023, time.July, 16, 0, 0, 0, 0, time.UTC), Amount: -10_30})
+		second := summarizer.Finalize()
+
+		// Assert
+		assert.Equal(t, 60.5, first.TotalBalance)
+		assert.Equal(t, 50.2, second.TotalBalance)
+		assert.Equal(t, 2, second.TotalTransactionCount)
+	})
+}
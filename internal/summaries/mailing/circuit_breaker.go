@@ -0,0 +1,125 @@
+package mailing
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitState is the state of a circuitBreaker
+type circuitState int
+
+const (
+	// circuitClosed lets every attempt through
+	circuitClosed circuitState = iota
+
+	// circuitOpen fails every attempt fast until the cool-down is over
+	circuitOpen
+
+	// circuitHalfOpen lets a single trial attempt through: its success closes
+	// the circuit, and its failure opens it again
+	circuitHalfOpen
+)
+
+// circuitBreaker stops dialing an SMTP server that keeps failing, so that
+// invocations fail fast instead of waiting for every attempt to time out.
+// It opens after a number of consecutive failures, and half-opens once the
+// cool-down is over to find out whether the server is back.
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	// threshold is the number of consecutive failures that opens the circuit
+	threshold int
+
+	// coolDown is how long the circuit stays open before half-opening
+	coolDown time.Duration
+
+	// now returns the current time, replaced in tests
+	now func() time.Time
+
+	failures int
+	openedAt time.Time
+	open     bool
+
+	// trial indicates that a half-open trial attempt is in flight
+	trial bool
+}
+
+// newCircuitBreaker creates a closed circuitBreaker.
+func newCircuitBreaker(threshold int, coolDown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		coolDown:  coolDown,
+		now:       time.Now,
+	}
+}
+
+// state returns the current state of the circuit.
+func (cb *circuitBreaker) state() circuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.stateLocked()
+}
+
+func (cb *circuitBreaker) stateLocked() circuitState {
+	switch {
+	case !cb.open:
+		return circuitClosed
+	case cb.now().Sub(cb.openedAt) < cb.coolDown:
+		return circuitOpen
+	default:
+		return circuitHalfOpen
+	}
+}
+
+// allow reports whether an attempt may go through, returning ErrCircuitOpen
+// when the circuit is open or a half-open trial is already in flight, and
+// whether the attempt is the half-open trial. Every allowed attempt must be
+// followed by a call to done with that trial flag.
+func (cb *circuitBreaker) allow() (trial bool, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.stateLocked() {
+	case circuitOpen:
+		return false, ErrCircuitOpen
+	case circuitHalfOpen:
+		if cb.trial {
+			return false, ErrCircuitOpen
+		}
+		cb.trial = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// done records the outcome of an allowed attempt. Only failures that suggest
+// the server is unavailable count towards opening the circuit; a permanent
+// failure (e.g. a rejected authentication) means the server is up.
+// An attempt abandoned before it got an answer (e.g. on cancellation) only
+// releases the half-open trial. Only the trial attempt itself releases it, so
+// attempts allowed before the circuit opened don't let a second trial through.
+func (cb *circuitBreaker) done(trial, failed, abandoned bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if trial {
+		cb.trial = false
+	}
+
+	switch {
+	case abandoned:
+		return
+	case !failed:
+		cb.failures = 0
+		cb.open = false
+	case trial:
+		// The server is still down, so start a new cool-down right away
+		cb.openedAt = cb.now()
+	default:
+		cb.failures++
+		if !cb.open && cb.failures >= cb.threshold {
+			cb.open = true
+			cb.openedAt = cb.now()
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package mailing
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"stori-challenge/internal/summaries"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed email_template.html email_template.txt
+var emailTemplateFS embed.FS
+
+const (
+	// LocaleSpanish renders emails in Spanish (default)
+	LocaleSpanish = "es"
+
+	// LocaleEnglish renders emails in English
+	LocaleEnglish = "en"
+)
+
+// subjects holds the email subject for each supported locale
+var subjects = map[string]string{
+	LocaleSpanish: "Resumen de Transacciones - Stori",
+	LocaleEnglish: "Transaction Summary - Stori",
+}
+
+// labels holds the text of the email bodies for each supported locale,
+// rendered with the label template function
+var labels = map[string]map[string]string{
+	LocaleSpanish: {
+		"title":         "Resumen de Transacciones",
+		"totalBalance":  "Saldo total",
+		"transactions":  "transacciones",
+		"averageDebit":  "Débito promedio",
+		"averageCredit": "Crédito promedio",
+		"noActivity":    "Sin movimientos en el período.",
+	},
+	LocaleEnglish: {
+		"title":         "Transaction Summary",
+		"totalBalance":  "Total balance",
+		"transactions":  "transactions",
+		"averageDebit":  "Average debit",
+		"averageCredit": "Average credit",
+		"noActivity":    "No activity in the period.",
+	},
+}
+
+// spanishMonthNames holds the Spanish name of each month
+var spanishMonthNames = map[time.Month]string{
+	time.January:   "Enero",
+	time.February:  "Febrero",
+	time.March:     "Marzo",
+	time.April:     "Abril",
+	time.May:       "Mayo",
+	time.June:      "Junio",
+	time.July:      "Julio",
+	time.August:    "Agosto",
+	time.September: "Septiembre",
+	time.October:   "Octubre",
+	time.November:  "Noviembre",
+	time.December:  "Diciembre",
+}
+
+// emailRenderer renders the subject and the bodies of the summary emails,
+// shared by the mailers so that every channel sends the same email.
+type emailRenderer struct {
+	// locale is the language of the email, LocaleSpanish or LocaleEnglish
+	locale string
+
+	// subjectTemplate is a text/template for the subject, empty for the
+	// localized default subject
+	subjectTemplate string
+
+	// htmlTemplate is a custom HTML template, nil for the embedded one
+	htmlTemplate *template.Template
+
+	// now returns the current time, stamped on the emails as their generation
+	// time. It's replaced in tests to render deterministic emails.
+	now func() time.Time
+}
+
+// newEmailRenderer creates an emailRenderer. Unsupported locales fall back to
+// Spanish, and a nil template keeps the embedded one.
+func newEmailRenderer(locale, subjectTemplate string, tmpl *template.Template) *emailRenderer {
+	if _, ok := subjects[locale]; !ok {
+		locale = LocaleSpanish
+	}
+
+	return &emailRenderer{
+		locale:          locale,
+		subjectTemplate: subjectTemplate,
+		htmlTemplate:    tmpl,
+		now:             time.Now,
+	}
+}
+
+// ParseTemplate parses a custom HTML email template, making the functions of
+// the embedded template (monthName, label, formatAmount, ...) available to it
+func ParseTemplate(text string) (*template.Template, error) {
+	defaults := &emailRenderer{locale: LocaleSpanish}
+	return template.New("email").Funcs(defaults.templateFuncs()).Parse(text)
+}
+
+// templateFuncs returns the custom functions available to the email templates
+func (r *emailRenderer) templateFuncs() map[string]any {
+	return map[string]any{
+		"monthName": r.monthName,
+		"label":     r.label,
+		"locale": func() string {
+			return r.locale
+		},
+		"hasDebit": func(value float64) bool {
+			return value != 0.0
+		},
+		"hasCredit": func(value float64) bool {
+			return value != 0.0
+		},
+		"formatAmount": func(value float64) string {
+			return fmt.Sprintf("%.2f", value)
+		},
+	}
+}
+
+// monthName returns the name of the month in the configured locale
+func (r *emailRenderer) monthName(month time.Month) string {
+	if r.locale == LocaleEnglish {
+		return month.String()
+	}
+	return spanishMonthNames[month]
+}
+
+// label returns the text of an email body label in the configured locale,
+// or the key itself when the label is unknown
+func (r *emailRenderer) label(key string) string {
+	if text, ok := labels[r.locale][key]; ok {
+		return text
+	}
+	return key
+}
+
+// templateData returns the data passed to the email templates
+func (r *emailRenderer) templateData(summary summaries.Summary) any {
+	return struct {
+		summaries.Summary
+		GeneratedAt string
+	}{
+		Summary:     summary,
+		GeneratedAt: r.now().Format("2006-01-02 15:04:05"),
+	}
+}
+
+// subjectData holds the data available to the subject template
+type subjectData struct {
+	summaries.Summary
+
+	// Recipient is the email address the summary is sent to
+	Recipient string
+
+	// PeriodStart and PeriodEnd are the first and last days with transactions,
+	// zero for an empty summary
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+}
+
+// generateSubject generates the email subject from the configured subject
+// template, falling back to the localized default subject when unset
+func (r *emailRenderer) generateSubject(to string, summary summaries.Summary) (string, error) {
+	if r.subjectTemplate == "" {
+		return subjects[r.locale], nil
+	}
+
+	// Create template with custom functions
+	t := texttemplate.New("subject").Funcs(r.templateFuncs())
+
+	// Parse template
+	t, err := t.Parse(r.subjectTemplate)
+	if err != nil {
+		return "", fmt.Errorf("error parsing subject template: %w", err)
+	}
+
+	// Prepare data for template
+	data := subjectData{
+		Summary:   summary,
+		Recipient: to,
+	}
+	if len(summary.DailyBalances) > 0 {
+		data.PeriodStart = summary.DailyBalances[0].Date
+		data.PeriodEnd = summary.DailyBalances[len(summary.DailyBalances)-1].Date
+	}
+
+	// Execute template
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("error executing subject template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// RenderHTML renders the HTML body of the summary email, as sent by Send
+func (r *emailRenderer) RenderHTML(summary summaries.Summary) (string, error) {
+	t, err := r.htmlBodyTemplate()
+	if err != nil {
+		return "", err
+	}
+
+	// Execute template
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, r.templateData(summary)); err != nil {
+		return "", fmt.Errorf("error executing template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// htmlBodyTemplate returns the HTML template bound to the mailer's functions,
+// either the custom one or the embedded one
+func (r *emailRenderer) htmlBodyTemplate() (*template.Template, error) {
+	if r.htmlTemplate != nil {
+		// Clone the custom template to bind the functions of the configured locale
+		t, err := r.htmlTemplate.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("error cloning template: %w", err)
+		}
+		return t.Funcs(r.templateFuncs()), nil
+	}
+
+	// Read template from embedded file
+	templateContent, err := emailTemplateFS.ReadFile("email_template.html")
+	if err != nil {
+		return nil, fmt.Errorf("error reading email template: %w", err)
+	}
+
+	// Create template with custom functions
+	t := template.New("email").Funcs(r.templateFuncs())
+
+	// Parse template
+	t, err = t.Parse(string(templateContent))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template: %w", err)
+	}
+
+	return t, nil
+}
+
+// RenderPlain renders the plain text body of the summary email, as sent by
+// Send for clients that don't render HTML
+func (r *emailRenderer) RenderPlain(summary summaries.Summary) (string, error) {
+	// Read template from embedded file
+	templateContent, err := emailTemplateFS.ReadFile("email_template.txt")
+	if err != nil {
+		return "", fmt.Errorf("error reading plain text template: %w", err)
+	}
+
+	// Create template with custom functions
+	t := texttemplate.New("email").Funcs(r.templateFuncs())
+
+	// Parse template
+	t, err = t.Parse(string(templateContent))
+	if err != nil {
+		return "", fmt.Errorf("error parsing plain text template: %w", err)
+	}
+
+	// Execute template
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, r.templateData(summary)); err != nil {
+		return "", fmt.Errorf("error executing plain text template: %w", err)
+	}
+
+	return buf.String(), nil
+}
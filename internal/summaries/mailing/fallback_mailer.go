@@ -0,0 +1,47 @@
+package mailing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"stori-challenge/internal/summaries"
+)
+
+// FallbackMailer implements Mailer by trying several mailers in order (e.g.
+// SES, then SMTP), until one of them sends the email.
+type FallbackMailer struct {
+	mailers []Mailer
+}
+
+// NewFallbackMailer creates a new FallbackMailer trying the given mailers in order.
+func NewFallbackMailer(mailers ...Mailer) *FallbackMailer {
+	return &FallbackMailer{mailers: mailers}
+}
+
+// Send sends the email with the first mailer that succeeds. When every mailer
+// fails, the returned error joins the failure of each one (see errors.Is).
+// An invalid recipient is returned right away, as no other mailer would send
+// to it either, and no further mailer is tried once the context is done.
+func (f *FallbackMailer) Send(ctx context.Context, to string, summary summaries.Summary, opts ...SendOption) error {
+	if len(f.mailers) == 0 {
+		return ErrNoMailers
+	}
+
+	var errs []error
+	for i, mailer := range f.mailers {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+
+		err := mailer.Send(ctx, to, summary, opts...)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, ErrInvalidRecipient) {
+			return err
+		}
+		errs = append(errs, fmt.Errorf("mailer %d of %d: %w", i+1, len(f.mailers), err))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,105 @@
+package mailing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"stori-challenge/internal/summaries"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMailer counts the emails it's asked to send, returning err.
+type fakeMailer struct {
+	sent   int
+	err    error
+	onSend func()
+}
+
+func (m *fakeMailer) Send(_ context.Context, _ string, _ summaries.Summary, _ ...SendOption) error {
+	m.sent++
+	if m.onSend != nil {
+		m.onSend()
+	}
+	return m.err
+}
+
+func TestFallbackMailer_Send(t *testing.T) {
+	summary := summaries.Summary{TotalBalance: 39.74, YearlyData: summaries.YearlyData{}}
+
+	t.Run("it should fall back to the next mailer when one fails", func(t *testing.T) {
+		// Arrange
+		throttled := &fakeMailer{err: errors.New("TooManyRequestsException: Maximum sending rate exceeded")}
+		smtp := &fakeMailer{}
+		unused := &fakeMailer{}
+		mailer := NewFallbackMailer(throttled, smtp, unused)
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, throttled.sent)
+		assert.Equal(t, 1, smtp.sent)
+		assert.Equal(t, 0, unused.sent, "should stop at the first mailer that succeeds")
+	})
+
+	t.Run("it should return the errors of every mailer when all fail", func(t *testing.T) {
+		// Arrange
+		sesErr := errors.New("TooManyRequestsException: Maximum sending rate exceeded")
+		smtpErr := fmt.Errorf("giving up after 3 attempts: %w", ErrCircuitOpen)
+		mailer := NewFallbackMailer(&fakeMailer{err: sesErr}, &fakeMailer{err: smtpErr})
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		require.Error(t, err)
+		assert.ErrorIs(t, err, sesErr)
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.ErrorContains(t, err, "mailer 1 of 2: TooManyRequestsException")
+		assert.ErrorContains(t, err, "mailer 2 of 2: giving up after 3 attempts")
+	})
+
+	t.Run("it should not try further mailers once the context is done", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		sesErr := errors.New("RequestTimeout")
+		first := &fakeMailer{err: sesErr, onSend: cancel}
+		second := &fakeMailer{}
+		mailer := NewFallbackMailer(first, second)
+
+		// Act
+		err := mailer.Send(ctx, "user@example.com", summary)
+
+		// Assert
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, err, sesErr)
+		assert.Equal(t, 0, second.sent)
+	})
+
+	t.Run("it should not fall back on an invalid recipient", func(t *testing.T) {
+		// Arrange
+		first := &fakeMailer{err: fmt.Errorf("%w %q", ErrInvalidRecipient, "not-an-email")}
+		second := &fakeMailer{}
+		mailer := NewFallbackMailer(first, second)
+
+		// Act
+		err := mailer.Send(context.Background(), "not-an-email", summary)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrInvalidRecipient)
+		assert.Equal(t, 0, second.sent)
+	})
+
+	t.Run("it should fail without mailers", func(t *testing.T) {
+		// Act
+		err := NewFallbackMailer().Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrNoMailers)
+	})
+}
@@ -0,0 +1,108 @@
+package mailing
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"stori-challenge/internal/summaries"
+	"strings"
+)
+
+// fileMailerTimeFormat is the format of the timestamp in the file names,
+// precise enough for emails saved in quick succession not to collide
+const fileMailerTimeFormat = "20060102T150405.000000000"
+
+// FileMailerConfig holds the configuration of a FileMailer.
+type FileMailerConfig struct {
+	// Dir is the directory the emails are written to, created when missing
+	Dir string
+
+	// Locale is the language of the email, LocaleSpanish or LocaleEnglish.
+	// Unsupported values fall back to the default (default: "es")
+	Locale string
+}
+
+// FileMailer implements Mailer by writing the rendered HTML body of the
+// emails to disk instead of sending them, to inspect them during local
+// development and QA. Each email is written to
+// "<dir>/<recipient>_<timestamp>.html", and its attachments next to it as
+// "<dir>/<recipient>_<timestamp>_<filename>".
+type FileMailer struct {
+	*emailRenderer
+
+	config FileMailerConfig
+}
+
+// NewFileMailer creates a new FileMailer that writes the emails to the given
+// directory, rendering the embedded HTML template
+func NewFileMailer(dir string) *FileMailer {
+	return NewFileMailerWithConfig(FileMailerConfig{Dir: dir}, nil)
+}
+
+// NewFileMailerWithConfig creates a new FileMailer with custom configuration
+// that renders the given HTML template instead of the embedded one (nil keeps
+// the embedded template), parsed with ParseTemplate.
+func NewFileMailerWithConfig(config FileMailerConfig, tmpl *template.Template) *FileMailer {
+	if _, ok := subjects[config.Locale]; !ok {
+		config.Locale = LocaleSpanish
+	}
+
+	return &FileMailer{
+		emailRenderer: newEmailRenderer(config.Locale, "", tmpl),
+		config:        config,
+	}
+}
+
+// Send writes the HTML body of the email with the transaction summary to the
+// configured directory, along with the attachments given with WithAttachments
+func (f *FileMailer) Send(ctx context.Context, to string, summary summaries.Summary, opts ...SendOption) error {
+	// Validate the recipient before doing any work
+	if _, err := mail.ParseAddress(to); err != nil {
+		return fmt.Errorf("%w %q: %v", ErrInvalidRecipient, to, err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	htmlBody, err := f.RenderHTML(summary)
+	if err != nil {
+		return fmt.Errorf("error generating HTML body: %w", err)
+	}
+
+	if err := os.MkdirAll(f.config.Dir, 0o755); err != nil {
+		return fmt.Errorf("error creating email directory: %w", err)
+	}
+
+	base := filepath.Join(f.config.Dir, sanitizeFileName(to)+"_"+f.now().UTC().Format(fileMailerTimeFormat))
+	if err := os.WriteFile(base+".html", []byte(htmlBody), 0o644); err != nil {
+		return fmt.Errorf("error writing email: %w", err)
+	}
+
+	for _, attachment := range NewSendOptions(opts...).Attachments {
+		name := base + "_" + sanitizeFileName(filepath.Base(attachment.Filename))
+		if err := os.WriteFile(name, attachment.Content, 0o644); err != nil {
+			return fmt.Errorf("error writing attachment %q: %w", attachment.Filename, err)
+		}
+	}
+
+	return nil
+}
+
+// sanitizeFileName replaces the characters that aren't safe in file names
+// (e.g. path separators) with underscores
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == '@' || r == '.' || r == '-' || r == '_' || r == '+':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
@@ -0,0 +1,127 @@
+package mailing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"stori-challenge/internal/summaries"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer_Send(t *testing.T) {
+	summary := summaries.Summary{
+		TotalBalance: 39.74,
+		YearlyData: summaries.YearlyData{
+			2024: summaries.MonthlyData{
+				time.July: {TransactionCount: 2, AverageDebit: -10.3, AverageCredit: 60.5},
+			},
+		},
+	}
+	frozenNow := func() time.Time { return time.Date(2003, time.May, 1, 13, 45, 30, 123, time.UTC) }
+
+	t.Run("it should write the rendered HTML named by recipient and timestamp", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		mailer := NewFileMailer(dir)
+		mailer.now = frozenNow
+		expected, err := mailer.RenderHTML(summary)
+		require.NoError(t, err)
+
+		// Act
+		err = mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(dir, "user@example.com_20030501T134530.000000123.html"))
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(content))
+		assert.Contains(t, string(content), "$39.74")
+		assert.Contains(t, string(content), "Julio")
+	})
+
+	t.Run("it should create the directory when missing", func(t *testing.T) {
+		// Arrange
+		dir := filepath.Join(t.TempDir(), "emails", "qa")
+		mailer := NewFileMailer(dir)
+		mailer.now = frozenNow
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		require.NoError(t, err)
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "user@example.com_20030501T134530.000000123.html", entries[0].Name())
+	})
+
+	t.Run("it should write the attachments next to the email", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		mailer := NewFileMailer(dir)
+		mailer.now = frozenNow
+		statement := []byte("Id,Date,Transaction\n0,7/15,+60.5\n")
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary, WithAttachments(
+			Attachment{Filename: "statement.csv", ContentType: "text/csv", Content: statement},
+		))
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(dir, "user@example.com_20030501T134530.000000123_statement.csv"))
+		require.NoError(t, err)
+		assert.Equal(t, statement, content)
+	})
+
+	t.Run("it should not let the recipient escape the directory", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		mailer := NewFileMailer(dir)
+		mailer.now = frozenNow
+
+		// Act
+		err := mailer.Send(context.Background(), `"../../etc/x"@example.com`, summary)
+
+		// Assert
+		require.NoError(t, err)
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "_.._.._etc_x_@example.com_20030501T134530.000000123.html", entries[0].Name())
+	})
+
+	t.Run("it should render the configured locale", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		mailer := NewFileMailerWithConfig(FileMailerConfig{Dir: dir, Locale: LocaleEnglish}, nil)
+		mailer.now = frozenNow
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(dir, "user@example.com_20030501T134530.000000123.html"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "July")
+	})
+
+	t.Run("it should reject an invalid recipient without writing", func(t *testing.T) {
+		// Arrange
+		dir := filepath.Join(t.TempDir(), "emails")
+		mailer := NewFileMailer(dir)
+
+		// Act
+		err := mailer.Send(context.Background(), "not-an-email", summary)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrInvalidRecipient)
+		assert.NoDirExists(t, dir)
+	})
+}
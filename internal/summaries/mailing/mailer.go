@@ -2,9 +2,58 @@ package mailing
 
 import (
 	"context"
+	"errors"
 	"stori-challenge/internal/summaries"
 )
 
+// ErrInvalidRecipient is returned when the recipient email address is malformed.
+var ErrInvalidRecipient = errors.New("invalid recipient")
+
+// ErrCircuitOpen is returned when the SMTP server isn't dialed because it
+// failed repeatedly and the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// ErrNoMailers is returned when a FallbackMailer has no mailers to send with.
+var ErrNoMailers = errors.New("no mailers configured")
+
 type Mailer interface {
-	Send(ctx context.Context, to string, summary summaries.Summary) error
+	Send(ctx context.Context, to string, summary summaries.Summary, opts ...SendOption) error
+}
+
+// Attachment is a file attached to an email, e.g. the original statement.
+type Attachment struct {
+	// Filename is the name of the attached file, e.g. "statement.csv"
+	Filename string
+
+	// ContentType is the MIME type of the file. When empty, it's guessed from
+	// the filename extension, falling back to "application/octet-stream"
+	ContentType string
+
+	// Content is the content of the file
+	Content []byte
+}
+
+// SendOptions holds the options of a single email, see SendOption.
+type SendOptions struct {
+	// Attachments are the files attached to the email
+	Attachments []Attachment
+}
+
+// SendOption customizes a single email.
+type SendOption func(*SendOptions)
+
+// WithAttachments attaches the given files to the email.
+func WithAttachments(attachments ...Attachment) SendOption {
+	return func(options *SendOptions) {
+		options.Attachments = append(options.Attachments, attachments...)
+	}
+}
+
+// NewSendOptions applies the given options over the defaults (no attachments).
+func NewSendOptions(opts ...SendOption) SendOptions {
+	var options SendOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
 }
@@ -0,0 +1,95 @@
+package mailing
+
+import (
+	"io"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/go-gomail/gomail"
+)
+
+// plainDialTimeout is how long dialing the SMTP server may take, as gomail does
+const plainDialTimeout = 10 * time.Second
+
+// plainDialer dials an SMTP server in plain text for TLSModeNone. Unlike
+// gomail.Dialer, it never upgrades the connection with STARTTLS, even when the
+// server advertises it.
+//
+// Credentials are sent with PLAIN authentication when the server supports
+// AUTH. Note that net/smtp refuses to send them over an unencrypted connection
+// to any host but localhost.
+type plainDialer struct {
+	host     string
+	port     int
+	username string
+	password string
+}
+
+// newPlainDialer creates a plainDialer for the configured server.
+func newPlainDialer(config SMTPConfig) *plainDialer {
+	return &plainDialer{
+		host:     config.Host,
+		port:     config.Port,
+		username: config.Username,
+		password: config.Password,
+	}
+}
+
+// Dial connects and authenticates to the SMTP server.
+func (d *plainDialer) Dial() (gomail.SendCloser, error) {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(d.host, strconv.Itoa(d.port)), plainDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := smtp.NewClient(conn, d.host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if d.username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(smtp.PlainAuth("", d.username, d.password, d.host)); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+
+	return &plainSender{client: client}, nil
+}
+
+// plainSender is the gomail.SendCloser of a plainDialer connection.
+type plainSender struct {
+	client *smtp.Client
+}
+
+// Send sends a message to the given recipients.
+func (s *plainSender) Send(from string, to []string, msg io.WriterTo) error {
+	if err := s.client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := s.client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	w, err := s.client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := msg.WriteTo(w); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// Close ends the SMTP session with QUIT.
+func (s *plainSender) Close() error {
+	return s.client.Quit()
+}
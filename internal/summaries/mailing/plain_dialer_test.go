@@ -0,0 +1,127 @@
+package mailing
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-gomail/gomail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer is a minimal SMTP server that advertises STARTTLS and records
+// the commands it receives, without ever upgrading the connection.
+type fakeSMTPServer struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	commands []string
+	data     string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fakeSMTPServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go server.serve()
+	return server
+}
+
+func (s *fakeSMTPServer) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.commands = append(s.commands, line)
+		s.mu.Unlock()
+
+		switch verb := strings.ToUpper(strings.Fields(line)[0]); verb {
+		case "EHLO":
+			text.PrintfLine("250-localhost")
+			text.PrintfLine("250 STARTTLS")
+		case "STARTTLS":
+			text.PrintfLine("454 TLS not available")
+		case "DATA":
+			text.PrintfLine("354 Go ahead")
+			data, _ := text.ReadDotBytes()
+			s.mu.Lock()
+			s.data = string(data)
+			s.mu.Unlock()
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("250 OK")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) received() ([]string, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...), s.data
+}
+
+func TestPlainDialer(t *testing.T) {
+	t.Run("it should send without upgrading the connection", func(t *testing.T) {
+		// Arrange
+		server := newFakeSMTPServer(t)
+		dialer := newPlainDialer(SMTPConfig{Host: "127.0.0.1", Port: server.port()})
+		message := gomail.NewMessage()
+		message.SetHeader("From", "noreply@example.com")
+		message.SetHeader("To", "user@example.com")
+		message.SetBody("text/plain", "Hello")
+
+		// Act
+		conn, err := dialer.Dial()
+		require.NoError(t, err)
+		sendErr := gomail.Send(conn, message)
+		closeErr := conn.Close()
+
+		// Assert
+		require.NoError(t, sendErr)
+		require.NoError(t, closeErr)
+		commands, data := server.received()
+		for _, command := range commands {
+			assert.NotEqual(t, "STARTTLS", strings.ToUpper(command), "should not upgrade with STARTTLS")
+		}
+		assert.Contains(t, commands, "MAIL FROM:<noreply@example.com>")
+		assert.Contains(t, commands, "RCPT TO:<user@example.com>")
+		assert.Contains(t, data, "Hello")
+	})
+
+	t.Run("it should fail when the server is unreachable", func(t *testing.T) {
+		// Arrange
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		port := listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+		dialer := newPlainDialer(SMTPConfig{Host: "127.0.0.1", Port: port})
+
+		// Act
+		_, err = dialer.Dial()
+
+		// Assert
+		assert.True(t, isTransientSMTPError(err), "should be retried: %v", err)
+	})
+}
@@ -0,0 +1,141 @@
+package mailing
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/mail"
+	"path/filepath"
+	"stori-challenge/internal/summaries"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+// sesCharset is the charset of the subject and the bodies sent to SES
+const sesCharset = "UTF-8"
+
+// sesClient abstracts the SES operations used by the mailer so the client
+// can be replaced in tests.
+type sesClient interface {
+	SendEmail(ctx context.Context, params *sesv2.SendEmailInput, optFns ...func(*sesv2.Options)) (*sesv2.SendEmailOutput, error)
+}
+
+// SESConfig holds the configuration of an SESMailer.
+type SESConfig struct {
+	// From is the sender address, which must be a verified SES identity
+	From string
+
+	// Locale is the language of the email, LocaleSpanish or LocaleEnglish.
+	// Unsupported values fall back to the default (default: "es")
+	Locale string
+
+	// SubjectTemplate is a text/template for the email subject, as in
+	// SMTPConfig (default: the localized "Resumen de Transacciones - Stori")
+	SubjectTemplate string
+
+	// ConfigurationSetName is the SES configuration set used to send the
+	// emails, e.g. to publish delivery events (default: none)
+	ConfigurationSetName string
+}
+
+// SESMailer implements Mailer using Amazon SES, sending the same email as
+// SMTPMailer. Retries are left to the SES client.
+type SESMailer struct {
+	*emailRenderer
+
+	client sesClient
+	config SESConfig
+}
+
+// NewSESMailer creates a new SESMailer that sends the emails from the given
+// address and renders the embedded HTML template
+func NewSESMailer(client *sesv2.Client, from string) *SESMailer {
+	return NewSESMailerWithConfig(client, SESConfig{From: from}, nil)
+}
+
+// NewSESMailerWithConfig creates a new SESMailer with custom configuration
+// that renders the given HTML template instead of the embedded one (nil keeps
+// the embedded template), parsed with ParseTemplate.
+func NewSESMailerWithConfig(client *sesv2.Client, config SESConfig, tmpl *template.Template) *SESMailer {
+	return newSESMailer(client, config, tmpl)
+}
+
+// newSESMailer creates a mailer on top of any sesClient.
+func newSESMailer(client sesClient, config SESConfig, tmpl *template.Template) *SESMailer {
+	if _, ok := subjects[config.Locale]; !ok {
+		config.Locale = LocaleSpanish
+	}
+
+	return &SESMailer{
+		emailRenderer: newEmailRenderer(config.Locale, config.SubjectTemplate, tmpl),
+		client:        client,
+		config:        config,
+	}
+}
+
+// Send sends an email with the transaction summary, along with the attachments
+// given with WithAttachments
+func (s *SESMailer) Send(ctx context.Context, to string, summary summaries.Summary, opts ...SendOption) error {
+	// Validate the recipient before doing any work
+	if _, err := mail.ParseAddress(to); err != nil {
+		return fmt.Errorf("%w %q: %v", ErrInvalidRecipient, to, err)
+	}
+
+	subject, err := s.generateSubject(to, summary)
+	if err != nil {
+		return fmt.Errorf("error generating subject: %w", err)
+	}
+
+	htmlBody, err := s.RenderHTML(summary)
+	if err != nil {
+		return fmt.Errorf("error generating HTML body: %w", err)
+	}
+
+	plainBody, err := s.RenderPlain(summary)
+	if err != nil {
+		return fmt.Errorf("error generating plain text body: %w", err)
+	}
+
+	message := &types.Message{
+		Subject: &types.Content{Data: aws.String(subject), Charset: aws.String(sesCharset)},
+		Body: &types.Body{
+			Text: &types.Content{Data: aws.String(plainBody), Charset: aws.String(sesCharset)},
+			Html: &types.Content{Data: aws.String(htmlBody), Charset: aws.String(sesCharset)},
+		},
+	}
+	for _, attachment := range NewSendOptions(opts...).Attachments {
+		message.Attachments = append(message.Attachments, sesAttachment(attachment))
+	}
+
+	input := &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(s.config.From),
+		Destination:      &types.Destination{ToAddresses: []string{to}},
+		Content:          &types.EmailContent{Simple: message},
+	}
+	if s.config.ConfigurationSetName != "" {
+		input.ConfigurationSetName = aws.String(s.config.ConfigurationSetName)
+	}
+
+	if _, err := s.client.SendEmail(ctx, input); err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+
+	return nil
+}
+
+// sesAttachment converts an attachment to its SES representation. An empty
+// content type is left for SES to guess from the filename.
+func sesAttachment(attachment Attachment) types.Attachment {
+	converted := types.Attachment{
+		FileName:           aws.String(filepath.Base(attachment.Filename)),
+		RawContent:         attachment.Content,
+		ContentDisposition: types.AttachmentContentDispositionAttachment,
+	}
+	if attachment.ContentType != "" {
+		converted.ContentType = aws.String(attachment.ContentType)
+	}
+
+	return converted
+}
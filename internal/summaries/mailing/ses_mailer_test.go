@@ -0,0 +1,146 @@
+package mailing
+
+import (
+	"context"
+	"errors"
+	"stori-challenge/internal/summaries"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSESClient records the SendEmail inputs, returning err.
+type fakeSESClient struct {
+	inputs []*sesv2.SendEmailInput
+	err    error
+}
+
+func (c *fakeSESClient) SendEmail(_ context.Context, params *sesv2.SendEmailInput, _ ...func(*sesv2.Options)) (*sesv2.SendEmailOutput, error) {
+	c.inputs = append(c.inputs, params)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &sesv2.SendEmailOutput{MessageId: aws.String("message-id")}, nil
+}
+
+func TestSESMailer_Send(t *testing.T) {
+	summary := summaries.Summary{
+		TotalBalance: 39.74,
+		YearlyData: summaries.YearlyData{
+			2024: summaries.MonthlyData{
+				time.July: {TransactionCount: 2, AverageDebit: -10.3, AverageCredit: 60.5},
+			},
+		},
+	}
+
+	t.Run("it should send the rendered bodies to the recipient", func(t *testing.T) {
+		// Arrange
+		client := &fakeSESClient{}
+		mailer := newSESMailer(client, SESConfig{From: "noreply@example.com"}, nil)
+		mailer.now = func() time.Time { return time.Date(2003, time.May, 1, 0, 0, 0, 0, time.UTC) }
+		expectedHTML, _ := mailer.RenderHTML(summary)
+		expectedPlain, _ := mailer.RenderPlain(summary)
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, client.inputs, 1)
+		input := client.inputs[0]
+		assert.Equal(t, "noreply@example.com", aws.ToString(input.FromEmailAddress))
+		assert.Equal(t, []string{"user@example.com"}, input.Destination.ToAddresses)
+		assert.Nil(t, input.ConfigurationSetName)
+
+		message := input.Content.Simple
+		require.NotNil(t, message)
+		assert.Equal(t, "Resumen de Transacciones - Stori", aws.ToString(message.Subject.Data))
+		assert.Equal(t, expectedHTML, aws.ToString(message.Body.Html.Data))
+		assert.Contains(t, aws.ToString(message.Body.Html.Data), "$39.74")
+		assert.Contains(t, aws.ToString(message.Body.Html.Data), "Julio")
+		assert.Equal(t, expectedPlain, aws.ToString(message.Body.Text.Data))
+		assert.Equal(t, "UTF-8", aws.ToString(message.Body.Html.Charset))
+		assert.Empty(t, message.Attachments)
+	})
+
+	t.Run("it should render the configured locale and subject", func(t *testing.T) {
+		// Arrange
+		client := &fakeSESClient{}
+		mailer := newSESMailer(client, SESConfig{
+			From:                 "noreply@example.com",
+			Locale:               LocaleEnglish,
+			SubjectTemplate:      "Statement for {{.Recipient}}",
+			ConfigurationSetName: "statements",
+		}, nil)
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		require.NoError(t, err)
+		message := client.inputs[0].Content.Simple
+		assert.Equal(t, "Statement for user@example.com", aws.ToString(message.Subject.Data))
+		assert.Contains(t, aws.ToString(message.Body.Html.Data), "July")
+		assert.Equal(t, "statements", aws.ToString(client.inputs[0].ConfigurationSetName))
+	})
+
+	t.Run("it should attach the files", func(t *testing.T) {
+		// Arrange
+		client := &fakeSESClient{}
+		mailer := newSESMailer(client, SESConfig{From: "noreply@example.com"}, nil)
+		statement := []byte("Id,Date,Transaction\n0,7/15,+60.5\n")
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary, WithAttachments(
+			Attachment{Filename: "statement.csv", ContentType: "text/csv", Content: statement},
+			Attachment{Filename: "statement.pdf", Content: []byte("%PDF-1.4")},
+		))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []types.Attachment{
+			{
+				FileName:           aws.String("statement.csv"),
+				ContentType:        aws.String("text/csv"),
+				RawContent:         statement,
+				ContentDisposition: types.AttachmentContentDispositionAttachment,
+			},
+			{
+				FileName:           aws.String("statement.pdf"),
+				RawContent:         []byte("%PDF-1.4"),
+				ContentDisposition: types.AttachmentContentDispositionAttachment,
+			},
+		}, client.inputs[0].Content.Simple.Attachments)
+	})
+
+	t.Run("it should reject an invalid recipient without calling SES", func(t *testing.T) {
+		// Arrange
+		client := &fakeSESClient{}
+		mailer := newSESMailer(client, SESConfig{From: "noreply@example.com"}, nil)
+
+		// Act
+		err := mailer.Send(context.Background(), "not-an-email", summary)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrInvalidRecipient)
+		assert.Empty(t, client.inputs)
+	})
+
+	t.Run("it should return the SES error", func(t *testing.T) {
+		// Arrange
+		failure := errors.New("MessageRejected: Email address is not verified")
+		client := &fakeSESClient{err: failure}
+		mailer := newSESMailer(client, SESConfig{From: "noreply@example.com"}, nil)
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		assert.ErrorIs(t, err, failure)
+	})
+}
@@ -1,19 +1,54 @@
 package mailing
 
 import (
-	"bytes"
 	"context"
-	"embed"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"html/template"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
 	"stori-challenge/internal/summaries"
+	"stori-challenge/pkg/blend"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/go-gomail/gomail"
 )
 
-//go:embed email_template.html
-var emailTemplateFS embed.FS
+const (
+	// defaultMaxAttempts is the default number of attempts to send an email
+	defaultMaxAttempts = 3
+
+	// defaultRetryBaseDelay is the default delay before the first retry
+	defaultRetryBaseDelay = 500 * time.Millisecond
+
+	// defaultCircuitBreakerThreshold is the default number of consecutive
+	// failures that opens the circuit breaker
+	defaultCircuitBreakerThreshold = 5
+
+	// defaultCircuitBreakerCoolDown is the default time the circuit breaker
+	// stays open before letting a trial attempt through
+	defaultCircuitBreakerCoolDown = 30 * time.Second
+)
+
+const (
+	// TLSModeStartTLS connects in plain text and upgrades the connection with
+	// STARTTLS when the server advertises it (usually port 587)
+	TLSModeStartTLS = "starttls"
+
+	// TLSModeSSL connects with implicit TLS (usually port 465)
+	TLSModeSSL = "ssl"
+
+	// TLSModeNone connects in plain text, even when the server advertises
+	// STARTTLS (e.g. for a local development server)
+	TLSModeNone = "none"
+)
 
 // SMTPConfig holds the configuration for SMTP connection
 type SMTPConfig struct {
@@ -22,104 +57,348 @@ type SMTPConfig struct {
 	Username string
 	Password string
 	From     string
+
+	// MaxAttempts is the maximum number of attempts to send an email,
+	// including the first one (default: 3)
+	MaxAttempts int
+
+	// RetryBaseDelay is the delay before the first retry, doubled on every
+	// following retry (default: 500ms)
+	RetryBaseDelay time.Duration
+
+	// CircuitBreakerThreshold is the number of consecutive transient failures,
+	// across sends, after which the SMTP server isn't dialed anymore and sends
+	// fail fast with ErrCircuitOpen (default: 5)
+	CircuitBreakerThreshold int
+
+	// CircuitBreakerCoolDown is how long sends fail fast once the circuit
+	// breaker opens. Afterwards a single trial attempt is let through: its
+	// success closes the breaker and its failure opens it again (default: 30s)
+	CircuitBreakerCoolDown time.Duration
+
+	// Locale is the language of the email, LocaleSpanish or LocaleEnglish.
+	// Unsupported values fall back to the default (default: "es")
+	Locale string
+
+	// TLSMode is the TLS mode of the connection: TLSModeStartTLS, TLSModeSSL or
+	// TLSModeNone. Unsupported values fall back to the default (default: implicit
+	// TLS on port 465, STARTTLS otherwise)
+	TLSMode string
+
+	// SubjectTemplate is a text/template for the email subject. It can reference
+	// the summary fields, the recipient and the summarized period, e.g.
+	// "Statement for {{.Recipient}} - {{monthName .PeriodEnd.Month}}"
+	// (default: the localized "Resumen de Transacciones - Stori")
+	SubjectTemplate string
+
+	// Logger logs the failures that don't fail the send, such as closing the
+	// connection after the email was accepted (default: a dummy logger)
+	Logger blend.Logger
+}
+
+// dialer abstracts the SMTP dialer so it can be replaced in tests.
+type dialer interface {
+	Dial() (gomail.SendCloser, error)
 }
 
 type SMTPMailer struct {
-	config SMTPConfig
+	*emailRenderer
+
+	config  SMTPConfig
+	dialer  dialer
+	breaker *circuitBreaker
 }
 
 // NewSMTPMailer creates a new SMTPMailer with the given configuration
+// that renders the embedded HTML template
 func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	return NewSMTPMailerWithTemplate(config, nil)
+}
+
+// NewSMTPMailerWithTemplate creates a new SMTPMailer with the given configuration
+// that renders the given HTML template instead of the embedded one (nil keeps the
+// embedded template). The template receives the same data and functions as the
+// embedded one, so it should be parsed with ParseTemplate.
+func NewSMTPMailerWithTemplate(config SMTPConfig, tmpl *template.Template) *SMTPMailer {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.RetryBaseDelay <= 0 {
+		config.RetryBaseDelay = defaultRetryBaseDelay
+	}
+	if config.CircuitBreakerThreshold <= 0 {
+		config.CircuitBreakerThreshold = defaultCircuitBreakerThreshold
+	}
+	if config.CircuitBreakerCoolDown <= 0 {
+		config.CircuitBreakerCoolDown = defaultCircuitBreakerCoolDown
+	}
+	if _, ok := subjects[config.Locale]; !ok {
+		config.Locale = LocaleSpanish
+	}
+	if config.Logger == nil {
+		config.Logger = blend.NewDummyLogger()
+	}
+
 	return &SMTPMailer{
-		config: config,
+		emailRenderer: newEmailRenderer(config.Locale, config.SubjectTemplate, tmpl),
+		config:        config,
+		dialer:        newDialer(config),
+		breaker:       newCircuitBreaker(config.CircuitBreakerThreshold, config.CircuitBreakerCoolDown),
+	}
+}
+
+// newDialer creates the dialer for the configured TLS mode. gomail always
+// upgrades the connection when the server advertises STARTTLS, so plain text
+// connections use a plainDialer instead.
+func newDialer(config SMTPConfig) dialer {
+	if config.TLSMode == TLSModeNone {
+		return newPlainDialer(config)
+	}
+	return newGomailDialer(config)
+}
+
+// newGomailDialer creates the gomail dialer for the configured TLS mode
+func newGomailDialer(config SMTPConfig) *gomail.Dialer {
+	d := gomail.NewDialer(config.Host, config.Port, config.Username, config.Password)
+
+	switch config.TLSMode {
+	case TLSModeSSL:
+		d.SSL = true
+		d.TLSConfig = &tls.Config{ServerName: config.Host, MinVersion: tls.VersionTLS12}
+	case TLSModeStartTLS:
+		d.SSL = false
+		d.TLSConfig = &tls.Config{ServerName: config.Host, MinVersion: tls.VersionTLS12}
 	}
+
+	return d
 }
 
-// Send sends an email with the transaction summary
-func (s *SMTPMailer) Send(ctx context.Context, to string, summary summaries.Summary) error {
+// Send sends an email with the transaction summary, along with the attachments
+// given with WithAttachments
+func (s *SMTPMailer) Send(ctx context.Context, to string, summary summaries.Summary, opts ...SendOption) error {
+	// Validate the recipient before doing any work
+	if _, err := mail.ParseAddress(to); err != nil {
+		return fmt.Errorf("%w %q: %v", ErrInvalidRecipient, to, err)
+	}
+
 	// Create a new message
 	m := gomail.NewMessage()
 	m.SetHeader("From", s.config.From)
 	m.SetHeader("To", to)
-	m.SetHeader("Subject", "Resumen de Transacciones - Stori")
+
+	// Generate the subject
+	subject, err := s.generateSubject(to, summary)
+	if err != nil {
+		return fmt.Errorf("error generating subject: %w", err)
+	}
+
+	m.SetHeader("Subject", subject)
 
 	// Generate HTML content
-	htmlBody, err := s.generateHTMLBody(summary)
+	htmlBody, err := s.RenderHTML(summary)
 	if err != nil {
 		return fmt.Errorf("error generating HTML body: %w", err)
 	}
 
-	m.SetBody("text/html", htmlBody)
+	// Generate plain text content for clients that don't render HTML
+	plainBody, err := s.RenderPlain(summary)
+	if err != nil {
+		return fmt.Errorf("error generating plain text body: %w", err)
+	}
+
+	m.SetBody("text/plain", plainBody)
+	m.AddAlternative("text/html", htmlBody)
 
-	// Create SMTP dialer
-	d := gomail.NewDialer(s.config.Host, s.config.Port, s.config.Username, s.config.Password)
+	for _, attachment := range NewSendOptions(opts...).Attachments {
+		attach(m, attachment)
+	}
 
-	// Send the email
-	if err := d.DialAndSend(m); err != nil {
+	// Send the email, retrying on transient failures
+	if err := s.sendWithRetry(ctx, m); err != nil {
 		return fmt.Errorf("error sending email: %w", err)
 	}
 
 	return nil
 }
 
-// generateHTMLBody generates the HTML body for the email
-func (s *SMTPMailer) generateHTMLBody(summary summaries.Summary) (string, error) {
-	// Read template from embedded file
-	templateContent, err := emailTemplateFS.ReadFile("email_template.html")
-	if err != nil {
-		return "", fmt.Errorf("error reading email template: %w", err)
-	}
-
-	// Create template with custom functions
-	t := template.New("email").Funcs(template.FuncMap{
-		"monthName": func(month time.Month) string {
-			months := map[time.Month]string{
-				time.January:   "Enero",
-				time.February:  "Febrero",
-				time.March:     "Marzo",
-				time.April:     "Abril",
-				time.May:       "Mayo",
-				time.June:      "Junio",
-				time.July:      "Julio",
-				time.August:    "Agosto",
-				time.September: "Septiembre",
-				time.October:   "Octubre",
-				time.November:  "Noviembre",
-				time.December:  "Diciembre",
+// attach attaches a file to the message from memory
+func attach(m *gomail.Message, attachment Attachment) {
+	content := attachment.Content
+	settings := []gomail.FileSetting{
+		gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := w.Write(content)
+			return err
+		}),
+	}
+	if attachment.ContentType != "" {
+		settings = append(settings, gomail.SetHeader(map[string][]string{
+			"Content-Type": {attachment.ContentType + `; name="` + filepath.Base(attachment.Filename) + `"`},
+		}))
+	}
+
+	m.Attach(attachment.Filename, settings...)
+}
+
+// sendWithRetry sends the message, retrying transient failures with exponential
+// backoff and jitter. Permanent failures (e.g. authentication) are returned
+// immediately, and the context is respected between attempts. While the
+// circuit breaker is open, no attempt is made and ErrCircuitOpen is returned.
+func (s *SMTPMailer) sendWithRetry(ctx context.Context, m *gomail.Message) error {
+	var err error
+	for attempt := 1; attempt <= s.config.MaxAttempts; attempt++ {
+		trial, breakerErr := s.breaker.allow()
+		if breakerErr != nil {
+			if err == nil {
+				return breakerErr
 			}
-			return months[month]
-		},
-		"hasDebit": func(value float64) bool {
-			return value != 0.0
-		},
-		"hasCredit": func(value float64) bool {
-			return value != 0.0
-		},
-		"formatAmount": func(value float64) string {
-			return fmt.Sprintf("%.2f", value)
-		},
-	})
-
-	// Parse template
-	t, err = t.Parse(string(templateContent))
-	if err != nil {
-		return "", fmt.Errorf("error parsing template: %w", err)
+			return fmt.Errorf("%w (last error: %v)", breakerErr, err)
+		}
+
+		err = s.sendContext(ctx, m)
+
+		// Check the context first: context.DeadlineExceeded is a net.Error, so
+		// an attempt abandoned on a deadline would otherwise look transient
+		abandoned := err != nil && ctx.Err() != nil
+		s.breaker.done(trial, !abandoned && err != nil && isTransientSMTPError(err), abandoned)
+		if err == nil {
+			return nil
+		}
+
+		if !isTransientSMTPError(err) {
+			return err
+		}
+
+		if attempt == s.config.MaxAttempts {
+			break
+		}
+
+		// Wait before the next attempt, unless the context is done
+		select {
+		case <-ctx.Done():
+			return contextError(ctx, err)
+		case <-time.After(s.retryDelay(attempt)):
+		}
+	}
+
+	return fmt.Errorf("giving up after %d attempts: %w", s.config.MaxAttempts, err)
+}
+
+// contextError returns the context error, keeping the last send error in the
+// message when it isn't the context error itself.
+func contextError(ctx context.Context, lastErr error) error {
+	if errors.Is(lastErr, ctx.Err()) {
+		return lastErr
+	}
+	return fmt.Errorf("%w (last error: %v)", ctx.Err(), lastErr)
+}
+
+// sendContext dials the SMTP server and sends the message in a goroutine,
+// returning ctx.Err() as soon as the context is done. The connection is closed
+// on cancellation so a hung server doesn't leak the goroutine.
+func (s *SMTPMailer) sendContext(ctx context.Context, m *gomail.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var (
+		mu        sync.Mutex
+		conn      gomail.SendCloser
+		cancelled bool
+	)
+	done := make(chan error, 1)
+
+	go func() {
+		sendCloser, err := s.dialer.Dial()
+		if err != nil {
+			done <- err
+			return
+		}
+
+		// Don't use the connection if the context was done while dialing
+		mu.Lock()
+		if cancelled {
+			mu.Unlock()
+			sendCloser.Close()
+			done <- ctx.Err()
+			return
+		}
+		conn = sendCloser
+		mu.Unlock()
+
+		err = gomail.Send(sendCloser, m)
+		if closeErr := sendCloser.Close(); closeErr != nil && err == nil {
+			// The email was already accepted by the server, so failing the
+			// send here would retry it and deliver a duplicate
+			s.config.Logger.Warn(ctx, "failed to close the SMTP connection after sending: %v", closeErr)
+		}
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		mu.Lock()
+		cancelled = true
+		if conn != nil {
+			conn.Close()
+		}
+		mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Ping checks the connectivity with the SMTP server (and the credentials) by
+// dialing it and closing the connection right away, without sending anything.
+// It bypasses the circuit breaker, so it reports the current state of the server.
+func (s *SMTPMailer) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		conn, err := s.dialer.Dial()
+		if err != nil {
+			done <- err
+			return
+		}
+		done <- conn.Close()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+}
+
+// retryDelay returns the delay before the retry following the given attempt.
+// The delay doubles on every attempt, and half of it is randomized (jitter)
+// to avoid retrying in lockstep with other invocations.
+func (s *SMTPMailer) retryDelay(attempt int) time.Duration {
+	delay := s.config.RetryBaseDelay << (attempt - 1)
+	return delay/2 + rand.N(delay/2+1)
+}
 
-	// Prepare data for template
-	data := struct {
-		summaries.Summary
-		GeneratedAt string
-	}{
-		Summary:     summary,
-		GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+// isTransientSMTPError reports whether an SMTP error is worth retrying:
+// network errors (e.g. connection reset) and 4xx SMTP replies are transient,
+// while anything else (e.g. 535 authentication failure) is permanent.
+func isTransientSMTPError(err error) bool {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code >= 400 && protoErr.Code < 500
 	}
 
-	// Execute template
-	var buf bytes.Buffer
-	if err := t.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("error executing template: %w", err)
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
 	}
 
-	return buf.String(), nil
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
 }
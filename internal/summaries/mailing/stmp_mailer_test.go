@@ -0,0 +1,1104 @@
+package mailing
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/summaries"
+
+	"github.com/go-gomail/gomail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSendCloser is a test SMTP connection that blocks sending until
+// the release channel is closed (when set), and records the last message sent
+// and whether it was closed. Closing it fails with closeErr (when set).
+type fakeSendCloser struct {
+	mu       sync.Mutex
+	release  chan struct{}
+	sent     int
+	message  string
+	closed   bool
+	closeErr error
+}
+
+func (c *fakeSendCloser) Send(from string, to []string, msg io.WriterTo) error {
+	if c.release != nil {
+		<-c.release
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errors.New("connection closed")
+	}
+	var buf bytes.Buffer
+	if _, err := msg.WriteTo(&buf); err != nil {
+		return err
+	}
+	c.sent++
+	c.message = buf.String()
+	return nil
+}
+
+func (c *fakeSendCloser) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed && c.release != nil {
+		close(c.release)
+	}
+	c.closed = true
+	return c.closeErr
+}
+
+func (c *fakeSendCloser) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// fakeDialer is a test dialer that returns the queued errors in order,
+// connecting successfully once the queue is exhausted.
+type fakeDialer struct {
+	mu       sync.Mutex
+	errs     []error
+	attempts int
+	conn     *fakeSendCloser
+	onDial   func()
+}
+
+func (d *fakeDialer) Dial() (gomail.SendCloser, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.attempts++
+	if d.onDial != nil {
+		d.onDial()
+	}
+	if len(d.errs) > 0 {
+		err := d.errs[0]
+		d.errs = d.errs[1:]
+		return nil, err
+	}
+	if d.conn == nil {
+		d.conn = &fakeSendCloser{}
+	}
+	return d.conn, nil
+}
+
+// newTestMailer creates an SMTPMailer with fast retries and the given fake dialer.
+func newTestMailer(dialer dialer) *SMTPMailer {
+	mailer := NewSMTPMailer(SMTPConfig{
+		Host:           "smtp.example.com",
+		Port:           587,
+		From:           "noreply@example.com",
+		MaxAttempts:    3,
+		RetryBaseDelay: time.Millisecond,
+	})
+	mailer.dialer = dialer
+	return mailer
+}
+
+func TestSMTPMailer_Send_Retry(t *testing.T) {
+	summary := summaries.Summary{TotalBalance: 39.74, YearlyData: summaries.YearlyData{}}
+
+	t.Run("it should succeed after transient failures", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{errs: []error{
+			syscall.ECONNRESET,
+			&textproto.Error{Code: 421, Msg: "Service not available"},
+		}}
+		mailer := newTestMailer(dialer)
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 3, dialer.attempts, "should retry twice before succeeding")
+	})
+
+	t.Run("it should not retry permanent failures", func(t *testing.T) {
+		// Arrange
+		authErr := &textproto.Error{Code: 535, Msg: "Authentication failed"}
+		dialer := &fakeDialer{errs: []error{authErr}}
+		mailer := newTestMailer(dialer)
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		require.Error(t, err)
+		assert.ErrorIs(t, err, authErr)
+		assert.Equal(t, 1, dialer.attempts, "should give up on the first permanent failure")
+	})
+
+	t.Run("it should give up after the maximum attempts", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{errs: []error{syscall.ECONNRESET, syscall.ECONNRESET, syscall.ECONNRESET, syscall.ECONNRESET}}
+		mailer := newTestMailer(dialer)
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		require.Error(t, err)
+		assert.ErrorIs(t, err, syscall.ECONNRESET)
+		assert.Contains(t, err.Error(), "giving up after 3 attempts")
+		assert.Equal(t, 3, dialer.attempts)
+	})
+
+	t.Run("it should stop retrying when the context is cancelled", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		dialer := &fakeDialer{errs: []error{syscall.ECONNRESET, syscall.ECONNRESET}, onDial: cancel}
+		mailer := newTestMailer(dialer)
+		mailer.config.RetryBaseDelay = time.Hour
+
+		// Act
+		err := mailer.Send(ctx, "user@example.com", summary)
+
+		// Assert
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, dialer.attempts)
+	})
+}
+
+func TestSMTPMailer_Send_Context(t *testing.T) {
+	summary := summaries.Summary{TotalBalance: 39.74, YearlyData: summaries.YearlyData{}}
+
+	t.Run("it should not dial with an already-cancelled context", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{}
+		mailer := newTestMailer(dialer)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Act
+		err := mailer.Send(ctx, "user@example.com", summary)
+
+		// Assert
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 0, dialer.attempts, "should not dial at all")
+	})
+
+	t.Run("it should return promptly and close the connection on a slow server", func(t *testing.T) {
+		// Arrange
+		conn := &fakeSendCloser{release: make(chan struct{})}
+		dialer := &fakeDialer{conn: conn}
+		mailer := newTestMailer(dialer)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		// Act
+		start := time.Now()
+		err := mailer.Send(ctx, "user@example.com", summary)
+
+		// Assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second, "should not wait for the slow server")
+		assert.True(t, conn.isClosed(), "should close the connection on cancellation")
+		assert.Equal(t, 1, dialer.attempts, "should not retry after the deadline")
+	})
+
+	t.Run("it should send and close the connection", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{}
+		mailer := newTestMailer(dialer)
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, dialer.conn.sent)
+		assert.True(t, dialer.conn.isClosed())
+	})
+
+	t.Run("it should not retry when closing fails after sending", func(t *testing.T) {
+		// Arrange
+		conn := &fakeSendCloser{closeErr: syscall.ECONNRESET}
+		dialer := &fakeDialer{conn: conn}
+		mailer := newTestMailer(dialer)
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, conn.sent, "should send the email exactly once")
+		assert.Equal(t, 1, dialer.attempts, "should not retry")
+	})
+}
+
+// newBreakerTestMailer creates a test mailer whose circuit breaker opens after
+// two consecutive failures for a minute, and a function to advance its clock.
+func newBreakerTestMailer(dialer dialer, maxAttempts int) (*SMTPMailer, func(time.Duration)) {
+	mailer := newTestMailer(dialer)
+	mailer.config.MaxAttempts = maxAttempts
+
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	mailer.breaker = newCircuitBreaker(2, time.Minute)
+	mailer.breaker.now = func() time.Time { return now }
+	return mailer, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestSMTPMailer_Send_CircuitBreaker(t *testing.T) {
+	summary := summaries.Summary{TotalBalance: 39.74, YearlyData: summaries.YearlyData{}}
+
+	t.Run("it should open after consecutive failures and fail fast", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{errs: []error{syscall.ECONNREFUSED, syscall.ECONNREFUSED}}
+		mailer, _ := newBreakerTestMailer(dialer, 1)
+
+		// Act
+		firstErr := mailer.Send(context.Background(), "user@example.com", summary)
+		stateAfterFirst := mailer.breaker.state()
+		secondErr := mailer.Send(context.Background(), "user@example.com", summary)
+		thirdErr := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		assert.ErrorIs(t, firstErr, syscall.ECONNREFUSED)
+		assert.Equal(t, circuitClosed, stateAfterFirst)
+		assert.ErrorIs(t, secondErr, syscall.ECONNREFUSED)
+		assert.Equal(t, circuitOpen, mailer.breaker.state())
+		assert.ErrorIs(t, thirdErr, ErrCircuitOpen)
+		assert.Equal(t, 2, dialer.attempts, "should not dial while the circuit is open")
+	})
+
+	t.Run("it should stop retrying once the circuit opens", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{errs: []error{syscall.ECONNRESET, syscall.ECONNRESET, syscall.ECONNRESET}}
+		mailer, _ := newBreakerTestMailer(dialer, 3)
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.Contains(t, err.Error(), syscall.ECONNRESET.Error())
+		assert.Equal(t, 2, dialer.attempts)
+	})
+
+	t.Run("it should half-open after the cool-down and close on success", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{errs: []error{syscall.ECONNREFUSED, syscall.ECONNREFUSED}}
+		mailer, advance := newBreakerTestMailer(dialer, 1)
+		mailer.Send(context.Background(), "user@example.com", summary)
+		mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Act
+		advance(59 * time.Second)
+		stillOpen := mailer.breaker.state()
+		advance(time.Second)
+		halfOpen := mailer.breaker.state()
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		assert.Equal(t, circuitOpen, stillOpen)
+		assert.Equal(t, circuitHalfOpen, halfOpen)
+		assert.NoError(t, err)
+		assert.Equal(t, circuitClosed, mailer.breaker.state())
+		assert.Equal(t, 3, dialer.attempts)
+	})
+
+	t.Run("it should reopen when the half-open trial fails", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{errs: []error{syscall.ECONNREFUSED, syscall.ECONNREFUSED, syscall.ECONNREFUSED}}
+		mailer, advance := newBreakerTestMailer(dialer, 3)
+		mailer.Send(context.Background(), "user@example.com", summary)
+		advance(time.Minute)
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrCircuitOpen, "should not retry a failed trial")
+		assert.Equal(t, circuitOpen, mailer.breaker.state(), "should start a new cool-down")
+		assert.Equal(t, 3, dialer.attempts)
+	})
+
+	t.Run("it should let a single trial through while half-open", func(t *testing.T) {
+		// Arrange
+		breaker := newCircuitBreaker(1, time.Minute)
+		now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		breaker.now = func() time.Time { return now }
+		_, err := breaker.allow()
+		require.NoError(t, err)
+		breaker.done(false, true, false)
+		now = now.Add(time.Minute)
+
+		// Act
+		trial, trialErr := breaker.allow()
+		_, concurrentErr := breaker.allow()
+
+		// Assert
+		assert.NoError(t, trialErr)
+		assert.True(t, trial)
+		assert.ErrorIs(t, concurrentErr, ErrCircuitOpen)
+	})
+
+	t.Run("it should keep the trial in flight when an earlier attempt finishes", func(t *testing.T) {
+		// Arrange
+		breaker := newCircuitBreaker(1, time.Minute)
+		now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		breaker.now = func() time.Time { return now }
+		earlier, err := breaker.allow()
+		require.NoError(t, err)
+		_, err = breaker.allow()
+		require.NoError(t, err)
+		breaker.done(false, true, false)
+		now = now.Add(time.Minute)
+		_, err = breaker.allow()
+		require.NoError(t, err)
+
+		// Act
+		breaker.done(earlier, true, false)
+		_, concurrentErr := breaker.allow()
+
+		// Assert
+		assert.False(t, earlier)
+		assert.ErrorIs(t, concurrentErr, ErrCircuitOpen, "should not let a second trial through")
+	})
+
+	t.Run("it should not count an attempt abandoned on a deadline", func(t *testing.T) {
+		// Arrange
+		conn := &fakeSendCloser{release: make(chan struct{})}
+		dialer := &fakeDialer{conn: conn}
+		mailer, _ := newBreakerTestMailer(dialer, 3)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		// Act
+		err := mailer.Send(ctx, "user@example.com", summary)
+
+		// Assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Zero(t, mailer.breaker.failures, "should not count the deadline as a transient failure")
+	})
+
+	t.Run("it should reset the failure count on success", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{errs: []error{syscall.ECONNREFUSED}}
+		mailer, _ := newBreakerTestMailer(dialer, 1)
+		mailer.Send(context.Background(), "user@example.com", summary)
+		mailer.Send(context.Background(), "user@example.com", summary)
+		dialer.errs = []error{syscall.ECONNREFUSED}
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		assert.ErrorIs(t, err, syscall.ECONNREFUSED)
+		assert.Equal(t, circuitClosed, mailer.breaker.state())
+	})
+
+	t.Run("it should not count permanent failures", func(t *testing.T) {
+		// Arrange
+		authErr := &textproto.Error{Code: 535, Msg: "Authentication failed"}
+		dialer := &fakeDialer{errs: []error{authErr, authErr, authErr}}
+		mailer, _ := newBreakerTestMailer(dialer, 1)
+
+		// Act
+		for range 3 {
+			mailer.Send(context.Background(), "user@example.com", summary)
+		}
+
+		// Assert
+		assert.Equal(t, circuitClosed, mailer.breaker.state())
+		assert.Equal(t, 3, dialer.attempts)
+	})
+
+	t.Run("it should apply the default thresholds", func(t *testing.T) {
+		// Act
+		mailer := NewSMTPMailer(SMTPConfig{Host: "smtp.example.com", Port: 587})
+
+		// Assert
+		assert.Equal(t, 5, mailer.breaker.threshold)
+		assert.Equal(t, 30*time.Second, mailer.breaker.coolDown)
+	})
+}
+
+func TestSMTPMailer_Ping(t *testing.T) {
+	t.Run("it should dial and close the connection without sending", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{}
+		mailer := newTestMailer(dialer)
+
+		// Act
+		err := mailer.Ping(context.Background())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, dialer.attempts)
+		assert.True(t, dialer.conn.isClosed())
+		assert.Equal(t, 0, dialer.conn.sent)
+	})
+
+	t.Run("it should return the dial failure", func(t *testing.T) {
+		// Arrange
+		authErr := &textproto.Error{Code: 535, Msg: "Authentication failed"}
+		mailer := newTestMailer(&fakeDialer{errs: []error{authErr}})
+
+		// Act
+		err := mailer.Ping(context.Background())
+
+		// Assert
+		assert.ErrorIs(t, err, authErr)
+	})
+
+	t.Run("it should not dial with an already-cancelled context", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{}
+		mailer := newTestMailer(dialer)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Act
+		err := mailer.Ping(ctx)
+
+		// Assert
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 0, dialer.attempts)
+	})
+}
+
+func TestSMTPMailer_Send_Alternatives(t *testing.T) {
+	// Arrange
+	summary := summaries.Summary{
+		TotalBalance: 39.74,
+		YearlyData: summaries.YearlyData{
+			2024: summaries.MonthlyData{
+				time.July:   {TransactionCount: 2, AverageDebit: -10.3, AverageCredit: 60.5},
+				time.August: {TransactionCount: 2, AverageDebit: -10.46, AverageCredit: 10},
+			},
+		},
+	}
+	dialer := &fakeDialer{}
+	mailer := newTestMailer(dialer)
+
+	// Act
+	err := mailer.Send(context.Background(), "user@example.com", summary)
+
+	// Assert
+	require.NoError(t, err)
+	message := dialer.conn.message
+	assert.Contains(t, message, "multipart/alternative")
+	assert.Contains(t, message, "Content-Type: text/plain")
+	assert.Contains(t, message, "Content-Type: text/html")
+	assert.Less(t, strings.Index(message, "Content-Type: text/plain"), strings.Index(message, "Content-Type: text/html"),
+		"the HTML part should come last so clients prefer it")
+}
+
+func TestSMTPMailer_Send_Attachments(t *testing.T) {
+	summary := summaries.Summary{TotalBalance: 39.74, YearlyData: summaries.YearlyData{}}
+
+	t.Run("it should attach the files with their name and content type", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{}
+		mailer := newTestMailer(dialer)
+		statement := []byte("Id,Date,Transaction\n0,7/15,+60.5\n")
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary, WithAttachments(
+			Attachment{Filename: "statement.csv", ContentType: "text/csv", Content: statement},
+			Attachment{Filename: "statement.pdf", Content: []byte("%PDF-1.4")},
+		))
+
+		// Assert
+		require.NoError(t, err)
+		message := dialer.conn.message
+		assert.Contains(t, message, "multipart/mixed")
+		assert.Contains(t, message, `Content-Type: text/csv; name="statement.csv"`)
+		assert.Contains(t, message, `Content-Disposition: attachment; filename="statement.csv"`)
+		assert.Contains(t, message, base64.StdEncoding.EncodeToString(statement))
+		assert.Contains(t, message, `Content-Type: application/pdf; name="statement.pdf"`, "should guess the content type from the extension")
+		assert.Contains(t, message, `Content-Disposition: attachment; filename="statement.pdf"`)
+		assert.Contains(t, message, "multipart/alternative", "should keep the text and HTML bodies")
+	})
+
+	t.Run("it should not attach anything by default", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{}
+		mailer := newTestMailer(dialer)
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		require.NoError(t, err)
+		assert.NotContains(t, dialer.conn.message, "Content-Disposition: attachment")
+	})
+}
+
+func TestSMTPMailer_RenderHTML(t *testing.T) {
+	summary := summaries.Summary{
+		TotalBalance:          39.74,
+		TotalTransactionCount: 4,
+		YearlyData: summaries.YearlyData{
+			2024: summaries.MonthlyData{
+				time.July:   {TransactionCount: 3, AverageDebit: -15.38, AverageCredit: 60.5},
+				time.August: {TransactionCount: 1, AverageCredit: 10},
+			},
+		},
+	}
+
+	t.Run("it should render the totals and the months of the summary", func(t *testing.T) {
+		// Arrange
+		mailer := newTestMailer(&fakeDialer{})
+
+		// Act
+		body, err := mailer.RenderHTML(summary)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Contains(t, body, "$39.74")
+		assert.Contains(t, body, "2024")
+		assert.Contains(t, body, "Julio")
+		assert.Contains(t, body, "3 transacciones")
+		assert.Contains(t, body, "15.38")
+		assert.Contains(t, body, "+$60.50")
+		assert.Contains(t, body, "Agosto")
+		assert.Contains(t, body, "+$10.00")
+		assert.Less(t, strings.Index(body, "Julio"), strings.Index(body, "Agosto"), "months should be in order")
+	})
+
+	t.Run("it should render the month names of the configured locale", func(t *testing.T) {
+		// Arrange
+		mailer := newTestMailer(&fakeDialer{})
+		mailer.locale = LocaleEnglish
+
+		// Act
+		body, err := mailer.RenderHTML(summary)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Contains(t, body, "July")
+		assert.Contains(t, body, "August")
+		assert.NotContains(t, body, "Julio")
+	})
+
+	t.Run("it should render the body sent by Send", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{}
+		mailer := newTestMailer(dialer)
+		mailer.now = func() time.Time { return time.Date(2003, time.May, 1, 0, 0, 0, 0, time.UTC) }
+
+		// Act
+		body, renderErr := mailer.RenderHTML(summary)
+		sendErr := mailer.Send(context.Background(), "user@example.com", summary)
+
+		// Assert
+		require.NoError(t, renderErr)
+		require.NoError(t, sendErr)
+		assert.Contains(t, decodeQuotedPrintable(t, dialer.conn.message), body)
+	})
+}
+
+// decodeQuotedPrintable decodes a quoted-printable message, as gomail encodes the bodies.
+func decodeQuotedPrintable(t *testing.T, message string) string {
+	t.Helper()
+	decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(message)))
+	require.NoError(t, err)
+	return strings.ReplaceAll(string(decoded), "\r\n", "\n")
+}
+
+func TestSMTPMailer_RenderPlain(t *testing.T) {
+	// Arrange
+	summary := summaries.Summary{
+		TotalBalance: 39.74,
+		YearlyData: summaries.YearlyData{
+			2024: summaries.MonthlyData{
+				time.July:   {TransactionCount: 2, AverageDebit: -10.3, AverageCredit: 60.5},
+				time.August: {TransactionCount: 1, AverageCredit: 10},
+			},
+		},
+	}
+	mailer := newTestMailer(&fakeDialer{})
+
+	// Act
+	body, err := mailer.RenderPlain(summary)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Contains(t, body, "Saldo total: $39.74")
+	assert.Contains(t, body, "2024")
+	assert.Contains(t, body, "Julio: 2 transacciones")
+	assert.Contains(t, body, "Débito promedio: $-10.30")
+	assert.Contains(t, body, "Crédito promedio: $60.50")
+	assert.Contains(t, body, "Agosto: 1 transacciones")
+	assert.NotContains(t, body, "<", "plain text body should not contain HTML")
+}
+
+func TestSMTPMailer_GeneratedAt(t *testing.T) {
+	// Arrange
+	summary := summaries.Summary{TotalBalance: 39.74, YearlyData: summaries.YearlyData{}}
+	mailer := newTestMailer(&fakeDialer{})
+	mailer.now = func() time.Time {
+		return time.Date(2003, time.May, 1, 13, 45, 30, 0, time.UTC)
+	}
+
+	// Act
+	htmlBody, htmlErr := mailer.RenderHTML(summary)
+	plainBody, plainErr := mailer.RenderPlain(summary)
+
+	// Assert
+	require.NoError(t, htmlErr)
+	require.NoError(t, plainErr)
+	assert.Contains(t, htmlBody, "2003-05-01 13:45:30")
+	assert.Contains(t, plainBody, "2003-05-01 13:45:30")
+}
+
+func TestSMTPMailer_Currencies(t *testing.T) {
+	t.Run("it should render the balance and the months of each currency", func(t *testing.T) {
+		// Arrange
+		mailer := newTestMailer(&fakeDialer{})
+		summary := summaries.Summary{
+			TotalTransactionCount: 3,
+			YearlyData:            summaries.YearlyData{},
+			Currencies: map[string]summaries.Summary{
+				"MXN": {TotalBalance: 2000, Currency: "MXN", YearlyData: summaries.YearlyData{
+					2024: summaries.MonthlyData{time.July: {TransactionCount: 2, AverageCredit: 1000}},
+				}},
+				"USD": {TotalBalance: -10.3, Currency: "USD", YearlyData: summaries.YearlyData{
+					2024: summaries.MonthlyData{time.August: {TransactionCount: 1, AverageDebit: -10.3}},
+				}},
+			},
+		}
+
+		// Act
+		htmlBody, htmlErr := mailer.RenderHTML(summary)
+		plainBody, plainErr := mailer.RenderPlain(summary)
+
+		// Assert
+		require.NoError(t, htmlErr)
+		require.NoError(t, plainErr)
+		assert.Contains(t, htmlBody, "$2000.00 MXN")
+		assert.Contains(t, htmlBody, "$-10.30 USD")
+		assert.NotContains(t, htmlBody, "Sin movimientos")
+		assert.Contains(t, plainBody, "Saldo total (MXN): $2000.00\nSaldo total (USD): $-10.30\n")
+		assert.NotContains(t, plainBody, "Saldo total: ")
+		assert.NotContains(t, plainBody, "Sin movimientos")
+
+		mxnHTML, usdHTML, _ := strings.Cut(htmlBody, "USD\n")
+		assert.Contains(t, mxnHTML, "Julio")
+		assert.Contains(t, mxnHTML, "2 transacciones")
+		assert.Contains(t, mxnHTML, "+$1000.00")
+		assert.Contains(t, usdHTML, "Agosto")
+		assert.Contains(t, usdHTML, "1 transacciones")
+		assert.Contains(t, usdHTML, "-$-10.30")
+		assert.Contains(t, plainBody, "[MXN]\n\n2024\n\n  Julio: 2 transacciones\n    Crédito promedio: $1000.00\n")
+		assert.Contains(t, plainBody, "[USD]\n\n2024\n\n  Agosto: 1 transacciones\n    Débito promedio: $-10.30\n")
+	})
+}
+
+func TestSMTPMailer_NoActivity(t *testing.T) {
+	t.Run("it should render a no activity notice for an empty summary", func(t *testing.T) {
+		// Arrange
+		mailer := newTestMailer(&fakeDialer{})
+
+		// Act
+		htmlBody, htmlErr := mailer.RenderHTML(summaries.Summary{YearlyData: summaries.YearlyData{}})
+		plainBody, plainErr := mailer.RenderPlain(summaries.Summary{YearlyData: summaries.YearlyData{}})
+
+		// Assert
+		require.NoError(t, htmlErr)
+		require.NoError(t, plainErr)
+		assert.Contains(t, htmlBody, "Sin movimientos en el período.")
+		assert.Contains(t, plainBody, "Saldo total: $0.00")
+		assert.Contains(t, plainBody, "Sin movimientos en el período.")
+	})
+
+	t.Run("it should not render the notice for a summary with activity", func(t *testing.T) {
+		// Arrange
+		mailer := newTestMailer(&fakeDialer{})
+		summary := summaries.Summary{YearlyData: summaries.YearlyData{
+			2024: summaries.MonthlyData{time.July: {TransactionCount: 1, AverageCredit: 10}},
+		}}
+
+		// Act
+		htmlBody, htmlErr := mailer.RenderHTML(summary)
+		plainBody, plainErr := mailer.RenderPlain(summary)
+
+		// Assert
+		require.NoError(t, htmlErr)
+		require.NoError(t, plainErr)
+		assert.NotContains(t, htmlBody, "Sin movimientos")
+		assert.NotContains(t, plainBody, "Sin movimientos")
+	})
+}
+
+func TestSMTPMailer_Locale(t *testing.T) {
+	summary := summaries.Summary{
+		TotalBalance: 39.74,
+		YearlyData: summaries.YearlyData{
+			2024: summaries.MonthlyData{
+				time.July: {TransactionCount: 2, AverageDebit: -10.3, AverageCredit: 60.5},
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		locale          string
+		expectedMonth   string
+		expectedSubject string
+	}{
+		{
+			name:            "it should default to Spanish",
+			locale:          "",
+			expectedMonth:   "Julio",
+			expectedSubject: "Resumen de Transacciones - Stori",
+		},
+		{
+			name:            "it should render in Spanish",
+			locale:          LocaleSpanish,
+			expectedMonth:   "Julio",
+			expectedSubject: "Resumen de Transacciones - Stori",
+		},
+		{
+			name:            "it should render in English",
+			locale:          LocaleEnglish,
+			expectedMonth:   "July",
+			expectedSubject: "Transaction Summary - Stori",
+		},
+		{
+			name:            "it should fall back to Spanish for unsupported locales",
+			locale:          "fr",
+			expectedMonth:   "Julio",
+			expectedSubject: "Resumen de Transacciones - Stori",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			dialer := &fakeDialer{}
+			mailer := NewSMTPMailer(SMTPConfig{From: "noreply@example.com", Locale: tt.locale})
+			mailer.dialer = dialer
+
+			// Act
+			htmlBody, htmlErr := mailer.RenderHTML(summary)
+			plainBody, plainErr := mailer.RenderPlain(summary)
+			sendErr := mailer.Send(context.Background(), "user@example.com", summary)
+
+			// Assert
+			require.NoError(t, htmlErr)
+			require.NoError(t, plainErr)
+			require.NoError(t, sendErr)
+			assert.Contains(t, htmlBody, tt.expectedMonth)
+			assert.Contains(t, plainBody, tt.expectedMonth)
+			assert.Contains(t, dialer.conn.message, "Subject: "+tt.expectedSubject)
+		})
+	}
+	t.Run("it should not render Spanish labels in English", func(t *testing.T) {
+		// Arrange
+		mailer := NewSMTPMailer(SMTPConfig{From: "noreply@example.com", Locale: LocaleEnglish})
+		spanishLabels := []string{"Resumen", "Saldo total", "transacciones", "Débito", "Crédito", "promedio", "Sin movimientos", "período", `lang="es"`}
+
+		for _, summary := range []summaries.Summary{summary, {YearlyData: summaries.YearlyData{}}} {
+			// Act
+			htmlBody, htmlErr := mailer.RenderHTML(summary)
+			plainBody, plainErr := mailer.RenderPlain(summary)
+
+			// Assert
+			require.NoError(t, htmlErr)
+			require.NoError(t, plainErr)
+			for _, label := range spanishLabels {
+				assert.NotContains(t, htmlBody, label)
+				assert.NotContains(t, plainBody, label)
+			}
+		}
+	})
+}
+
+func TestSMTPMailer_generateSubject(t *testing.T) {
+	summary := summaries.Summary{
+		TotalBalance: 39.74,
+		YearlyData:   summaries.YearlyData{},
+		DailyBalances: []summaries.DailyBalance{
+			{Date: time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC), Balance: 60.5},
+			{Date: time.Date(2024, time.August, 13, 0, 0, 0, 0, time.UTC), Balance: 39.74},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		config          SMTPConfig
+		expectedSubject string
+		expectError     bool
+	}{
+		{
+			name:            "it should use the default subject when unset",
+			config:          SMTPConfig{},
+			expectedSubject: "Resumen de Transacciones - Stori",
+		},
+		{
+			name:            "it should use the localized default subject when unset",
+			config:          SMTPConfig{Locale: LocaleEnglish},
+			expectedSubject: "Transaction Summary - Stori",
+		},
+		{
+			name:            "it should render the recipient and period",
+			config:          SMTPConfig{SubjectTemplate: "Statement for {{.Recipient}} - {{monthName .PeriodEnd.Month}} {{.PeriodEnd.Year}}"},
+			expectedSubject: "Statement for user@example.com - Agosto 2024",
+		},
+		{
+			name:            "it should render summary fields",
+			config:          SMTPConfig{SubjectTemplate: "Your balance: ${{formatAmount .TotalBalance}}"},
+			expectedSubject: "Your balance: $39.74",
+		},
+		{
+			name:        "it should fail on an invalid template",
+			config:      SMTPConfig{SubjectTemplate: "Statement for {{.Recipient"},
+			expectError: true,
+		},
+		{
+			name:        "it should fail on an unknown field",
+			config:      SMTPConfig{SubjectTemplate: "Statement for {{.AccountID}}"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mailer := NewSMTPMailer(tt.config)
+
+			// Act
+			subject, err := mailer.generateSubject("user@example.com", summary)
+
+			// Assert
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSubject, subject)
+		})
+	}
+}
+
+func TestSMTPMailer_Send_SubjectTemplate(t *testing.T) {
+	t.Run("it should send the rendered subject", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{}
+		mailer := newTestMailer(dialer)
+		mailer.subjectTemplate = "Statement for {{.Recipient}}"
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summaries.Summary{YearlyData: summaries.YearlyData{}})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Contains(t, dialer.conn.message, "Subject: Statement for user@example.com")
+	})
+
+	t.Run("it should not dial when the subject can't be rendered", func(t *testing.T) {
+		// Arrange
+		dialer := &fakeDialer{}
+		mailer := newTestMailer(dialer)
+		mailer.subjectTemplate = "{{.Unknown}}"
+
+		// Act
+		err := mailer.Send(context.Background(), "user@example.com", summaries.Summary{YearlyData: summaries.YearlyData{}})
+
+		// Assert
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error generating subject")
+		assert.Equal(t, 0, dialer.attempts)
+	})
+}
+
+func TestSMTPMailer_CustomTemplate(t *testing.T) {
+	summary := summaries.Summary{
+		TotalBalance: 39.74,
+		YearlyData: summaries.YearlyData{
+			2024: summaries.MonthlyData{
+				time.July: {TransactionCount: 2, AverageDebit: -10.3, AverageCredit: 60.5},
+			},
+		},
+	}
+
+	t.Run("it should render a custom template with the same data and functions", func(t *testing.T) {
+		// Arrange
+		tmpl, err := ParseTemplate(`<p>Bank X: ${{formatAmount .TotalBalance}}</p>` +
+			`{{range $year, $months := .YearlyData}}{{range $month, $data := $months}}` +
+			`<p>{{monthName $month}} {{$year}}: {{$data.TransactionCount}}</p>{{end}}{{end}}` +
+			`<small>{{.GeneratedAt}}</small>`)
+		require.NoError(t, err)
+		mailer := NewSMTPMailerWithTemplate(SMTPConfig{}, tmpl)
+
+		// Act
+		body, err := mailer.RenderHTML(summary)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Contains(t, body, "<p>Bank X: $39.74</p>")
+		assert.Contains(t, body, "<p>Julio 2024: 2</p>")
+		assert.NotContains(t, body, "SAVVI Financieros", "should not render the embedded template")
+	})
+
+	t.Run("it should bind the functions of the configured locale", func(t *testing.T) {
+		// Arrange
+		tmpl, err := ParseTemplate(`{{range $year, $months := .YearlyData}}{{range $month, $data := $months}}{{monthName $month}}{{end}}{{end}}`)
+		require.NoError(t, err)
+		mailer := NewSMTPMailerWithTemplate(SMTPConfig{Locale: LocaleEnglish}, tmpl)
+
+		// Act
+		body, err := mailer.RenderHTML(summary)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "July", body)
+	})
+
+	t.Run("it should fall back to the embedded template when nil", func(t *testing.T) {
+		// Arrange
+		mailer := NewSMTPMailerWithTemplate(SMTPConfig{}, nil)
+
+		// Act
+		body, err := mailer.RenderHTML(summary)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Contains(t, body, "SAVVI Financieros")
+	})
+}
+
+func TestSMTPMailer_Send_Recipient(t *testing.T) {
+	tests := []struct {
+		name        string
+		to          string
+		expectError bool
+	}{
+		{name: "it should accept a valid address", to: "user@example.com", expectError: false},
+		{name: "it should accept an address with a display name", to: "User <user@example.com>", expectError: false},
+		{name: "it should reject an empty address", to: "", expectError: true},
+		{name: "it should reject a garbage address", to: "not an email", expectError: true},
+		{name: "it should reject an address without a domain", to: "user@", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			dialer := &fakeDialer{}
+			mailer := newTestMailer(dialer)
+
+			// Act
+			err := mailer.Send(context.Background(), tt.to, summaries.Summary{YearlyData: summaries.YearlyData{}})
+
+			// Assert
+			if tt.expectError {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrInvalidRecipient)
+				assert.Contains(t, err.Error(), fmt.Sprintf("invalid recipient %q", tt.to))
+				assert.Equal(t, 0, dialer.attempts, "should not dial with an invalid recipient")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 1, dialer.attempts)
+		})
+	}
+}
+
+func TestNewSMTPMailer_TLSMode(t *testing.T) {
+	tests := []struct {
+		name            string
+		tlsMode         string
+		port            int
+		expectedSSL     bool
+		expectTLSConfig bool
+	}{
+		{name: "it should default to STARTTLS on port 587", tlsMode: "", port: 587, expectedSSL: false, expectTLSConfig: false},
+		{name: "it should default to implicit TLS on port 465", tlsMode: "", port: 465, expectedSSL: true, expectTLSConfig: false},
+		{name: "it should use STARTTLS", tlsMode: TLSModeStartTLS, port: 465, expectedSSL: false, expectTLSConfig: true},
+		{name: "it should use implicit TLS", tlsMode: TLSModeSSL, port: 587, expectedSSL: true, expectTLSConfig: true},
+		{name: "it should fall back to the default for unsupported modes", tlsMode: "tls13", port: 465, expectedSSL: true, expectTLSConfig: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			mailer := NewSMTPMailer(SMTPConfig{Host: "smtp.example.com", Port: tt.port, TLSMode: tt.tlsMode})
+
+			// Assert
+			d, ok := mailer.dialer.(*gomail.Dialer)
+			require.True(t, ok, "should use the gomail dialer")
+			assert.Equal(t, "smtp.example.com", d.Host)
+			assert.Equal(t, tt.port, d.Port)
+			assert.Equal(t, tt.expectedSSL, d.SSL)
+			if tt.expectTLSConfig {
+				require.NotNil(t, d.TLSConfig)
+				assert.Equal(t, "smtp.example.com", d.TLSConfig.ServerName)
+				assert.Equal(t, uint16(tls.VersionTLS12), d.TLSConfig.MinVersion)
+			} else {
+				assert.Nil(t, d.TLSConfig)
+			}
+		})
+	}
+
+	t.Run("it should use the plain dialer without TLS", func(t *testing.T) {
+		// Act
+		mailer := NewSMTPMailer(SMTPConfig{Host: "smtp.example.com", Port: 465, TLSMode: TLSModeNone})
+
+		// Assert
+		d, ok := mailer.dialer.(*plainDialer)
+		require.True(t, ok, "should not use the gomail dialer, as it upgrades with STARTTLS")
+		assert.Equal(t, "smtp.example.com", d.host)
+		assert.Equal(t, 465, d.port)
+	})
+}
+
+func TestSMTPMailer_retryDelay(t *testing.T) {
+	// Arrange
+	mailer := newTestMailer(&fakeDialer{})
+	mailer.config.RetryBaseDelay = 100 * time.Millisecond
+
+	for attempt := 1; attempt <= 4; attempt++ {
+		// Act
+		delay := mailer.retryDelay(attempt)
+
+		// Assert
+		base := mailer.config.RetryBaseDelay << (attempt - 1)
+		assert.GreaterOrEqual(t, delay, base/2, "delay should be at least half of the exponential delay")
+		assert.LessOrEqual(t, delay, base, "delay should not exceed the exponential delay")
+	}
+}
+
+func TestIsTransientSMTPError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "it should retry connection resets", err: syscall.ECONNRESET, expected: true},
+		{name: "it should retry 4xx SMTP replies", err: &textproto.Error{Code: 450, Msg: "Mailbox busy"}, expected: true},
+		{name: "it should not retry authentication failures", err: &textproto.Error{Code: 535, Msg: "Authentication failed"}, expected: false},
+		{name: "it should not retry unknown errors", err: errors.New("boom"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := isTransientSMTPError(tt.err)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
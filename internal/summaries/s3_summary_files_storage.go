@@ -2,71 +2,221 @@ package summaries
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
 )
 
+// ErrObjectTooLarge is returned when an object exceeds the configured maximum size.
+var ErrObjectTooLarge = errors.New("object too large")
+
+// ErrNoCanaryPath is returned by Ping when no canary object is configured.
+var ErrNoCanaryPath = errors.New("no canary path configured")
+
+// versionIDSuffix separates the key from the object version in a path.
+const versionIDSuffix = "?versionId="
+
+// s3Client abstracts the S3 operations used by the storage so the client
+// can be replaced in tests.
+type s3Client interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	GetObjectTagging(ctx context.Context, params *s3.GetObjectTaggingInput, optFns ...func(*s3.Options)) (*s3.GetObjectTaggingOutput, error)
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+}
+
+// S3SummaryFilesStorageConfig holds the configuration of an S3SummaryFilesStorage.
+type S3SummaryFilesStorageConfig struct {
+	// MaxAttempts is the maximum number of attempts for each S3 request, retrying
+	// only transient errors (e.g. 503 Slow Down) (default: 1, i.e. no retries)
+	MaxAttempts int
+
+	// RetryBaseDelay is the delay before the first retry, doubled on every
+	// subsequent retry (default: 100ms)
+	RetryBaseDelay time.Duration
+
+	// AllowMissingEmail only requires the AccountID metadata, returning files
+	// without an AccountEmail with an empty one instead of failing (default:
+	// false, i.e. both are required). Useful for batch jobs that never email.
+	AllowMissingEmail bool
+
+	// MaxObjectBytes is the maximum size of the objects to download. Larger
+	// objects are rejected with ErrObjectTooLarge before being downloaded, at
+	// the cost of an extra HeadObject request (default: 0, i.e. unlimited)
+	MaxObjectBytes int64
+
+	// CanaryPath is the path of an object ("s3://bucket/key") whose existence
+	// Ping checks to verify the connectivity with S3 (default: none, i.e. Ping
+	// fails with ErrNoCanaryPath)
+	CanaryPath string
+}
+
+// DefaultS3SummaryFilesStorageConfig returns the default configuration.
+func DefaultS3SummaryFilesStorageConfig() S3SummaryFilesStorageConfig {
+	return S3SummaryFilesStorageConfig{
+		MaxAttempts:    1,
+		RetryBaseDelay: 100 * time.Millisecond,
+	}
+}
+
 // S3SummaryFilesStorage implements SummaryFilesStorage using AWS S3 as backend.
 type S3SummaryFilesStorage struct {
-	client *s3.Client
+	client s3Client
+	config S3SummaryFilesStorageConfig
+
+	// wait sleeps between retries, and is replaced in tests.
+	wait func(ctx context.Context, delay time.Duration) error
 }
 
 // NewS3SummaryFilesStorage creates a new instance backed by S3.
 func NewS3SummaryFilesStorage(client *s3.Client) *S3SummaryFilesStorage {
-	return &S3SummaryFilesStorage{client: client}
+	return NewS3SummaryFilesStorageWithConfig(client, DefaultS3SummaryFilesStorageConfig())
+}
+
+// NewS3SummaryFilesStorageWithConfig creates a new instance backed by S3 with custom configuration.
+// Non-positive values fall back to the defaults.
+func NewS3SummaryFilesStorageWithConfig(client *s3.Client, config S3SummaryFilesStorageConfig) *S3SummaryFilesStorage {
+	return newS3SummaryFilesStorage(client, config)
+}
+
+// newS3SummaryFilesStorage creates a storage on top of any s3Client.
+func newS3SummaryFilesStorage(client s3Client, config S3SummaryFilesStorageConfig) *S3SummaryFilesStorage {
+	defaults := DefaultS3SummaryFilesStorageConfig()
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.RetryBaseDelay <= 0 {
+		config.RetryBaseDelay = defaults.RetryBaseDelay
+	}
+
+	return &S3SummaryFilesStorage{
+		client: client,
+		config: config,
+		wait:   waitContext,
+	}
 }
 
 // Get retrieves a SummaryFile from S3 by path ("s3://bucket/key" or "bucket/key").
-// It fetches both file content and metadata from object tags.
+// The path may end with "?versionId=..." to retrieve a specific version of
+// the object, on versioned buckets.
+// It fetches both file content and metadata from object tags (or user metadata).
 func (s *S3SummaryFilesStorage) Get(ctx context.Context, path string) (*SummaryFile, error) {
-	bucket, key, err := s.parsePath(path)
+	bucket, key, versionID, err := s.parsePath(path)
 	if err != nil {
 		return nil, fmt.Errorf("invalid S3 path %q: %w", path, err)
 	}
 
+	// Check the size before downloading, so a huge object can't exhaust the memory
+	if s.config.MaxObjectBytes > 0 {
+		head, err := s.headObject(ctx, bucket, key, versionID)
+		if err != nil {
+			return nil, fmt.Errorf("head object s3://%s/%s: %w", bucket, key, err)
+		}
+		if size := aws.ToInt64(head.ContentLength); size > s.config.MaxObjectBytes {
+			return nil, fmt.Errorf("%w: s3://%s/%s is %d bytes, above the %d bytes limit", ErrObjectTooLarge, bucket, key, size, s.config.MaxObjectBytes)
+		}
+	}
+
 	// Fetch content
-	content, err := s.client.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
+	var content *s3.GetObjectOutput
+	err = s.withRetry(ctx, func() (err error) {
+		content, err = s.client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket:    aws.String(bucket),
+			Key:       aws.String(key),
+			VersionId: optionalString(versionID),
+		})
+		return
 	})
 	if err != nil {
 		return nil, fmt.Errorf("get object s3://%s/%s: %w", bucket, key, err)
 	}
 
 	// Fetch metadata (tags)
-	accountID, accountEmail, err := s.getFileMetadata(ctx, bucket, key)
+	accountID, accountEmail, err := s.getFileMetadata(ctx, bucket, key, versionID)
 	if err != nil {
+		content.Body.Close()
 		return nil, err
 	}
 
 	return &SummaryFile{
 		Path:         fmt.Sprintf("s3://%s/%s", bucket, key),
+		VersionID:    versionID,
 		AccountID:    accountID,
 		AccountEmail: accountEmail,
 		Content:      content.Body,
 	}, nil
 }
 
-// parsePath extracts bucket and key from "s3://bucket/key" or "bucket/key".
-func (s *S3SummaryFilesStorage) parsePath(path string) (bucket, key string, err error) {
+// Ping checks the connectivity with S3 (and the permissions to read the
+// objects) with a HeadObject request for the configured canary object.
+func (s *S3SummaryFilesStorage) Ping(ctx context.Context) error {
+	if s.config.CanaryPath == "" {
+		return ErrNoCanaryPath
+	}
+
+	bucket, key, versionID, err := s.parsePath(s.config.CanaryPath)
+	if err != nil {
+		return fmt.Errorf("invalid canary path %q: %w", s.config.CanaryPath, err)
+	}
+
+	if _, err := s.headObject(ctx, bucket, key, versionID); err != nil {
+		return fmt.Errorf("failed to head canary object %s: %w", s.config.CanaryPath, err)
+	}
+	return nil
+}
+
+// parsePath extracts bucket, key and the optional version ID from
+// "s3://bucket/key" or "bucket/key", optionally followed by "?versionId=...".
+func (s *S3SummaryFilesStorage) parsePath(path string) (bucket, key, versionID string, err error) {
 	if path == "" {
-		return "", "", fmt.Errorf("path is empty")
+		return "", "", "", fmt.Errorf("path is empty")
+	}
+	path, versionID, versioned := cutLast(path, versionIDSuffix)
+	if versioned && versionID == "" {
+		return "", "", "", fmt.Errorf("version ID is empty")
 	}
 	parts := strings.SplitN(strings.TrimPrefix(path, "s3://"), "/", 2)
 	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
-		return "", "", fmt.Errorf("path must be 'bucket/key' or 's3://bucket/key'")
+		return "", "", "", fmt.Errorf("path must be 'bucket/key' or 's3://bucket/key'")
+	}
+	return parts[0], parts[1], versionID, nil
+}
+
+// cutLast slices s around the last instance of sep, like strings.Cut does
+// around the first one.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
 	}
-	return parts[0], parts[1], nil
+	return s, "", false
 }
 
-// getFileMetadata extracts AccountID and AccountEmail from S3 object tags.
-func (s *S3SummaryFilesStorage) getFileMetadata(ctx context.Context, bucket, key string) (string, string, error) {
-	tags, err := s.client.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
+// optionalString returns a pointer to s, or nil if s is empty.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
+}
+
+// getFileMetadata extracts AccountID and AccountEmail from S3 object tags,
+// falling back to the object's user metadata (e.g. x-amz-meta-account-id)
+// for the values missing from the tags.
+func (s *S3SummaryFilesStorage) getFileMetadata(ctx context.Context, bucket, key, versionID string) (string, string, error) {
+	var tags *s3.GetObjectTaggingOutput
+	err := s.withRetry(ctx, func() (err error) {
+		tags, err = s.client.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+			Bucket:    aws.String(bucket),
+			Key:       aws.String(key),
+			VersionId: optionalString(versionID),
+		})
+		return
 	})
 	if err != nil {
 		return "", "", fmt.Errorf("get tags s3://%s/%s: %w", bucket, key, err)
@@ -77,16 +227,120 @@ func (s *S3SummaryFilesStorage) getFileMetadata(ctx context.Context, bucket, key
 		if t.Key == nil || t.Value == nil {
 			continue
 		}
-		switch strings.ToLower(*t.Key) {
-		case "accountid", "account_id":
-			accountID = *t.Value
-		case "accountemail", "account_email", "email":
-			accountEmail = *t.Value
-		}
+		assignAccountMetadata(*t.Key, *t.Value, &accountID, &accountEmail)
 	}
 
+	// Tags take precedence; user metadata only fills in what they lack.
 	if accountID == "" || accountEmail == "" {
-		return "", "", fmt.Errorf("missing required tags in s3://%s/%s (found: AccountID=%q, AccountEmail=%q)", bucket, key, accountID, accountEmail)
+		head, err := s.headObject(ctx, bucket, key, versionID)
+		if err != nil {
+			return "", "", fmt.Errorf("head object s3://%s/%s: %w", bucket, key, err)
+		}
+
+		var metadataID, metadataEmail string
+		for name, value := range head.Metadata {
+			assignAccountMetadata(name, value, &metadataID, &metadataEmail)
+		}
+		if accountID == "" {
+			accountID = metadataID
+		}
+		if accountEmail == "" {
+			accountEmail = metadataEmail
+		}
+	}
+
+	if accountID == "" || (accountEmail == "" && !s.config.AllowMissingEmail) {
+		return "", "", fmt.Errorf("missing required tags or metadata in s3://%s/%s (found: AccountID=%q, AccountEmail=%q)", bucket, key, accountID, accountEmail)
 	}
 	return accountID, accountEmail, nil
 }
+
+// headObject retrieves the object's size and user metadata, without its content.
+func (s *S3SummaryFilesStorage) headObject(ctx context.Context, bucket, key, versionID string) (head *s3.HeadObjectOutput, err error) {
+	err = s.withRetry(ctx, func() (err error) {
+		head, err = s.client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket:    aws.String(bucket),
+			Key:       aws.String(key),
+			VersionId: optionalString(versionID),
+		})
+		return
+	})
+	return
+}
+
+// assignAccountMetadata assigns the value of a tag or user metadata entry to
+// the account field its name refers to, if any. Names are case-insensitive.
+func assignAccountMetadata(name, value string, accountID, accountEmail *string) {
+	switch strings.ToLower(name) {
+	case "accountid", "account_id", "account-id":
+		*accountID = value
+	case "accountemail", "account_email", "account-email", "email":
+		*accountEmail = value
+	}
+}
+
+// withRetry calls request up to the configured number of attempts, retrying
+// transient errors with exponential backoff. It returns the last error when
+// giving up, or the context error when the context is done while waiting.
+func (s *S3SummaryFilesStorage) withRetry(ctx context.Context, request func() error) error {
+	// Fall back to a single attempt for storages not built with a constructor.
+	maxAttempts := max(s.config.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = request(); err == nil || !isRetryableS3Error(err) || attempt == maxAttempts {
+			return err
+		}
+
+		if waitErr := s.wait(ctx, s.retryDelay(attempt)); waitErr != nil {
+			return fmt.Errorf("%w (last error: %v)", waitErr, err)
+		}
+	}
+	return err
+}
+
+// retryDelay returns the delay before the retry following the given attempt.
+// The delay doubles on every attempt, and half of it is randomized (jitter)
+// to spread retries out.
+func (s *S3SummaryFilesStorage) retryDelay(attempt int) time.Duration {
+	delay := s.config.RetryBaseDelay << (attempt - 1)
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// retryableS3ErrorCodes are the S3 error codes worth retrying.
+var retryableS3ErrorCodes = map[string]bool{
+	"InternalError":      true,
+	"ServiceUnavailable": true,
+	"SlowDown":           true,
+	"RequestTimeout":     true,
+}
+
+// isRetryableS3Error reports whether an S3 error is worth retrying: server
+// errors (5xx) and throttling are transient, while anything else (e.g. 403
+// Access Denied or 404 No Such Key) is permanent.
+func isRetryableS3Error(err error) bool {
+	var statusErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &statusErr) {
+		switch statusErr.HTTPStatusCode() {
+		case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && retryableS3ErrorCodes[apiErr.ErrorCode()]
+}
+
+// waitContext sleeps for the given delay, returning early with the context
+// error when the context is done.
+func waitContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
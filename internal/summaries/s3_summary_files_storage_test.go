@@ -0,0 +1,587 @@
+package summaries
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3Client is an in-memory s3Client that records the requests it receives.
+type fakeS3Client struct {
+	body     string
+	tags     map[string]string
+	metadata map[string]string
+
+	// getObjectErrs and getTaggingErrs are returned, in order, by the first
+	// calls to GetObject and GetObjectTagging.
+	getObjectErrs  []error
+	getTaggingErrs []error
+
+	// headErr is returned by HeadObject.
+	headErr error
+
+	getObjectInputs  []*s3.GetObjectInput
+	getTaggingInputs []*s3.GetObjectTaggingInput
+	headInputs       []*s3.HeadObjectInput
+	bodies           []*closeRecorder
+}
+
+// closeRecorder is a reader that records whether Close was invoked.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func (c *fakeS3Client) GetObject(_ context.Context, params *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	c.getObjectInputs = append(c.getObjectInputs, params)
+	if len(c.getObjectInputs) <= len(c.getObjectErrs) {
+		return nil, c.getObjectErrs[len(c.getObjectInputs)-1]
+	}
+	body := &closeRecorder{Reader: strings.NewReader(c.body)}
+	c.bodies = append(c.bodies, body)
+	return &s3.GetObjectOutput{Body: body}, nil
+}
+
+func (c *fakeS3Client) GetObjectTagging(_ context.Context, params *s3.GetObjectTaggingInput, _ ...func(*s3.Options)) (*s3.GetObjectTaggingOutput, error) {
+	c.getTaggingInputs = append(c.getTaggingInputs, params)
+	if len(c.getTaggingInputs) <= len(c.getTaggingErrs) {
+		return nil, c.getTaggingErrs[len(c.getTaggingInputs)-1]
+	}
+	output := &s3.GetObjectTaggingOutput{}
+	for key, value := range c.tags {
+		output.TagSet = append(output.TagSet, types.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return output, nil
+}
+
+func (c *fakeS3Client) HeadObject(_ context.Context, params *s3.HeadObjectInput, _ ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	c.headInputs = append(c.headInputs, params)
+	if c.headErr != nil {
+		return nil, c.headErr
+	}
+	return &s3.HeadObjectOutput{ContentLength: aws.Int64(int64(len(c.body))), Metadata: c.metadata}, nil
+}
+
+// newFakeS3Client returns a fakeS3Client holding a tagged CSV file.
+func newFakeS3Client() *fakeS3Client {
+	return &fakeS3Client{
+		body: "Id,Date,Transaction\n0,7/15,+60.5\n",
+		tags: map[string]string{"AccountID": "ACC123", "AccountEmail": "john@example.com"},
+	}
+}
+
+// newS3ResponseError returns an error like the ones returned by the S3 client
+// for a failed response with the given status and error code.
+func newS3ResponseError(operation string, status int, code string) error {
+	return &smithy.OperationError{
+		ServiceID:     "S3",
+		OperationName: operation,
+		Err: &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+				Err:      &smithy.GenericAPIError{Code: code},
+			},
+		},
+	}
+}
+
+// newTestStorage returns a storage using the given client and configuration,
+// recording the retry delays instead of sleeping.
+func newTestStorage(client s3Client, config S3SummaryFilesStorageConfig) (*S3SummaryFilesStorage, *[]time.Duration) {
+	var delays []time.Duration
+	storage := newS3SummaryFilesStorage(client, config)
+	storage.wait = func(ctx context.Context, delay time.Duration) error {
+		delays = append(delays, delay)
+		return ctx.Err()
+	}
+	return storage, &delays
+}
+
+func TestS3SummaryFilesStorage_parsePath(t *testing.T) {
+	tests := []struct {
+		name              string
+		path              string
+		expectedBucket    string
+		expectedKey       string
+		expectedVersionID string
+		expectedErr       string
+	}{
+		{name: "it should parse an s3:// path", path: "s3://bucket/dir/file.csv", expectedBucket: "bucket", expectedKey: "dir/file.csv"},
+		{name: "it should parse a bucket/key path", path: "bucket/file.csv", expectedBucket: "bucket", expectedKey: "file.csv"},
+		{name: "it should parse a versioned path", path: "s3://bucket/dir/file.csv?versionId=3HL4kqtJlcpXroDTDmJ", expectedBucket: "bucket", expectedKey: "dir/file.csv", expectedVersionID: "3HL4kqtJlcpXroDTDmJ"},
+		{name: "it should keep question marks within the key", path: "bucket/file?.csv", expectedBucket: "bucket", expectedKey: "file?.csv"},
+		{name: "it should reject an empty path", path: "", expectedErr: "path is empty"},
+		{name: "it should reject a path without a key", path: "s3://bucket", expectedErr: "path must be 'bucket/key' or 's3://bucket/key'"},
+		{name: "it should reject a versioned path without a key", path: "s3://bucket/?versionId=v1", expectedErr: "path must be 'bucket/key' or 's3://bucket/key'"},
+		{name: "it should reject an empty version ID", path: "s3://bucket/file.csv?versionId=", expectedErr: "version ID is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			storage := &S3SummaryFilesStorage{}
+
+			// Act
+			bucket, key, versionID, err := storage.parsePath(tt.path)
+
+			// Assert
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedBucket, bucket)
+			assert.Equal(t, tt.expectedKey, key)
+			assert.Equal(t, tt.expectedVersionID, versionID)
+		})
+	}
+}
+
+func TestS3SummaryFilesStorage_Get(t *testing.T) {
+	t.Run("it should retrieve a plain path without a version", func(t *testing.T) {
+		// Arrange
+		client := newFakeS3Client()
+		storage := &S3SummaryFilesStorage{client: client}
+
+		// Act
+		file, err := storage.Get(context.Background(), "s3://bucket/file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "s3://bucket/file.csv", file.Path)
+		assert.Empty(t, file.VersionID)
+		assert.Equal(t, "ACC123", file.AccountID)
+		assert.Equal(t, "john@example.com", file.AccountEmail)
+		content, _ := io.ReadAll(file.Content)
+		assert.Equal(t, client.body, string(content))
+
+		require.Len(t, client.getObjectInputs, 1)
+		assert.Equal(t, "bucket", aws.ToString(client.getObjectInputs[0].Bucket))
+		assert.Equal(t, "file.csv", aws.ToString(client.getObjectInputs[0].Key))
+		assert.Nil(t, client.getObjectInputs[0].VersionId)
+		require.Len(t, client.getTaggingInputs, 1)
+		assert.Nil(t, client.getTaggingInputs[0].VersionId)
+	})
+
+	t.Run("it should retrieve the requested version of a versioned path", func(t *testing.T) {
+		// Arrange
+		client := newFakeS3Client()
+		storage := &S3SummaryFilesStorage{client: client}
+
+		// Act
+		file, err := storage.Get(context.Background(), "s3://bucket/file.csv?versionId=v2")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "s3://bucket/file.csv", file.Path)
+		assert.Equal(t, "v2", file.VersionID)
+
+		require.Len(t, client.getObjectInputs, 1)
+		assert.Equal(t, "file.csv", aws.ToString(client.getObjectInputs[0].Key))
+		assert.Equal(t, "v2", aws.ToString(client.getObjectInputs[0].VersionId))
+		require.Len(t, client.getTaggingInputs, 1)
+		assert.Equal(t, "v2", aws.ToString(client.getTaggingInputs[0].VersionId))
+	})
+}
+
+func TestS3SummaryFilesStorage_Get_Metadata(t *testing.T) {
+	tests := []struct {
+		name          string
+		tags          map[string]string
+		metadata      map[string]string
+		expectedID    string
+		expectedEmail string
+		expectedHeads int
+		expectedErr   string
+	}{
+		{
+			name:          "it should read the metadata from the tags",
+			tags:          map[string]string{"AccountID": "ACC123", "AccountEmail": "john@example.com"},
+			metadata:      map[string]string{"account-id": "ACC999", "account-email": "other@example.com"},
+			expectedID:    "ACC123",
+			expectedEmail: "john@example.com",
+			expectedHeads: 0,
+		},
+		{
+			name:          "it should read the metadata from the user metadata headers",
+			metadata:      map[string]string{"account-id": "ACC123", "account-email": "john@example.com"},
+			expectedID:    "ACC123",
+			expectedEmail: "john@example.com",
+			expectedHeads: 1,
+		},
+		{
+			name:          "it should prefer the tags over the user metadata headers",
+			tags:          map[string]string{"AccountID": "ACC123"},
+			metadata:      map[string]string{"account-id": "ACC999", "account-email": "john@example.com"},
+			expectedID:    "ACC123",
+			expectedEmail: "john@example.com",
+			expectedHeads: 1,
+		},
+		{
+			name:          "it should fail when neither has the metadata",
+			tags:          map[string]string{"Owner": "batch"},
+			metadata:      map[string]string{"account-id": "ACC123"},
+			expectedHeads: 1,
+			expectedErr:   `missing required tags or metadata in s3://bucket/file.csv (found: AccountID="ACC123", AccountEmail="")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			client := &fakeS3Client{tags: tt.tags, metadata: tt.metadata}
+			storage := newS3SummaryFilesStorage(client, DefaultS3SummaryFilesStorageConfig())
+
+			// Act
+			file, err := storage.Get(context.Background(), "s3://bucket/file.csv")
+
+			// Assert
+			assert.Len(t, client.headInputs, tt.expectedHeads)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedID, file.AccountID)
+			assert.Equal(t, tt.expectedEmail, file.AccountEmail)
+		})
+	}
+}
+
+func TestS3SummaryFilesStorage_Get_Close(t *testing.T) {
+	t.Run("it should leave the body open for the caller", func(t *testing.T) {
+		// Arrange
+		client := newFakeS3Client()
+		storage := newS3SummaryFilesStorage(client, DefaultS3SummaryFilesStorageConfig())
+
+		// Act
+		_, err := storage.Get(context.Background(), "s3://bucket/file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, client.bodies, 1)
+		assert.False(t, client.bodies[0].closed)
+	})
+
+	t.Run("it should close the body when the metadata can't be retrieved", func(t *testing.T) {
+		// Arrange
+		client := &fakeS3Client{tags: map[string]string{"Owner": "batch"}}
+		storage := newS3SummaryFilesStorage(client, DefaultS3SummaryFilesStorageConfig())
+
+		// Act
+		_, err := storage.Get(context.Background(), "s3://bucket/file.csv")
+
+		// Assert
+		require.Error(t, err)
+		require.Len(t, client.bodies, 1)
+		assert.True(t, client.bodies[0].closed)
+	})
+}
+
+func TestS3SummaryFilesStorage_Get_MaxObjectBytes(t *testing.T) {
+	t.Run("it should reject an object over the limit before downloading it", func(t *testing.T) {
+		// Arrange
+		client := newFakeS3Client()
+		config := DefaultS3SummaryFilesStorageConfig()
+		config.MaxObjectBytes = int64(len(client.body)) - 1
+		storage := newS3SummaryFilesStorage(client, config)
+
+		// Act
+		file, err := storage.Get(context.Background(), "s3://bucket/file.csv?versionId=v2")
+
+		// Assert
+		assert.Nil(t, file)
+		assert.ErrorIs(t, err, ErrObjectTooLarge)
+		require.Len(t, client.headInputs, 1)
+		assert.Equal(t, "v2", aws.ToString(client.headInputs[0].VersionId))
+		assert.Empty(t, client.getObjectInputs)
+	})
+
+	t.Run("it should download an object within the limit", func(t *testing.T) {
+		// Arrange
+		client := newFakeS3Client()
+		config := DefaultS3SummaryFilesStorageConfig()
+		config.MaxObjectBytes = int64(len(client.body))
+		storage := newS3SummaryFilesStorage(client, config)
+
+		// Act
+		file, err := storage.Get(context.Background(), "s3://bucket/file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		content, _ := io.ReadAll(file.Content)
+		assert.Equal(t, client.body, string(content))
+		assert.Len(t, client.headInputs, 1)
+		assert.Len(t, client.getObjectInputs, 1)
+	})
+
+	t.Run("it should not check the size when unlimited", func(t *testing.T) {
+		// Arrange
+		client := newFakeS3Client()
+		storage := newS3SummaryFilesStorage(client, DefaultS3SummaryFilesStorageConfig())
+
+		// Act
+		_, err := storage.Get(context.Background(), "s3://bucket/file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Empty(t, client.headInputs)
+	})
+}
+
+func TestS3SummaryFilesStorage_Ping(t *testing.T) {
+	t.Run("it should head the canary object", func(t *testing.T) {
+		// Arrange
+		client := newFakeS3Client()
+		config := DefaultS3SummaryFilesStorageConfig()
+		config.CanaryPath = "s3://bucket/health/canary.csv"
+		storage := newS3SummaryFilesStorage(client, config)
+
+		// Act
+		err := storage.Ping(context.Background())
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, client.headInputs, 1)
+		assert.Equal(t, "bucket", aws.ToString(client.headInputs[0].Bucket))
+		assert.Equal(t, "health/canary.csv", aws.ToString(client.headInputs[0].Key))
+		assert.Empty(t, client.getObjectInputs, "should not download the canary object")
+	})
+
+	t.Run("it should fail when the canary object can't be headed", func(t *testing.T) {
+		// Arrange
+		client := newFakeS3Client()
+		client.headErr = &smithy.GenericAPIError{Code: "Forbidden", Message: "Access Denied"}
+		config := DefaultS3SummaryFilesStorageConfig()
+		config.CanaryPath = "s3://bucket/health/canary.csv"
+		storage := newS3SummaryFilesStorage(client, config)
+
+		// Act
+		err := storage.Ping(context.Background())
+
+		// Assert
+		assert.ErrorIs(t, err, client.headErr)
+		assert.ErrorContains(t, err, "s3://bucket/health/canary.csv")
+	})
+
+	t.Run("it should fail without a canary object", func(t *testing.T) {
+		// Arrange
+		client := newFakeS3Client()
+		storage := newS3SummaryFilesStorage(client, DefaultS3SummaryFilesStorageConfig())
+
+		// Act
+		err := storage.Ping(context.Background())
+
+		// Assert
+		assert.ErrorIs(t, err, ErrNoCanaryPath)
+		assert.Empty(t, client.headInputs)
+	})
+}
+
+func TestS3SummaryFilesStorage_Get_AllowMissingEmail(t *testing.T) {
+	tests := []struct {
+		name              string
+		allowMissingEmail bool
+		tags              map[string]string
+		expectedEmail     string
+		expectedErr       string
+	}{
+		{
+			name:        "it should require the email in strict mode",
+			tags:        map[string]string{"AccountID": "ACC123"},
+			expectedErr: `missing required tags or metadata in s3://bucket/file.csv (found: AccountID="ACC123", AccountEmail="")`,
+		},
+		{
+			name:              "it should allow a missing email in lenient mode",
+			allowMissingEmail: true,
+			tags:              map[string]string{"AccountID": "ACC123"},
+			expectedEmail:     "",
+		},
+		{
+			name:              "it should keep the email when present in lenient mode",
+			allowMissingEmail: true,
+			tags:              map[string]string{"AccountID": "ACC123", "AccountEmail": "john@example.com"},
+			expectedEmail:     "john@example.com",
+		},
+		{
+			name:              "it should still require the account ID in lenient mode",
+			allowMissingEmail: true,
+			tags:              map[string]string{"AccountEmail": "john@example.com"},
+			expectedErr:       `missing required tags or metadata in s3://bucket/file.csv (found: AccountID="", AccountEmail="john@example.com")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			client := &fakeS3Client{tags: tt.tags}
+			config := DefaultS3SummaryFilesStorageConfig()
+			config.AllowMissingEmail = tt.allowMissingEmail
+			storage := newS3SummaryFilesStorage(client, config)
+
+			// Act
+			file, err := storage.Get(context.Background(), "s3://bucket/file.csv")
+
+			// Assert
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "ACC123", file.AccountID)
+			assert.Equal(t, tt.expectedEmail, file.AccountEmail)
+		})
+	}
+}
+
+func TestS3SummaryFilesStorage_Get_Retry(t *testing.T) {
+	retryConfig := S3SummaryFilesStorageConfig{MaxAttempts: 3, RetryBaseDelay: 10 * time.Millisecond}
+
+	t.Run("it should retry GetObject on 503 until it succeeds", func(t *testing.T) {
+		// Arrange
+		client := newFakeS3Client()
+		client.getObjectErrs = []error{
+			newS3ResponseError("GetObject", http.StatusServiceUnavailable, "SlowDown"),
+			newS3ResponseError("GetObject", http.StatusServiceUnavailable, "SlowDown"),
+		}
+		storage, delays := newTestStorage(client, retryConfig)
+
+		// Act
+		file, err := storage.Get(context.Background(), "s3://bucket/file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "ACC123", file.AccountID)
+		assert.Len(t, client.getObjectInputs, 3)
+		require.Len(t, *delays, 2)
+		assert.LessOrEqual(t, (*delays)[0], 10*time.Millisecond)
+		assert.LessOrEqual(t, (*delays)[1], 20*time.Millisecond)
+	})
+
+	t.Run("it should retry GetObjectTagging on 503 until it succeeds", func(t *testing.T) {
+		// Arrange
+		client := newFakeS3Client()
+		client.getTaggingErrs = []error{
+			newS3ResponseError("GetObjectTagging", http.StatusServiceUnavailable, "ServiceUnavailable"),
+			newS3ResponseError("GetObjectTagging", http.StatusInternalServerError, "InternalError"),
+		}
+		storage, _ := newTestStorage(client, retryConfig)
+
+		// Act
+		file, err := storage.Get(context.Background(), "s3://bucket/file.csv")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "john@example.com", file.AccountEmail)
+		assert.Len(t, client.getObjectInputs, 1)
+		assert.Len(t, client.getTaggingInputs, 3)
+	})
+
+	t.Run("it should give up after the configured attempts", func(t *testing.T) {
+		// Arrange
+		client := newFakeS3Client()
+		client.getObjectErrs = []error{
+			newS3ResponseError("GetObject", http.StatusServiceUnavailable, "SlowDown"),
+			newS3ResponseError("GetObject", http.StatusServiceUnavailable, "SlowDown"),
+			newS3ResponseError("GetObject", http.StatusServiceUnavailable, "SlowDown"),
+		}
+		storage, _ := newTestStorage(client, retryConfig)
+
+		// Act
+		_, err := storage.Get(context.Background(), "s3://bucket/file.csv")
+
+		// Assert
+		assert.ErrorContains(t, err, "get object s3://bucket/file.csv")
+		assert.ErrorContains(t, err, "SlowDown")
+		assert.Len(t, client.getObjectInputs, 3)
+	})
+
+	t.Run("it should not retry permanent errors", func(t *testing.T) {
+		// Arrange
+		client := newFakeS3Client()
+		client.getObjectErrs = []error{newS3ResponseError("GetObject", http.StatusForbidden, "AccessDenied")}
+		storage, delays := newTestStorage(client, retryConfig)
+
+		// Act
+		_, err := storage.Get(context.Background(), "s3://bucket/file.csv")
+
+		// Assert
+		assert.ErrorContains(t, err, "AccessDenied")
+		assert.Len(t, client.getObjectInputs, 1)
+		assert.Empty(t, *delays)
+	})
+
+	t.Run("it should make a single attempt by default", func(t *testing.T) {
+		// Arrange
+		client := newFakeS3Client()
+		client.getObjectErrs = []error{newS3ResponseError("GetObject", http.StatusServiceUnavailable, "SlowDown")}
+		storage, _ := newTestStorage(client, DefaultS3SummaryFilesStorageConfig())
+
+		// Act
+		_, err := storage.Get(context.Background(), "s3://bucket/file.csv")
+
+		// Assert
+		assert.ErrorContains(t, err, "SlowDown")
+		assert.Len(t, client.getObjectInputs, 1)
+	})
+
+	t.Run("it should stop retrying when the context is done", func(t *testing.T) {
+		// Arrange
+		client := newFakeS3Client()
+		client.getObjectErrs = []error{newS3ResponseError("GetObject", http.StatusServiceUnavailable, "SlowDown")}
+		storage, _ := newTestStorage(client, retryConfig)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Act
+		_, err := storage.Get(ctx, "s3://bucket/file.csv")
+
+		// Assert
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorContains(t, err, "SlowDown")
+		assert.Len(t, client.getObjectInputs, 1)
+	})
+}
+
+func TestIsRetryableS3Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "it should retry a 503", err: newS3ResponseError("GetObject", http.StatusServiceUnavailable, "SlowDown"), expected: true},
+		{name: "it should retry a 500", err: newS3ResponseError("GetObject", http.StatusInternalServerError, "InternalError"), expected: true},
+		{name: "it should retry a request timeout", err: newS3ResponseError("GetObject", http.StatusBadRequest, "RequestTimeout"), expected: true},
+		{name: "it should not retry a 403", err: newS3ResponseError("GetObject", http.StatusForbidden, "AccessDenied"), expected: false},
+		{name: "it should not retry a 404", err: newS3ResponseError("GetObject", http.StatusNotFound, "NoSuchKey"), expected: false},
+		{name: "it should not retry other errors", err: errors.New("boom"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual := isRetryableS3Error(tt.err)
+
+			// Assert
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -2,10 +2,16 @@ package summaries
 
 import (
 	"context"
+	"math"
+	"slices"
+	"sort"
 	"stori-challenge/internal/transactions"
 	"time"
 )
 
+// contextCheckInterval is the number of transactions processed between context checks.
+const contextCheckInterval = 1000
+
 // Summarizer defines the interface for calculating transaction summaries.
 type Summarizer interface {
 	// CalculateSummary processes a slice of transactions and returns a comprehensive summary
@@ -13,56 +19,328 @@ type Summarizer interface {
 	CalculateSummary(ctx context.Context, transactions []transactions.Transaction) Summary
 }
 
+// Granularity is a set of periods to aggregate transactions by, combined with |.
+type Granularity uint
+
+const (
+	// GranularityMonthly aggregates transactions by year and month (Summary.YearlyData).
+	// Monthly data is always computed.
+	GranularityMonthly Granularity = 1 << iota
+
+	// GranularityWeekly aggregates transactions by ISO year and week (Summary.WeeklyData).
+	GranularityWeekly
+)
+
+// Includes reports whether the granularity includes all the periods of other.
+func (g Granularity) Includes(other Granularity) bool {
+	return g&other == other
+}
+
+// SummarizerConfig holds the configuration of a DefaultSummarizer.
+type SummarizerConfig struct {
+	// Classifier decides whether each transaction is a debit, a credit or
+	// neither (default: SignClassifier)
+	Classifier Classifier
+
+	// RoundAmounts rounds the monetary values of the summary (the total and
+	// daily balances, and the monthly averages, totals, medians and extremes)
+	// half-up to two decimals. The amounts are accumulated unrounded, only the
+	// reported values are rounded (default: true)
+	RoundAmounts bool
+
+	// OutlierPercentile is the percentile of the month's credits above which
+	// credits are flagged as outliers, see FlagOutliers. Values outside (0, 100]
+	// fall back to the default (default: 95)
+	OutlierPercentile float64
+
+	// OutlierMinCredits is the minimum number of credits of a month to flag its
+	// outliers; smaller months are skipped, as their percentile isn't meaningful.
+	// Non-positive values fall back to the default (default: 20)
+	OutlierMinCredits int
+
+	// Granularity is the set of periods to aggregate transactions by. Monthly
+	// data is always computed; include GranularityWeekly to also compute the
+	// weekly data (default: GranularityMonthly)
+	Granularity Granularity
+}
+
+// DefaultSummarizerConfig returns the default configuration.
+func DefaultSummarizerConfig() SummarizerConfig {
+	return SummarizerConfig{
+		Classifier:        NewSignClassifier(),
+		RoundAmounts:      true,
+		OutlierPercentile: 95,
+		OutlierMinCredits: 20,
+		Granularity:       GranularityMonthly,
+	}
+}
+
 // DefaultSummarizer provides the default implementation of the Summarizer interface.
-type DefaultSummarizer struct{}
+type DefaultSummarizer struct {
+	// classifier decides whether each transaction is a debit, a credit or neither
+	classifier Classifier
+
+	// roundAmounts rounds the monetary values of the summary to two decimals
+	roundAmounts bool
+
+	// outlierPercentile is the percentile of the month's credits above which credits are outliers
+	outlierPercentile float64
+
+	// outlierMinCredits is the minimum number of credits of a month to flag its outliers
+	outlierMinCredits int
+
+	// weekly computes the weekly data of the summaries
+	weekly bool
+}
 
 // NewDefaultSummarizer creates a new instance of DefaultSummarizer.
+// Transactions are classified by the sign of their amount (see SignClassifier).
 func NewDefaultSummarizer() *DefaultSummarizer {
-	return &DefaultSummarizer{}
+	return NewDefaultSummarizerWithConfig(DefaultSummarizerConfig())
+}
+
+// NewDefaultSummarizerWithClassifier creates a new instance of DefaultSummarizer
+// that uses the given Classifier to separate debits from credits.
+func NewDefaultSummarizerWithClassifier(classifier Classifier) *DefaultSummarizer {
+	config := DefaultSummarizerConfig()
+	config.Classifier = classifier
+	return NewDefaultSummarizerWithConfig(config)
+}
+
+// NewDefaultSummarizerWithConfig creates a new instance of DefaultSummarizer with
+// custom configuration. A nil Classifier and invalid outlier settings fall back
+// to the defaults.
+func NewDefaultSummarizerWithConfig(config SummarizerConfig) *DefaultSummarizer {
+	defaults := DefaultSummarizerConfig()
+	if config.Classifier == nil {
+		config.Classifier = defaults.Classifier
+	}
+	if config.OutlierPercentile <= 0 || config.OutlierPercentile > 100 {
+		config.OutlierPercentile = defaults.OutlierPercentile
+	}
+	if config.OutlierMinCredits <= 0 {
+		config.OutlierMinCredits = defaults.OutlierMinCredits
+	}
+
+	return &DefaultSummarizer{
+		classifier:        config.Classifier,
+		roundAmounts:      config.RoundAmounts,
+		outlierPercentile: config.OutlierPercentile,
+		outlierMinCredits: config.OutlierMinCredits,
+		weekly:            config.Granularity.Includes(GranularityWeekly),
+	}
 }
 
 // CalculateSummary implements the Summarizer interface by processing transactions
 // and generating a comprehensive summary with total balance and yearly/monthly data.
+// The summary is always computed completely, ignoring context cancellation
+// (see CalculateSummaryContext for a cancellable variant).
 func (ds *DefaultSummarizer) CalculateSummary(ctx context.Context, txns []transactions.Transaction) Summary {
+	// The context can't be cancelled, so no error is ever returned
+	summary, _ := ds.CalculateSummaryContext(context.WithoutCancel(ctx), txns)
+	return summary
+}
+
+// CalculateSummaryContext processes transactions like CalculateSummary, but checks
+// the context periodically and returns early with the context error when it is
+// cancelled or its deadline is exceeded.
+// Transactions of several currencies are summarized separately, into the
+// Currencies segments of the summary.
+func (ds *DefaultSummarizer) CalculateSummaryContext(ctx context.Context, txns []transactions.Transaction) (Summary, error) {
+	if err := ctx.Err(); err != nil {
+		return Summary{}, err
+	}
+
+	currencyGroups := ds.groupTransactionsByCurrency(txns)
+	if len(currencyGroups) <= 1 {
+		return ds.summarize(ctx, txns)
+	}
+
+	// Amounts of different currencies can't be added up, only counted
+	summary := Summary{
+		TotalTransactionCount: len(txns),
+		YearlyData:            make(YearlyData),
+		WeeklyData:            ds.emptyWeeklyData(),
+		DailyBalances:         make([]DailyBalance, 0),
+		Currencies:            make(map[string]Summary, len(currencyGroups)),
+	}
+	for currency, currencyTxns := range currencyGroups {
+		segment, err := ds.summarize(ctx, currencyTxns)
+		if err != nil {
+			return Summary{}, err
+		}
+		summary.Currencies[currency] = segment
+	}
+
+	return summary, nil
+}
+
+// summarize calculates the summary of transactions of a single currency.
+func (ds *DefaultSummarizer) summarize(ctx context.Context, txns []transactions.Transaction) (Summary, error) {
 	if len(txns) == 0 {
 		return Summary{
-			TotalBalance: 0,
-			YearlyData:   make(YearlyData),
-		}
+			TotalBalance:          0,
+			TotalTransactionCount: 0,
+			YearlyData:            make(YearlyData),
+			WeeklyData:            ds.emptyWeeklyData(),
+			DailyBalances:         make([]DailyBalance, 0),
+		}, nil
 	}
 
 	// Calculate total balance
 	totalBalance := ds.calculateTotalBalance(txns)
 
 	// Group transactions by year and month and calculate data
-	yearlyData := ds.calculateYearlyData(txns)
+	yearlyData, err := ds.calculateYearlyData(ctx, txns)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	// Group transactions by ISO year and week, when enabled
+	var weeklyData WeeklyData
+	if ds.weekly {
+		weeklyData, err = ds.calculateWeeklyData(ctx, txns)
+		if err != nil {
+			return Summary{}, err
+		}
+	}
+
+	// Accumulate the running balance day by day
+	dailyBalances := ds.calculateDailyBalances(txns)
 
 	return Summary{
-		TotalBalance: totalBalance,
-		YearlyData:   yearlyData,
+		TotalBalance:          ds.round(totalBalance),
+		TotalTransactionCount: len(txns),
+		YearlyData:            yearlyData,
+		WeeklyData:            weeklyData,
+		DailyBalances:         dailyBalances,
+		Currency:              txns[0].Currency,
+	}, nil
+}
+
+// groupTransactionsByCurrency groups transactions by currency, keeping their order.
+func (ds *DefaultSummarizer) groupTransactionsByCurrency(txns []transactions.Transaction) map[string][]transactions.Transaction {
+	currencyGroups := make(map[string][]transactions.Transaction)
+	for _, txn := range txns {
+		currencyGroups[txn.Currency] = append(currencyGroups[txn.Currency], txn)
+	}
+	return currencyGroups
+}
+
+// FlagOutliers returns the IDs of the credits above the configured percentile
+// of their month's credits (see SummarizerConfig.OutlierPercentile), grouped by
+// year and month, in the order of the transactions. The percentile is computed
+// with the nearest-rank method. Months with fewer credits than
+// SummarizerConfig.OutlierMinCredits, and months without outliers, are omitted.
+func (ds *DefaultSummarizer) FlagOutliers(ctx context.Context, txns []transactions.Transaction) (map[SummaryYear]map[time.Month][]uint, error) {
+	yearlyGroups, err := ds.groupTransactionsByYearAndMonth(ctx, txns)
+	if err != nil {
+		return nil, err
+	}
+
+	outliers := make(map[SummaryYear]map[time.Month][]uint)
+	for year, monthGroups := range yearlyGroups {
+		for month, monthTxns := range monthGroups {
+			ids := ds.flagMonthOutliers(monthTxns)
+			if len(ids) == 0 {
+				continue
+			}
+			if outliers[year] == nil {
+				outliers[year] = make(map[time.Month][]uint)
+			}
+			outliers[year][month] = ids
+		}
+	}
+
+	return outliers, nil
+}
+
+// flagMonthOutliers returns the IDs of the credits of a month above the
+// configured percentile, or nil when the month has too few credits.
+func (ds *DefaultSummarizer) flagMonthOutliers(monthTxns []transactions.Transaction) []uint {
+	_, credits := ds.separateDebitsAndCredits(monthTxns)
+	if len(credits) < ds.outlierMinCredits {
+		return nil
 	}
+
+	// Nearest-rank percentile: the smallest credit with at least the
+	// percentile of the credits at or below it
+	sorted := slices.Clone(credits)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(ds.outlierPercentile / 100 * float64(len(sorted))))
+	threshold := sorted[max(rank, 1)-1]
+
+	var ids []uint
+	for _, txn := range monthTxns {
+		if ds.classifier.Classify(txn.AmountFloat()) == KindCredit && txn.Amount > threshold {
+			ids = append(ids, txn.ID)
+		}
+	}
+	return ids
 }
 
 // calculateTotalBalance sums all transaction amounts to get the account balance.
+// The amounts are added up exactly, in minor units.
 func (ds *DefaultSummarizer) calculateTotalBalance(txns []transactions.Transaction) float64 {
-	var total float64
+	var total transactions.Money
 	for _, txn := range txns {
 		total += txn.Amount
 	}
-	return total
+	return total.Float64()
+}
+
+// calculateDailyBalances sorts transactions chronologically and accumulates the
+// running balance, collapsing transactions of the same day into a single
+// end-of-day entry.
+func (ds *DefaultSummarizer) calculateDailyBalances(txns []transactions.Transaction) []DailyBalance {
+	// Sort a copy to keep the caller's slice untouched
+	sorted := make([]transactions.Transaction, len(txns))
+	copy(sorted, txns)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	dailyBalances := make([]DailyBalance, 0)
+	var balance transactions.Money
+	for _, txn := range sorted {
+		balance += txn.Amount
+
+		year, month, day := txn.Date.Date()
+		date := time.Date(year, month, day, 0, 0, 0, 0, txn.Date.Location())
+
+		// Same day as the previous entry - update its end-of-day balance
+		if last := len(dailyBalances) - 1; last >= 0 && dailyBalances[last].Date.Equal(date) {
+			dailyBalances[last].Balance = ds.round(balance.Float64())
+			continue
+		}
+
+		dailyBalances = append(dailyBalances, DailyBalance{Date: date, Balance: ds.round(balance.Float64())})
+	}
+
+	return dailyBalances
 }
 
 // calculateYearlyData groups transactions by year and month and calculates aggregated data.
-func (ds *DefaultSummarizer) calculateYearlyData(txns []transactions.Transaction) YearlyData {
-	yearlyGroups := ds.groupTransactionsByYearAndMonth(txns)
-	return ds.computeMonthlyDataFromYearlyGroups(yearlyGroups)
+func (ds *DefaultSummarizer) calculateYearlyData(ctx context.Context, txns []transactions.Transaction) (YearlyData, error) {
+	yearlyGroups, err := ds.groupTransactionsByYearAndMonth(ctx, txns)
+	if err != nil {
+		return nil, err
+	}
+	return ds.computeMonthlyDataFromYearlyGroups(yearlyGroups), nil
 }
 
 // groupTransactionsByYearAndMonth groups transactions by year and then by month.
-func (ds *DefaultSummarizer) groupTransactionsByYearAndMonth(txns []transactions.Transaction) map[SummaryYear]map[time.Month][]transactions.Transaction {
+// The context is checked every contextCheckInterval transactions.
+func (ds *DefaultSummarizer) groupTransactionsByYearAndMonth(ctx context.Context, txns []transactions.Transaction) (map[SummaryYear]map[time.Month][]transactions.Transaction, error) {
 	yearlyGroups := make(map[SummaryYear]map[time.Month][]transactions.Transaction)
 
-	for _, txn := range txns {
+	for i, txn := range txns {
+		if i%contextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
 		year := SummaryYear(txn.Date.Year())
 		month := txn.Date.Month()
 
@@ -73,7 +351,7 @@ func (ds *DefaultSummarizer) groupTransactionsByYearAndMonth(txns []transactions
 		yearlyGroups[year][month] = append(yearlyGroups[year][month], txn)
 	}
 
-	return yearlyGroups
+	return yearlyGroups, nil
 }
 
 // computeMonthlyDataFromYearlyGroups calculates aggregated data for each month from grouped transactions.
@@ -84,45 +362,170 @@ func (ds *DefaultSummarizer) computeMonthlyDataFromYearlyGroups(yearlyGroups map
 		result[year] = make(MonthlyData)
 
 		for month, monthTxns := range monthGroups {
-			debits, credits := ds.separateDebitsAndCredits(monthTxns)
-			avgDebit := ds.calculateAverage(debits)
-			avgCredit := ds.calculateAverage(credits)
-
-			result[year][month] = MonthlySummary{
-				TransactionCount: len(monthTxns),
-				AverageDebit:     avgDebit,
-				AverageCredit:    avgCredit,
-			}
+			result[year][month] = ds.periodSummary(monthTxns)
 		}
 	}
 
 	return result
 }
 
-// separateDebitsAndCredits separates transactions into debits and credits.
-func (ds *DefaultSummarizer) separateDebitsAndCredits(txns []transactions.Transaction) ([]float64, []float64) {
-	var debits, credits []float64
+// calculateWeeklyData groups transactions by ISO year and week and calculates aggregated data.
+// The context is checked every contextCheckInterval transactions.
+func (ds *DefaultSummarizer) calculateWeeklyData(ctx context.Context, txns []transactions.Transaction) (WeeklyData, error) {
+	weeklyGroups := make(map[SummaryYear]map[SummaryWeek][]transactions.Transaction)
+
+	for i, txn := range txns {
+		if i%contextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		// The ISO year may differ from the calendar year around New Year
+		isoYear, isoWeek := txn.Date.ISOWeek()
+		year, week := SummaryYear(isoYear), SummaryWeek(isoWeek)
+
+		if weeklyGroups[year] == nil {
+			weeklyGroups[year] = make(map[SummaryWeek][]transactions.Transaction)
+		}
+
+		weeklyGroups[year][week] = append(weeklyGroups[year][week], txn)
+	}
+
+	result := make(WeeklyData, len(weeklyGroups))
+	for year, weekGroups := range weeklyGroups {
+		result[year] = make(map[SummaryWeek]MonthlySummary, len(weekGroups))
+
+		for week, weekTxns := range weekGroups {
+			result[year][week] = ds.periodSummary(weekTxns)
+		}
+	}
+
+	return result, nil
+}
+
+// emptyWeeklyData returns the weekly data of a summary without transactions:
+// empty when weekly granularity is enabled, nil otherwise.
+func (ds *DefaultSummarizer) emptyWeeklyData() WeeklyData {
+	if !ds.weekly {
+		return nil
+	}
+	return make(WeeklyData)
+}
+
+// periodSummary calculates the aggregated data of the transactions of a period (a month or a week).
+func (ds *DefaultSummarizer) periodSummary(txns []transactions.Transaction) MonthlySummary {
+	debits, credits := ds.separateDebitsAndCredits(txns)
+	minDebit, maxCredit := ds.findExtremes(txns)
+	return ds.monthlySummary(len(txns), debits, credits, minDebit, maxCredit)
+}
+
+// monthlySummary calculates the aggregated data of a period from its
+// transaction count, its debit and credit amounts, and its extremes.
+func (ds *DefaultSummarizer) monthlySummary(count int, debits, credits []transactions.Money, minDebit, maxCredit transactions.Transaction) MonthlySummary {
+	return MonthlySummary{
+		TransactionCount: count,
+		DebitCount:       len(debits),
+		CreditCount:      len(credits),
+		AverageDebit:     ds.round(ds.calculateAverage(debits)),
+		AverageCredit:    ds.round(ds.calculateAverage(credits)),
+		TotalDebit:       ds.round(ds.calculateSum(debits)),
+		TotalCredit:      ds.round(ds.calculateSum(credits)),
+		MedianDebit:      ds.round(ds.calculateMedian(debits)),
+		MedianCredit:     ds.round(ds.calculateMedian(credits)),
+		MinDebit:         ds.round(minDebit.AmountFloat()),
+		MinDebitID:       minDebit.ID,
+		MaxCredit:        ds.round(maxCredit.AmountFloat()),
+		MaxCreditID:      maxCredit.ID,
+	}
+}
+
+// separateDebitsAndCredits separates transactions into debits and credits
+// according to the summarizer's Classifier.
+func (ds *DefaultSummarizer) separateDebitsAndCredits(txns []transactions.Transaction) ([]transactions.Money, []transactions.Money) {
+	var debits, credits []transactions.Money
 	for _, txn := range txns {
-		if txn.Amount < 0 {
+		switch ds.classifier.Classify(txn.AmountFloat()) {
+		case KindDebit:
 			debits = append(debits, txn.Amount)
-		} else if txn.Amount > 0 {
+		case KindCredit:
 			credits = append(credits, txn.Amount)
 		}
 	}
 	return debits, credits
 }
 
-// calculateAverage calculates the average of a slice of float64 values.
+// findExtremes finds the most negative debit and the largest credit transactions.
+// A zero-value transaction is returned for a side without transactions. On ties,
+// the first transaction found wins.
+func (ds *DefaultSummarizer) findExtremes(txns []transactions.Transaction) (minDebit, maxCredit transactions.Transaction) {
+	var hasDebit, hasCredit bool
+	for _, txn := range txns {
+		switch ds.classifier.Classify(txn.AmountFloat()) {
+		case KindDebit:
+			if !hasDebit || txn.Amount < minDebit.Amount {
+				minDebit, hasDebit = txn, true
+			}
+		case KindCredit:
+			if !hasCredit || txn.Amount > maxCredit.Amount {
+				maxCredit, hasCredit = txn, true
+			}
+		}
+	}
+
+	return minDebit, maxCredit
+}
+
+// calculateSum calculates the sum of a slice of amounts, in major units.
+// The amounts are added up exactly, in minor units. Returns 0 if the slice is empty.
+func (ds *DefaultSummarizer) calculateSum(values []transactions.Money) float64 {
+	return sumMoney(values).Float64()
+}
+
+// calculateAverage calculates the average of a slice of amounts, in major units.
+// Returns 0 if the slice is empty.
+func (ds *DefaultSummarizer) calculateAverage(values []transactions.Money) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	return sumMoney(values).Float64() / float64(len(values))
+}
+
+// calculateMedian calculates the median of a slice of amounts, in major units.
+// For an even number of values it returns the average of the two middle values.
 // Returns 0 if the slice is empty.
-func (ds *DefaultSummarizer) calculateAverage(values []float64) float64 {
+func (ds *DefaultSummarizer) calculateMedian(values []transactions.Money) float64 {
 	if len(values) == 0 {
 		return 0
 	}
 
-	var sum float64
+	// Sort a copy to keep the caller's slice untouched
+	sorted := make([]transactions.Money, len(values))
+	copy(sorted, values)
+	slices.Sort(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]).Float64() / 2
+	}
+	return sorted[middle].Float64()
+}
+
+// sumMoney adds up a slice of amounts exactly.
+func sumMoney(values []transactions.Money) transactions.Money {
+	var sum transactions.Money
 	for _, value := range values {
 		sum += value
 	}
+	return sum
+}
 
-	return sum / float64(len(values))
+// round rounds a monetary value half-up (away from zero) to two decimals,
+// unless rounding is disabled (see SummarizerConfig.RoundAmounts).
+func (ds *DefaultSummarizer) round(value float64) float64 {
+	if !ds.roundAmounts {
+		return value
+	}
+	return math.Round(value*100) / 100
 }
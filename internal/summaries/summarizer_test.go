@@ -8,11 +8,12 @@ import (
 	"stori-challenge/internal/transactions"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDefaultSummarizer_CalculateSummary(t *testing.T) {
 	// Arrange
-	summarizer := NewDefaultSummarizer()
+	summarizer := NewDefaultSummarizerWithConfig(SummarizerConfig{RoundAmounts: false})
 
 	tests := []struct {
 		name                 string
@@ -29,15 +30,25 @@ func TestDefaultSummarizer_CalculateSummary(t *testing.T) {
 		{
 			name: "it should calculate summary for single transaction",
 			transactions: []transactions.Transaction{
-				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100.50},
+				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100_50},
 			},
 			expectedTotalBalance: 100.50,
 			expectedYearlyData: YearlyData{
 				SummaryYear(2023): MonthlyData{
 					time.July: MonthlySummary{
 						TransactionCount: 1,
+						DebitCount:       0,
+						CreditCount:      1,
 						AverageDebit:     0,
 						AverageCredit:    100.50,
+						TotalDebit:       0,
+						TotalCredit:      100.50,
+						MedianDebit:      0,
+						MedianCredit:     100.50,
+						MinDebit:         0,
+						MinDebitID:       0,
+						MaxCredit:        100.50,
+						MaxCreditID:      1,
 					},
 				},
 			},
@@ -45,17 +56,27 @@ func TestDefaultSummarizer_CalculateSummary(t *testing.T) {
 		{
 			name: "it should calculate summary for multiple transactions in same month",
 			transactions: []transactions.Transaction{
-				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100.00},
-				{ID: 2, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: -50.00},
-				{ID: 3, Date: time.Date(2023, time.July, 25, 0, 0, 0, 0, time.UTC), Amount: 200.00},
+				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100_00},
+				{ID: 2, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: -50_00},
+				{ID: 3, Date: time.Date(2023, time.July, 25, 0, 0, 0, 0, time.UTC), Amount: 200_00},
 			},
 			expectedTotalBalance: 250.00,
 			expectedYearlyData: YearlyData{
 				SummaryYear(2023): MonthlyData{
 					time.July: MonthlySummary{
 						TransactionCount: 3,
+						DebitCount:       1,
+						CreditCount:      2,
 						AverageDebit:     -50.00,
 						AverageCredit:    150.00, // (100 + 200) / 2
+						TotalDebit:       -50.00,
+						TotalCredit:      300.00,
+						MedianDebit:      -50.00,
+						MedianCredit:     150.00,
+						MinDebit:         -50.00,
+						MinDebitID:       2,
+						MaxCredit:        200.00,
+						MaxCreditID:      3,
 					},
 				},
 			},
@@ -63,22 +84,42 @@ func TestDefaultSummarizer_CalculateSummary(t *testing.T) {
 		{
 			name: "it should calculate summary for multiple transactions across different months",
 			transactions: []transactions.Transaction{
-				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100.00},
-				{ID: 2, Date: time.Date(2023, time.August, 10, 0, 0, 0, 0, time.UTC), Amount: -30.00},
-				{ID: 3, Date: time.Date(2023, time.August, 20, 0, 0, 0, 0, time.UTC), Amount: 75.00},
+				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100_00},
+				{ID: 2, Date: time.Date(2023, time.August, 10, 0, 0, 0, 0, time.UTC), Amount: -30_00},
+				{ID: 3, Date: time.Date(2023, time.August, 20, 0, 0, 0, 0, time.UTC), Amount: 75_00},
 			},
 			expectedTotalBalance: 145.00,
 			expectedYearlyData: YearlyData{
 				SummaryYear(2023): MonthlyData{
 					time.July: MonthlySummary{
 						TransactionCount: 1,
+						DebitCount:       0,
+						CreditCount:      1,
 						AverageDebit:     0,
 						AverageCredit:    100.00,
+						TotalDebit:       0,
+						TotalCredit:      100.00,
+						MedianDebit:      0,
+						MedianCredit:     100.00,
+						MinDebit:         0,
+						MinDebitID:       0,
+						MaxCredit:        100.00,
+						MaxCreditID:      1,
 					},
 					time.August: MonthlySummary{
 						TransactionCount: 2,
+						DebitCount:       1,
+						CreditCount:      1,
 						AverageDebit:     -30.00,
 						AverageCredit:    75.00,
+						TotalDebit:       -30.00,
+						TotalCredit:      75.00,
+						MedianDebit:      -30.00,
+						MedianCredit:     75.00,
+						MinDebit:         -30.00,
+						MinDebitID:       2,
+						MaxCredit:        75.00,
+						MaxCreditID:      3,
 					},
 				},
 			},
@@ -86,24 +127,76 @@ func TestDefaultSummarizer_CalculateSummary(t *testing.T) {
 		{
 			name: "it should calculate summary for transactions across different years",
 			transactions: []transactions.Transaction{
-				{ID: 1, Date: time.Date(2022, time.December, 31, 0, 0, 0, 0, time.UTC), Amount: 50.00},
-				{ID: 2, Date: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), Amount: -25.00},
-				{ID: 3, Date: time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC), Amount: 100.00},
+				{ID: 1, Date: time.Date(2022, time.December, 31, 0, 0, 0, 0, time.UTC), Amount: 50_00},
+				{ID: 2, Date: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), Amount: -25_00},
+				{ID: 3, Date: time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC), Amount: 100_00},
 			},
 			expectedTotalBalance: 125.00,
 			expectedYearlyData: YearlyData{
 				SummaryYear(2022): MonthlyData{
 					time.December: MonthlySummary{
 						TransactionCount: 1,
+						DebitCount:       0,
+						CreditCount:      1,
 						AverageDebit:     0,
 						AverageCredit:    50.00,
+						TotalDebit:       0,
+						TotalCredit:      50.00,
+						MedianDebit:      0,
+						MedianCredit:     50.00,
+						MinDebit:         0,
+						MinDebitID:       0,
+						MaxCredit:        50.00,
+						MaxCreditID:      1,
 					},
 				},
 				SummaryYear(2023): MonthlyData{
 					time.January: MonthlySummary{
 						TransactionCount: 2,
+						DebitCount:       1,
+						CreditCount:      1,
 						AverageDebit:     -25.00,
 						AverageCredit:    100.00,
+						TotalDebit:       -25.00,
+						TotalCredit:      100.00,
+						MedianDebit:      -25.00,
+						MedianCredit:     100.00,
+						MinDebit:         -25.00,
+						MinDebitID:       2,
+						MaxCredit:        100.00,
+						MaxCreditID:      3,
+					},
+				},
+			},
+		},
+		{
+			name: "it should report medians unaffected by outliers",
+			transactions: []transactions.Transaction{
+				{ID: 1, Date: time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC), Amount: -10_00},
+				{ID: 2, Date: time.Date(2023, time.July, 2, 0, 0, 0, 0, time.UTC), Amount: -1000_00},
+				{ID: 3, Date: time.Date(2023, time.July, 3, 0, 0, 0, 0, time.UTC), Amount: -20_00},
+				{ID: 4, Date: time.Date(2023, time.July, 4, 0, 0, 0, 0, time.UTC), Amount: 10_00},
+				{ID: 5, Date: time.Date(2023, time.July, 5, 0, 0, 0, 0, time.UTC), Amount: 5000_00},
+				{ID: 6, Date: time.Date(2023, time.July, 6, 0, 0, 0, 0, time.UTC), Amount: 30_00},
+				{ID: 7, Date: time.Date(2023, time.July, 7, 0, 0, 0, 0, time.UTC), Amount: 20_00},
+			},
+			expectedTotalBalance: 4030.00,
+			expectedYearlyData: YearlyData{
+				SummaryYear(2023): MonthlyData{
+					time.July: MonthlySummary{
+						TransactionCount: 7,
+						DebitCount:       3,
+						CreditCount:      4,
+						AverageDebit:     -343.3333333333333, // (-10 + -1000 + -20) / 3
+						AverageCredit:    1265.00,            // (10 + 5000 + 30 + 20) / 4
+						TotalDebit:       -1030.00,
+						TotalCredit:      5060.00,
+						MedianDebit:      -20.00, // middle of [-1000, -20, -10]
+						MedianCredit:     25.00,  // (20 + 30) / 2
+						MinDebit:         -1000.00,
+						MinDebitID:       2,
+						MaxCredit:        5000.00,
+						MaxCreditID:      5,
 					},
 				},
 			},
@@ -111,16 +204,26 @@ func TestDefaultSummarizer_CalculateSummary(t *testing.T) {
 		{
 			name: "it should handle only debit transactions",
 			transactions: []transactions.Transaction{
-				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: -100.00},
-				{ID: 2, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: -50.00},
+				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: -100_00},
+				{ID: 2, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: -50_00},
 			},
 			expectedTotalBalance: -150.00,
 			expectedYearlyData: YearlyData{
 				SummaryYear(2023): MonthlyData{
 					time.July: MonthlySummary{
 						TransactionCount: 2,
+						DebitCount:       2,
+						CreditCount:      0,
 						AverageDebit:     -75.00, // (-100 + -50) / 2
 						AverageCredit:    0,
+						TotalDebit:       -150.00,
+						TotalCredit:      0,
+						MedianDebit:      -75.00,
+						MedianCredit:     0,
+						MinDebit:         -100.00,
+						MinDebitID:       1,
+						MaxCredit:        0,
+						MaxCreditID:      0,
 					},
 				},
 			},
@@ -129,15 +232,25 @@ func TestDefaultSummarizer_CalculateSummary(t *testing.T) {
 			name: "it should handle zero amount transactions",
 			transactions: []transactions.Transaction{
 				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 0},
-				{ID: 2, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: 100.00},
+				{ID: 2, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: 100_00},
 			},
 			expectedTotalBalance: 100.00,
 			expectedYearlyData: YearlyData{
 				SummaryYear(2023): MonthlyData{
 					time.July: MonthlySummary{
 						TransactionCount: 2,
+						DebitCount:       0,
+						CreditCount:      1,
 						AverageDebit:     0,
 						AverageCredit:    100.00,
+						TotalDebit:       0,
+						TotalCredit:      100.00,
+						MedianDebit:      0,
+						MedianCredit:     100.00,
+						MinDebit:         0,
+						MinDebitID:       0,
+						MaxCredit:        100.00,
+						MaxCreditID:      2,
 					},
 				},
 			},
@@ -152,6 +265,12 @@ func TestDefaultSummarizer_CalculateSummary(t *testing.T) {
 			// Assert
 			assert.Equal(t, tt.expectedTotalBalance, result.TotalBalance, "Total balance should match")
 			assert.Equal(t, tt.expectedYearlyData, result.YearlyData, "Yearly data should match")
+			assert.Equal(t, len(tt.transactions), result.TotalTransactionCount, "Total transaction count should match input length")
+			if len(tt.transactions) > 0 {
+				assert.Equal(t, tt.expectedTotalBalance, result.DailyBalances[len(result.DailyBalances)-1].Balance, "Last daily balance should match total balance")
+			} else {
+				assert.Empty(t, result.DailyBalances, "Daily balances should be empty")
+			}
 		})
 	}
 }
@@ -173,25 +292,25 @@ func TestDefaultSummarizer_calculateTotalBalance(t *testing.T) {
 		{
 			name: "it should sum positive amounts",
 			transactions: []transactions.Transaction{
-				{ID: 1, Amount: 100.50},
-				{ID: 2, Amount: 200.25},
+				{ID: 1, Amount: 100_50},
+				{ID: 2, Amount: 200_25},
 			},
 			expected: 300.75,
 		},
 		{
 			name: "it should sum negative amounts",
 			transactions: []transactions.Transaction{
-				{ID: 1, Amount: -50.00},
-				{ID: 2, Amount: -25.50},
+				{ID: 1, Amount: -50_00},
+				{ID: 2, Amount: -25_50},
 			},
 			expected: -75.50,
 		},
 		{
 			name: "it should sum mixed amounts",
 			transactions: []transactions.Transaction{
-				{ID: 1, Amount: 100.00},
-				{ID: 2, Amount: -30.00},
-				{ID: 3, Amount: 50.00},
+				{ID: 1, Amount: 100_00},
+				{ID: 2, Amount: -30_00},
+				{ID: 3, Amount: 50_00},
 			},
 			expected: 120.00,
 		},
@@ -208,6 +327,59 @@ func TestDefaultSummarizer_calculateTotalBalance(t *testing.T) {
 	}
 }
 
+func TestDefaultSummarizer_calculateDailyBalances(t *testing.T) {
+	// Arrange
+	summarizer := NewDefaultSummarizer()
+
+	tests := []struct {
+		name         string
+		transactions []transactions.Transaction
+		expected     []DailyBalance
+	}{
+		{
+			name:         "it should return empty series for no transactions",
+			transactions: []transactions.Transaction{},
+			expected:     []DailyBalance{},
+		},
+		{
+			name: "it should accumulate the balance across multiple days in chronological order",
+			transactions: []transactions.Transaction{
+				{ID: 1, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: -50_00},
+				{ID: 2, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100_00},
+				{ID: 3, Date: time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC), Amount: 25_00},
+			},
+			expected: []DailyBalance{
+				{Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Balance: 100.00},
+				{Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Balance: 50.00},
+				{Date: time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC), Balance: 75.00},
+			},
+		},
+		{
+			name: "it should collapse same-day transactions into the end-of-day balance",
+			transactions: []transactions.Transaction{
+				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100_00},
+				{ID: 2, Date: time.Date(2023, time.July, 15, 18, 30, 0, 0, time.UTC), Amount: -30_00},
+				{ID: 3, Date: time.Date(2023, time.July, 15, 9, 0, 0, 0, time.UTC), Amount: 10_00},
+				{ID: 4, Date: time.Date(2023, time.July, 16, 0, 0, 0, 0, time.UTC), Amount: -20_00},
+			},
+			expected: []DailyBalance{
+				{Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Balance: 80.00},
+				{Date: time.Date(2023, time.July, 16, 0, 0, 0, 0, time.UTC), Balance: 60.00},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := summarizer.calculateDailyBalances(tt.transactions)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
 func TestDefaultSummarizer_separateDebitsAndCredits(t *testing.T) {
 	// Arrange
 	summarizer := NewDefaultSummarizer()
@@ -215,8 +387,8 @@ func TestDefaultSummarizer_separateDebitsAndCredits(t *testing.T) {
 	tests := []struct {
 		name            string
 		transactions    []transactions.Transaction
-		expectedDebits  []float64
-		expectedCredits []float64
+		expectedDebits  []transactions.Money
+		expectedCredits []transactions.Money
 	}{
 		{
 			name:            "it should return empty slices for no transactions",
@@ -227,41 +399,41 @@ func TestDefaultSummarizer_separateDebitsAndCredits(t *testing.T) {
 		{
 			name: "it should separate debits and credits",
 			transactions: []transactions.Transaction{
-				{ID: 1, Amount: 100.00},
-				{ID: 2, Amount: -50.00},
-				{ID: 3, Amount: 200.00},
-				{ID: 4, Amount: -25.00},
+				{ID: 1, Amount: 100_00},
+				{ID: 2, Amount: -50_00},
+				{ID: 3, Amount: 200_00},
+				{ID: 4, Amount: -25_00},
 			},
-			expectedDebits:  []float64{-50.00, -25.00},
-			expectedCredits: []float64{100.00, 200.00},
+			expectedDebits:  []transactions.Money{-50_00, -25_00},
+			expectedCredits: []transactions.Money{100_00, 200_00},
 		},
 		{
 			name: "it should handle only credits",
 			transactions: []transactions.Transaction{
-				{ID: 1, Amount: 100.00},
-				{ID: 2, Amount: 200.00},
+				{ID: 1, Amount: 100_00},
+				{ID: 2, Amount: 200_00},
 			},
 			expectedDebits:  nil,
-			expectedCredits: []float64{100.00, 200.00},
+			expectedCredits: []transactions.Money{100_00, 200_00},
 		},
 		{
 			name: "it should handle only debits",
 			transactions: []transactions.Transaction{
-				{ID: 1, Amount: -50.00},
-				{ID: 2, Amount: -75.00},
+				{ID: 1, Amount: -50_00},
+				{ID: 2, Amount: -75_00},
 			},
-			expectedDebits:  []float64{-50.00, -75.00},
+			expectedDebits:  []transactions.Money{-50_00, -75_00},
 			expectedCredits: nil,
 		},
 		{
 			name: "it should ignore zero amounts",
 			transactions: []transactions.Transaction{
 				{ID: 1, Amount: 0},
-				{ID: 2, Amount: 100.00},
-				{ID: 3, Amount: -50.00},
+				{ID: 2, Amount: 100_00},
+				{ID: 3, Amount: -50_00},
 			},
-			expectedDebits:  []float64{-50.00},
-			expectedCredits: []float64{100.00},
+			expectedDebits:  []transactions.Money{-50_00},
+			expectedCredits: []transactions.Money{100_00},
 		},
 	}
 
@@ -277,38 +449,153 @@ func TestDefaultSummarizer_separateDebitsAndCredits(t *testing.T) {
 	}
 }
 
+func TestDefaultSummarizer_findExtremes(t *testing.T) {
+	// Arrange
+	summarizer := NewDefaultSummarizer()
+
+	tests := []struct {
+		name              string
+		transactions      []transactions.Transaction
+		expectedMinDebit  transactions.Transaction
+		expectedMaxCredit transactions.Transaction
+	}{
+		{
+			name:              "it should return zero values for no transactions",
+			transactions:      []transactions.Transaction{},
+			expectedMinDebit:  transactions.Transaction{},
+			expectedMaxCredit: transactions.Transaction{},
+		},
+		{
+			name: "it should find the extremes across a multi-transaction month",
+			transactions: []transactions.Transaction{
+				{ID: 1, Amount: -20_00},
+				{ID: 2, Amount: 150_00},
+				{ID: 3, Amount: -300_00},
+				{ID: 4, Amount: 0},
+				{ID: 5, Amount: 900_00},
+				{ID: 6, Amount: -10_00},
+				{ID: 7, Amount: 40_00},
+			},
+			expectedMinDebit:  transactions.Transaction{ID: 3, Amount: -300_00},
+			expectedMaxCredit: transactions.Transaction{ID: 5, Amount: 900_00},
+		},
+		{
+			name: "it should keep the first transaction on ties",
+			transactions: []transactions.Transaction{
+				{ID: 1, Amount: -50_00},
+				{ID: 2, Amount: 75_00},
+				{ID: 3, Amount: -50_00},
+				{ID: 4, Amount: 75_00},
+			},
+			expectedMinDebit:  transactions.Transaction{ID: 1, Amount: -50_00},
+			expectedMaxCredit: transactions.Transaction{ID: 2, Amount: 75_00},
+		},
+		{
+			name: "it should leave the credit side empty for debit-only months",
+			transactions: []transactions.Transaction{
+				{ID: 1, Amount: -50_00},
+				{ID: 2, Amount: -75_00},
+			},
+			expectedMinDebit:  transactions.Transaction{ID: 2, Amount: -75_00},
+			expectedMaxCredit: transactions.Transaction{},
+		},
+		{
+			name: "it should leave the debit side empty for credit-only months",
+			transactions: []transactions.Transaction{
+				{ID: 1, Amount: 50_00},
+				{ID: 2, Amount: 75_00},
+			},
+			expectedMinDebit:  transactions.Transaction{},
+			expectedMaxCredit: transactions.Transaction{ID: 2, Amount: 75_00},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			minDebit, maxCredit := summarizer.findExtremes(tt.transactions)
+
+			// Assert
+			assert.Equal(t, tt.expectedMinDebit, minDebit, "Min debit should match")
+			assert.Equal(t, tt.expectedMaxCredit, maxCredit, "Max credit should match")
+		})
+	}
+}
+
+func TestDefaultSummarizer_calculateSum(t *testing.T) {
+	// Arrange
+	summarizer := NewDefaultSummarizer()
+
+	tests := []struct {
+		name     string
+		values   []transactions.Money
+		expected float64
+	}{
+		{
+			name:     "it should return zero for empty slice",
+			values:   []transactions.Money{},
+			expected: 0,
+		},
+		{
+			name:     "it should sum credit-only values",
+			values:   []transactions.Money{100_00, 200_00, 300_00},
+			expected: 600.00,
+		},
+		{
+			name:     "it should sum debit-only values",
+			values:   []transactions.Money{-50_00, -100_00},
+			expected: -150.00,
+		},
+		{
+			name:     "it should sum mixed values",
+			values:   []transactions.Money{-50_00, 100_00, 200_00},
+			expected: 250.00,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := summarizer.calculateSum(tt.values)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
 func TestDefaultSummarizer_calculateAverage(t *testing.T) {
 	// Arrange
 	summarizer := NewDefaultSummarizer()
 
 	tests := []struct {
 		name     string
-		values   []float64
+		values   []transactions.Money
 		expected float64
 	}{
 		{
 			name:     "it should return zero for empty slice",
-			values:   []float64{},
+			values:   []transactions.Money{},
 			expected: 0,
 		},
 		{
 			name:     "it should calculate average for single value",
-			values:   []float64{100.00},
+			values:   []transactions.Money{100_00},
 			expected: 100.00,
 		},
 		{
 			name:     "it should calculate average for multiple values",
-			values:   []float64{100.00, 200.00, 300.00},
+			values:   []transactions.Money{100_00, 200_00, 300_00},
 			expected: 200.00,
 		},
 		{
 			name:     "it should calculate average for negative values",
-			values:   []float64{-50.00, -100.00},
+			values:   []transactions.Money{-50_00, -100_00},
 			expected: -75.00,
 		},
 		{
 			name:     "it should calculate average for mixed values",
-			values:   []float64{-50.00, 100.00, 200.00},
+			values:   []transactions.Money{-50_00, 100_00, 200_00},
 			expected: 83.33333333333333,
 		},
 	}
@@ -324,6 +611,58 @@ func TestDefaultSummarizer_calculateAverage(t *testing.T) {
 	}
 }
 
+func TestDefaultSummarizer_calculateMedian(t *testing.T) {
+	// Arrange
+	summarizer := NewDefaultSummarizer()
+
+	tests := []struct {
+		name     string
+		values   []transactions.Money
+		expected float64
+	}{
+		{
+			name:     "it should return zero for empty slice",
+			values:   []transactions.Money{},
+			expected: 0,
+		},
+		{
+			name:     "it should return the value for single value",
+			values:   []transactions.Money{100_00},
+			expected: 100.00,
+		},
+		{
+			name:     "it should return the middle value for odd length",
+			values:   []transactions.Money{300_00, 100_00, 200_00},
+			expected: 200.00,
+		},
+		{
+			name:     "it should average the two middle values for even length",
+			values:   []transactions.Money{400_00, 100_00, 300_00, 200_00},
+			expected: 250.00,
+		},
+		{
+			name:     "it should calculate median for negative values",
+			values:   []transactions.Money{-50_00, -1000_00, -75_00},
+			expected: -75.00,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			original := make([]transactions.Money, len(tt.values))
+			copy(original, tt.values)
+
+			// Act
+			result := summarizer.calculateMedian(tt.values)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, original, tt.values, "Input slice should not be reordered")
+		})
+	}
+}
+
 func TestDefaultSummarizer_groupTransactionsByYearAndMonth(t *testing.T) {
 	// Arrange
 	summarizer := NewDefaultSummarizer()
@@ -341,24 +680,24 @@ func TestDefaultSummarizer_groupTransactionsByYearAndMonth(t *testing.T) {
 		{
 			name: "it should group transactions by year and month",
 			transactions: []transactions.Transaction{
-				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100.00},
-				{ID: 2, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: -50.00},
-				{ID: 3, Date: time.Date(2023, time.August, 10, 0, 0, 0, 0, time.UTC), Amount: 200.00},
-				{ID: 4, Date: time.Date(2024, time.July, 5, 0, 0, 0, 0, time.UTC), Amount: 75.00},
+				{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100_00},
+				{ID: 2, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: -50_00},
+				{ID: 3, Date: time.Date(2023, time.August, 10, 0, 0, 0, 0, time.UTC), Amount: 200_00},
+				{ID: 4, Date: time.Date(2024, time.July, 5, 0, 0, 0, 0, time.UTC), Amount: 75_00},
 			},
 			expected: map[SummaryYear]map[time.Month][]transactions.Transaction{
 				SummaryYear(2023): {
 					time.July: {
-						{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100.00},
-						{ID: 2, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: -50.00},
+						{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100_00},
+						{ID: 2, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: -50_00},
 					},
 					time.August: {
-						{ID: 3, Date: time.Date(2023, time.August, 10, 0, 0, 0, 0, time.UTC), Amount: 200.00},
+						{ID: 3, Date: time.Date(2023, time.August, 10, 0, 0, 0, 0, time.UTC), Amount: 200_00},
 					},
 				},
 				SummaryYear(2024): {
 					time.July: {
-						{ID: 4, Date: time.Date(2024, time.July, 5, 0, 0, 0, 0, time.UTC), Amount: 75.00},
+						{ID: 4, Date: time.Date(2024, time.July, 5, 0, 0, 0, 0, time.UTC), Amount: 75_00},
 					},
 				},
 			},
@@ -368,14 +707,400 @@ func TestDefaultSummarizer_groupTransactionsByYearAndMonth(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Act
-			result := summarizer.groupTransactionsByYearAndMonth(tt.transactions)
+			result, err := summarizer.groupTransactionsByYearAndMonth(context.Background(), tt.transactions)
 
 			// Assert
+			assert.NoError(t, err)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
 
+func TestDefaultSummarizer_CalculateSummaryContext(t *testing.T) {
+	// Arrange
+	summarizer := NewDefaultSummarizer()
+	txns := make([]transactions.Transaction, 0, 3*contextCheckInterval)
+	for i := 0; i < 3*contextCheckInterval; i++ {
+		txns = append(txns, transactions.Transaction{
+			ID:     uint(i + 1),
+			Date:   time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC),
+			Amount: 10_00,
+		})
+	}
+
+	t.Run("it should calculate the summary when the context is active", func(t *testing.T) {
+		// Act
+		result, err := summarizer.CalculateSummaryContext(context.Background(), txns)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, len(txns), result.TotalTransactionCount)
+		assert.Equal(t, summarizer.CalculateSummary(context.Background(), txns), result)
+	})
+
+	t.Run("it should return the context error when the context is cancelled", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Act
+		result, err := summarizer.CalculateSummaryContext(ctx, txns)
+
+		// Assert
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, Summary{}, result)
+	})
+
+	t.Run("it should stop grouping when the context is cancelled mid-computation", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Act
+		result, err := summarizer.groupTransactionsByYearAndMonth(ctx, txns)
+
+		// Assert
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, result)
+	})
+
+	t.Run("it should ignore cancellation in CalculateSummary for compatibility", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Act
+		result := summarizer.CalculateSummary(ctx, txns)
+
+		// Assert
+		assert.Equal(t, len(txns), result.TotalTransactionCount)
+	})
+}
+
+func TestDefaultSummarizer_RoundAmounts(t *testing.T) {
+	txns := []transactions.Transaction{
+		{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 50_00},
+		{ID: 2, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: 100_00},
+		{ID: 3, Date: time.Date(2023, time.July, 25, 0, 0, 0, 0, time.UTC), Amount: 100_00},
+		{ID: 4, Date: time.Date(2023, time.July, 28, 0, 0, 0, 0, time.UTC), Amount: -10},
+		{ID: 5, Date: time.Date(2023, time.July, 29, 0, 0, 0, 0, time.UTC), Amount: -15},
+	}
+
+	tests := []struct {
+		name                  string
+		summarizer            *DefaultSummarizer
+		expectedAverageCredit float64
+		expectedAverageDebit  float64
+		expectedTotalBalance  float64
+	}{
+		{
+			name:                  "it should round to two decimals by default",
+			summarizer:            NewDefaultSummarizer(),
+			expectedAverageCredit: 83.33,
+			expectedAverageDebit:  -0.13,
+			expectedTotalBalance:  249.75,
+		},
+		{
+			name:                  "it should report the raw values when rounding is disabled",
+			summarizer:            NewDefaultSummarizerWithConfig(SummarizerConfig{RoundAmounts: false}),
+			expectedAverageCredit: 83.33333333333333,
+			expectedAverageDebit:  -0.125,
+			expectedTotalBalance:  249.75,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := tt.summarizer.CalculateSummary(context.Background(), txns)
+
+			// Assert
+			july := result.YearlyData[SummaryYear(2023)][time.July]
+			assert.Equal(t, tt.expectedAverageCredit, july.AverageCredit)
+			assert.Equal(t, tt.expectedAverageDebit, july.AverageDebit)
+			assert.Equal(t, tt.expectedTotalBalance, result.TotalBalance)
+			assert.Equal(t, tt.expectedTotalBalance, result.DailyBalances[len(result.DailyBalances)-1].Balance)
+		})
+	}
+}
+
+func TestDefaultSummarizer_ExactTotals(t *testing.T) {
+	t.Run("it should add up the amounts without float64 drift", func(t *testing.T) {
+		// Arrange
+		summarizer := NewDefaultSummarizerWithConfig(SummarizerConfig{RoundAmounts: false})
+		txns := make([]transactions.Transaction, 1000)
+		for i := range txns {
+			txns[i] = transactions.Transaction{ID: uint(i), Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 10}
+		}
+
+		// Act
+		result := summarizer.CalculateSummary(context.Background(), txns)
+
+		// Assert
+		assert.Equal(t, 100.0, result.TotalBalance)
+		assert.Equal(t, 100.0, result.YearlyData[SummaryYear(2023)][time.July].TotalCredit)
+		assert.Equal(t, 100.0, result.DailyBalances[0].Balance)
+	})
+}
+
+func TestDefaultSummarizer_Currencies(t *testing.T) {
+	summarizer := NewDefaultSummarizer()
+
+	t.Run("it should summarize each currency separately", func(t *testing.T) {
+		// Arrange
+		txns := []transactions.Transaction{
+			{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100_00, Currency: "USD"},
+			{ID: 2, Date: time.Date(2023, time.July, 16, 0, 0, 0, 0, time.UTC), Amount: 2000_00, Currency: "MXN"},
+			{ID: 3, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: -40_00, Currency: "USD"},
+			{ID: 4, Date: time.Date(2023, time.August, 2, 0, 0, 0, 0, time.UTC), Amount: -500_00, Currency: "MXN"},
+		}
+
+		// Act
+		result := summarizer.CalculateSummary(context.Background(), txns)
+
+		// Assert
+		assert.Equal(t, 4, result.TotalTransactionCount)
+		assert.Equal(t, 0.0, result.TotalBalance, "Amounts of different currencies should not be added up")
+		assert.Empty(t, result.YearlyData)
+		assert.Empty(t, result.Currency)
+		require.Len(t, result.Currencies, 2)
+
+		usd := result.Currencies["USD"]
+		assert.Equal(t, "USD", usd.Currency)
+		assert.Equal(t, 60.00, usd.TotalBalance)
+		assert.Equal(t, 2, usd.TotalTransactionCount)
+		assert.Equal(t, 100.00, usd.YearlyData[SummaryYear(2023)][time.July].TotalCredit)
+		assert.Equal(t, -40.00, usd.YearlyData[SummaryYear(2023)][time.July].TotalDebit)
+
+		mxn := result.Currencies["MXN"]
+		assert.Equal(t, "MXN", mxn.Currency)
+		assert.Equal(t, 1500.00, mxn.TotalBalance)
+		assert.Equal(t, 2, mxn.TotalTransactionCount)
+		assert.Equal(t, 2000.00, mxn.YearlyData[SummaryYear(2023)][time.July].TotalCredit)
+		assert.Equal(t, -500.00, mxn.YearlyData[SummaryYear(2023)][time.August].TotalDebit)
+	})
+
+	t.Run("it should not segment transactions of a single currency", func(t *testing.T) {
+		// Arrange
+		txns := []transactions.Transaction{
+			{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 100_00, Currency: "MXN"},
+			{ID: 2, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: -40_00, Currency: "MXN"},
+		}
+
+		// Act
+		result := summarizer.CalculateSummary(context.Background(), txns)
+
+		// Assert
+		assert.Equal(t, "MXN", result.Currency)
+		assert.Equal(t, 60.00, result.TotalBalance)
+		assert.Nil(t, result.Currencies)
+	})
+}
+
+func TestDefaultSummarizer_WeeklyData(t *testing.T) {
+	weekly := DefaultSummarizerConfig()
+	weekly.Granularity = GranularityMonthly | GranularityWeekly
+
+	t.Run("it should bucket transactions by ISO week across the year boundary", func(t *testing.T) {
+		// Arrange
+		txns := []transactions.Transaction{
+			// Sunday, the last day of week 52 of 2024
+			{ID: 1, Date: time.Date(2024, time.December, 29, 0, 0, 0, 0, time.UTC), Amount: 50_00},
+			// Monday, the first day of week 1 of 2025, in calendar year 2024
+			{ID: 2, Date: time.Date(2024, time.December, 30, 0, 0, 0, 0, time.UTC), Amount: -20_00},
+			{ID: 3, Date: time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC), Amount: 100_00},
+			// Monday, the first day of week 2 of 2025
+			{ID: 4, Date: time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC), Amount: -5_50},
+		}
+
+		// Act
+		result := NewDefaultSummarizerWithConfig(weekly).CalculateSummary(context.Background(), txns)
+
+		// Assert
+		require.Len(t, result.WeeklyData, 2)
+		require.Len(t, result.WeeklyData[SummaryYear(2024)], 1)
+		assert.Equal(t, MonthlySummary{
+			TransactionCount: 1, CreditCount: 1, AverageCredit: 50, TotalCredit: 50, MedianCredit: 50, MaxCredit: 50, MaxCreditID: 1,
+		}, result.WeeklyData[SummaryYear(2024)][SummaryWeek(52)])
+
+		require.Len(t, result.WeeklyData[SummaryYear(2025)], 2)
+		firstWeek := result.WeeklyData[SummaryYear(2025)][SummaryWeek(1)]
+		assert.Equal(t, 2, firstWeek.TransactionCount, "December 30, 2024 should belong to the first week of 2025")
+		assert.Equal(t, -20.00, firstWeek.TotalDebit)
+		assert.Equal(t, uint(2), firstWeek.MinDebitID)
+		assert.Equal(t, 100.00, firstWeek.TotalCredit)
+		assert.Equal(t, -5.50, result.WeeklyData[SummaryYear(2025)][SummaryWeek(2)].TotalDebit)
+
+		// Monthly data keeps using calendar years
+		assert.Equal(t, 2, result.YearlyData[SummaryYear(2024)][time.December].TransactionCount)
+		assert.Equal(t, 2, result.YearlyData[SummaryYear(2025)][time.January].TransactionCount)
+	})
+
+	t.Run("it should assign early January days to the last week of the previous year", func(t *testing.T) {
+		// Arrange
+		txns := []transactions.Transaction{
+			// Friday, January 1, 2021 belongs to week 53 of 2020
+			{ID: 1, Date: time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC), Amount: 10_00},
+		}
+
+		// Act
+		result := NewDefaultSummarizerWithConfig(weekly).CalculateSummary(context.Background(), txns)
+
+		// Assert
+		require.Contains(t, result.WeeklyData, SummaryYear(2020))
+		assert.Equal(t, 1, result.WeeklyData[SummaryYear(2020)][SummaryWeek(53)].TransactionCount)
+		assert.NotContains(t, result.WeeklyData, SummaryYear(2021))
+	})
+
+	t.Run("it should not compute weekly data unless enabled", func(t *testing.T) {
+		// Arrange
+		txns := []transactions.Transaction{
+			{ID: 1, Date: time.Date(2024, time.December, 30, 0, 0, 0, 0, time.UTC), Amount: 10_00},
+		}
+
+		// Act
+		result := NewDefaultSummarizer().CalculateSummary(context.Background(), txns)
+
+		// Assert
+		assert.Nil(t, result.WeeklyData)
+	})
+
+	t.Run("it should return empty weekly data for no transactions when enabled", func(t *testing.T) {
+		// Act
+		result := NewDefaultSummarizerWithConfig(weekly).CalculateSummary(context.Background(), nil)
+
+		// Assert
+		assert.NotNil(t, result.WeeklyData)
+		assert.Empty(t, result.WeeklyData)
+	})
+}
+
+func TestGranularity_Includes(t *testing.T) {
+	tests := []struct {
+		name        string
+		granularity Granularity
+		other       Granularity
+		expected    bool
+	}{
+		{
+			name:        "it should include a period of the set",
+			granularity: GranularityMonthly | GranularityWeekly,
+			other:       GranularityWeekly,
+			expected:    true,
+		},
+		{
+			name:        "it should not include a period outside the set",
+			granularity: GranularityMonthly,
+			other:       GranularityWeekly,
+			expected:    false,
+		},
+		{
+			name:        "it should include all the periods of a set",
+			granularity: GranularityMonthly | GranularityWeekly,
+			other:       GranularityMonthly | GranularityWeekly,
+			expected:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			includes := tt.granularity.Includes(tt.other)
+
+			// Assert
+			assert.Equal(t, tt.expected, includes)
+		})
+	}
+}
+
+func TestDefaultSummarizer_FlagOutliers(t *testing.T) {
+	// newCredits returns count credits of amount in the given month, with IDs from firstID
+	newCredits := func(firstID uint, month time.Month, count int, amount transactions.Money) []transactions.Transaction {
+		txns := make([]transactions.Transaction, 0, count)
+		for i := range count {
+			txns = append(txns, transactions.Transaction{ID: firstID + uint(i), Date: time.Date(2023, month, 1+i%28, 0, 0, 0, 0, time.UTC), Amount: amount})
+		}
+		return txns
+	}
+
+	t.Run("it should flag a clear outlier of the month's credits", func(t *testing.T) {
+		// Arrange
+		summarizer := NewDefaultSummarizer()
+		txns := newCredits(1, time.July, 20, 10_00)
+		txns = append(txns,
+			transactions.Transaction{ID: 100, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 1000_00},
+			transactions.Transaction{ID: 101, Date: time.Date(2023, time.July, 16, 0, 0, 0, 0, time.UTC), Amount: -5000_00},
+		)
+
+		// Act
+		outliers, err := summarizer.FlagOutliers(context.Background(), txns)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, map[SummaryYear]map[time.Month][]uint{2023: {time.July: {100}}}, outliers)
+	})
+
+	t.Run("it should skip months with too few credits", func(t *testing.T) {
+		// Arrange
+		summarizer := NewDefaultSummarizer()
+		txns := newCredits(1, time.August, 5, 10_00)
+		txns = append(txns, transactions.Transaction{ID: 100, Date: time.Date(2023, time.August, 15, 0, 0, 0, 0, time.UTC), Amount: 1000_00})
+
+		// Act
+		outliers, err := summarizer.FlagOutliers(context.Background(), txns)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Empty(t, outliers)
+	})
+
+	t.Run("it should flag with the configured percentile and minimum", func(t *testing.T) {
+		// Arrange
+		summarizer := NewDefaultSummarizerWithConfig(SummarizerConfig{OutlierPercentile: 50, OutlierMinCredits: 4})
+		txns := []transactions.Transaction{
+			{ID: 1, Date: time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC), Amount: 10_00},
+			{ID: 2, Date: time.Date(2023, time.July, 2, 0, 0, 0, 0, time.UTC), Amount: 40_00},
+			{ID: 3, Date: time.Date(2023, time.July, 3, 0, 0, 0, 0, time.UTC), Amount: 20_00},
+			{ID: 4, Date: time.Date(2023, time.July, 4, 0, 0, 0, 0, time.UTC), Amount: 30_00},
+			{ID: 5, Date: time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC), Amount: 10_00},
+			{ID: 6, Date: time.Date(2023, time.August, 2, 0, 0, 0, 0, time.UTC), Amount: 40_00},
+		}
+
+		// Act
+		outliers, err := summarizer.FlagOutliers(context.Background(), txns)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, map[SummaryYear]map[time.Month][]uint{2023: {time.July: {2, 4}}}, outliers)
+	})
+
+	t.Run("it should stop when the context is cancelled", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Act
+		outliers, err := NewDefaultSummarizer().FlagOutliers(ctx, newCredits(1, time.July, 20, 10_00))
+
+		// Assert
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, outliers)
+	})
+}
+
+func TestNewDefaultSummarizerWithConfig(t *testing.T) {
+	t.Run("it should fall back to the default classifier when nil", func(t *testing.T) {
+		// Act
+		summarizer := NewDefaultSummarizerWithConfig(SummarizerConfig{})
+
+		// Assert
+		assert.Equal(t, NewSignClassifier(), summarizer.classifier)
+		assert.False(t, summarizer.roundAmounts)
+		assert.Equal(t, 95.0, summarizer.outlierPercentile)
+		assert.Equal(t, 20, summarizer.outlierMinCredits)
+	})
+}
+
 func TestNewDefaultSummarizer(t *testing.T) {
 	t.Run("it should create a new DefaultSummarizer instance", func(t *testing.T) {
 		// Act
@@ -386,3 +1111,51 @@ func TestNewDefaultSummarizer(t *testing.T) {
 		assert.IsType(t, &DefaultSummarizer{}, summarizer)
 	})
 }
+
+// zeroAsCreditClassifier is a test Classifier that counts zero-amount adjustments as credits.
+type zeroAsCreditClassifier struct{}
+
+func (c *zeroAsCreditClassifier) Classify(amount float64) Kind {
+	if amount < 0 {
+		return KindDebit
+	}
+	return KindCredit
+}
+
+func TestDefaultSummarizer_CustomClassifier(t *testing.T) {
+	t.Run("it should route zero amounts into credits with a custom classifier", func(t *testing.T) {
+		// Arrange
+		summarizer := NewDefaultSummarizerWithClassifier(&zeroAsCreditClassifier{})
+		txns := []transactions.Transaction{
+			{ID: 1, Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 0},
+			{ID: 2, Date: time.Date(2023, time.July, 20, 0, 0, 0, 0, time.UTC), Amount: 100_00},
+			{ID: 3, Date: time.Date(2023, time.July, 25, 0, 0, 0, 0, time.UTC), Amount: -40_00},
+		}
+
+		// Act
+		result := summarizer.CalculateSummary(context.Background(), txns)
+
+		// Assert
+		july := result.YearlyData[SummaryYear(2023)][time.July]
+		assert.Equal(t, 3, july.TransactionCount)
+		assert.Equal(t, 50.00, july.AverageCredit, "Zero amount should count as a credit") // (0 + 100) / 2
+		assert.Equal(t, 100.00, july.TotalCredit)
+		assert.Equal(t, -40.00, july.AverageDebit)
+	})
+
+	t.Run("it should separate zero amounts into credits with a custom classifier", func(t *testing.T) {
+		// Arrange
+		summarizer := NewDefaultSummarizerWithClassifier(&zeroAsCreditClassifier{})
+		txns := []transactions.Transaction{
+			{ID: 1, Amount: 0},
+			{ID: 2, Amount: -50_00},
+		}
+
+		// Act
+		debits, credits := summarizer.separateDebitsAndCredits(txns)
+
+		// Assert
+		assert.Equal(t, []transactions.Money{-50_00}, debits, "Debits should match")
+		assert.Equal(t, []transactions.Money{0}, credits, "Credits should include zero amounts")
+	})
+}
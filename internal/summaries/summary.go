@@ -1,6 +1,11 @@
 package summaries
 
-import "time"
+import (
+	"math"
+	"slices"
+	"stori-challenge/internal/transactions"
+	"time"
+)
 
 // SummaryYear represents a year as an integer (e.g., 2023)
 type SummaryYear uint
@@ -8,14 +13,39 @@ type SummaryYear uint
 // MonthlyData represents a mapping of months to their aggregated data
 type MonthlyData map[time.Month]MonthlySummary
 
+// SortedMonths returns the months of the data in ascending order.
+func (md MonthlyData) SortedMonths() []time.Month {
+	months := make([]time.Month, 0, len(md))
+	for month := range md {
+		months = append(months, month)
+	}
+	slices.Sort(months)
+	return months
+}
+
 // YearlyData represents a mapping of years to their monthly data
 type YearlyData map[SummaryYear]MonthlyData
 
+// SummaryWeek represents an ISO 8601 week number (1 to 53)
+type SummaryWeek uint
+
+// WeeklyData represents a mapping of ISO 8601 week-numbering years to the
+// aggregated data of their weeks. A week's data has the same aggregates as a
+// month's. The ISO year of a week may differ from the calendar year of its
+// days, e.g. December 30, 2024 belongs to week 1 of 2025.
+type WeeklyData map[SummaryYear]map[SummaryWeek]MonthlySummary
+
 // MonthlySummary represents the aggregated data for transactions in a specific month.
 type MonthlySummary struct {
 	// TransactionCount is the total number of transactions in this month
 	TransactionCount int
 
+	// DebitCount is the number of debit transactions in this month
+	DebitCount int
+
+	// CreditCount is the number of credit transactions in this month
+	CreditCount int
+
 	// AverageDebit is the average amount of debit transactions in this month
 	// Returns 0 if there are no debit transactions
 	AverageDebit float64
@@ -23,6 +53,75 @@ type MonthlySummary struct {
 	// AverageCredit is the average amount of credit transactions in this month
 	// Returns 0 if there are no credit transactions
 	AverageCredit float64
+
+	// TotalDebit is the sum of debit transactions (gross outflow) in this month
+	// Returns 0 if there are no debit transactions
+	TotalDebit float64
+
+	// TotalCredit is the sum of credit transactions (gross inflow) in this month
+	// Returns 0 if there are no credit transactions
+	TotalCredit float64
+
+	// MedianDebit is the median amount of debit transactions in this month
+	// Returns 0 if there are no debit transactions
+	MedianDebit float64
+
+	// MedianCredit is the median amount of credit transactions in this month
+	// Returns 0 if there are no credit transactions
+	MedianCredit float64
+
+	// MinDebit is the amount of the largest (most negative) debit transaction in this month
+	// Returns 0 if there are no debit transactions
+	MinDebit float64
+
+	// MinDebitID is the ID of the transaction holding MinDebit
+	// Returns 0 if there are no debit transactions
+	MinDebitID uint
+
+	// MaxCredit is the amount of the largest credit transaction in this month
+	// Returns 0 if there are no credit transactions
+	MaxCredit float64
+
+	// MaxCreditID is the ID of the transaction holding MaxCredit
+	// Returns 0 if there are no credit transactions
+	MaxCreditID uint
+}
+
+// SpendRatio returns the ratio of the total debit magnitude to the total credit
+// of the month (e.g. 1.2 means 20% more was spent than received).
+// The second return value is false when there are no credits, as the ratio is undefined.
+func (ms MonthlySummary) SpendRatio() (float64, bool) {
+	if ms.TotalCredit == 0 {
+		return 0, false
+	}
+	return math.Abs(ms.TotalDebit) / ms.TotalCredit, true
+}
+
+// Net returns the net amount of the month: its credits minus its debit magnitude.
+func (ms MonthlySummary) Net() float64 {
+	// Add up in minor units to avoid float64 drift
+	return (transactions.MoneyFromFloat(ms.TotalCredit) + transactions.MoneyFromFloat(ms.TotalDebit)).Float64()
+}
+
+// YoYPoint represents the data of a month in a given year, for year-over-year comparisons.
+type YoYPoint struct {
+	// Year is the year of the point
+	Year SummaryYear
+
+	// TransactionCount is the number of transactions of the month in this year
+	TransactionCount int
+
+	// Net is the net amount of the month in this year (credits minus debit magnitude)
+	Net float64
+}
+
+// DailyBalance represents the account balance at the end of a given day.
+type DailyBalance struct {
+	// Date is the day of the balance (at midnight)
+	Date time.Time
+
+	// Balance is the accumulated balance at the end of the day
+	Balance float64
 }
 
 // Summary represents the complete summary of account transactions.
@@ -30,6 +129,71 @@ type Summary struct {
 	// TotalBalance is the sum of all transaction amounts
 	TotalBalance float64
 
+	// TotalTransactionCount is the total number of transactions, including zero-amount ones
+	TotalTransactionCount int
+
 	// YearlyData contains aggregated data grouped by year and then by month
 	YearlyData YearlyData
+
+	// WeeklyData contains aggregated data grouped by ISO year and then by ISO week.
+	// Nil unless weekly granularity is enabled (see SummarizerConfig.Granularity)
+	WeeklyData WeeklyData
+
+	// DailyBalances is the running balance at the end of each day with transactions,
+	// sorted chronologically
+	DailyBalances []DailyBalance
+
+	// Currency is the ISO 4217 code of the currency of the amounts (e.g. "USD").
+	// Empty when unspecified, or when the transactions have several currencies
+	Currency string
+
+	// Currencies holds a summary segment per currency (by ISO 4217 code) when the
+	// transactions have several currencies. The amounts of the summary itself are
+	// then left empty, as amounts of different currencies can't be added up.
+	// Nil for transactions of a single currency
+	Currencies map[string]Summary
+}
+
+// SortedCurrencies returns the currencies of the summary's Currencies segments
+// in ascending order.
+func (s Summary) SortedCurrencies() []string {
+	currencies := make([]string, 0, len(s.Currencies))
+	for currency := range s.Currencies {
+		currencies = append(currencies, currency)
+	}
+	slices.Sort(currencies)
+	return currencies
+}
+
+// SortedYears returns the years of the summary's YearlyData in ascending order.
+func (s Summary) SortedYears() []SummaryYear {
+	years := make([]SummaryYear, 0, len(s.YearlyData))
+	for year := range s.YearlyData {
+		years = append(years, year)
+	}
+	slices.Sort(years)
+	return years
+}
+
+// YearOverYear returns the transaction count and net amount of the given month
+// for each year of the summary, in ascending year order (e.g. to compare this
+// December with last December). Every year from the first to the last one of the
+// summary gets a point; years without data for the month get a zero point.
+func (s Summary) YearOverYear(month time.Month) []YoYPoint {
+	years := s.SortedYears()
+	if len(years) == 0 {
+		return []YoYPoint{}
+	}
+
+	first, last := years[0], years[len(years)-1]
+	points := make([]YoYPoint, 0, last-first+1)
+	for year := first; year <= last; year++ {
+		point := YoYPoint{Year: year}
+		if monthData, ok := s.YearlyData[year][month]; ok {
+			point.TransactionCount = monthData.TransactionCount
+			point.Net = monthData.Net()
+		}
+		points = append(points, point)
+	}
+	return points
 }
@@ -8,6 +8,10 @@ type SummaryFile struct {
 	// Path is the full path to the file in storage (e.g., "s3://bucket/key").
 	Path string
 
+	// VersionID is the version of the file in storage, when a specific one
+	// was requested (e.g., "s3://bucket/key?versionId=..."). Empty otherwise.
+	VersionID string
+
 	// AccountID is the unique identifier for the account associated with the transactions.
 	AccountID string
 
@@ -15,5 +19,7 @@ type SummaryFile struct {
 	AccountEmail string
 
 	// Content is a reader for the file's content.
-	Content io.Reader
+	// The caller must close it once done reading, to release the underlying
+	// resources (e.g. the HTTP connection of an S3 object).
+	Content io.ReadCloser
 }
@@ -0,0 +1,111 @@
+package summaries
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+// summaryJSON is the JSON representation of a Summary.
+type summaryJSON struct {
+	TotalBalance          float64                                  `json:"total_balance"`
+	TotalTransactionCount int                                      `json:"total_transaction_count"`
+	YearlyData            map[string]map[string]monthlySummaryJSON `json:"yearly_data"`
+	WeeklyData            map[string]map[string]monthlySummaryJSON `json:"weekly_data,omitempty"`
+	DailyBalances         []dailyBalanceJSON                       `json:"daily_balances"`
+	Currency              string                                   `json:"currency,omitempty"`
+	Currencies            map[string]Summary                       `json:"currencies,omitempty"`
+}
+
+// monthlySummaryJSON is the JSON representation of a MonthlySummary.
+type monthlySummaryJSON struct {
+	TransactionCount int     `json:"transaction_count"`
+	DebitCount       int     `json:"debit_count"`
+	CreditCount      int     `json:"credit_count"`
+	AverageDebit     float64 `json:"average_debit"`
+	AverageCredit    float64 `json:"average_credit"`
+	TotalDebit       float64 `json:"total_debit"`
+	TotalCredit      float64 `json:"total_credit"`
+	MedianDebit      float64 `json:"median_debit"`
+	MedianCredit     float64 `json:"median_credit"`
+	MinDebit         float64 `json:"min_debit"`
+	MinDebitID       uint    `json:"min_debit_id"`
+	MaxCredit        float64 `json:"max_credit"`
+	MaxCreditID      uint    `json:"max_credit_id"`
+}
+
+// dailyBalanceJSON is the JSON representation of a DailyBalance.
+type dailyBalanceJSON struct {
+	Date    string  `json:"date"`
+	Balance float64 `json:"balance"`
+}
+
+// MarshalJSON implements json.Marshaler. Years and month names (e.g. "2023" and
+// "July") are used as object keys, and monetary values are rounded to two decimals.
+// The currency, the currency segments and the weekly data (keyed by ISO year
+// and week number, e.g. "2025" and "1") are only included when present.
+func (s Summary) MarshalJSON() ([]byte, error) {
+	yearlyData := make(map[string]map[string]monthlySummaryJSON, len(s.YearlyData))
+	for year, monthlyData := range s.YearlyData {
+		months := make(map[string]monthlySummaryJSON, len(monthlyData))
+		for month, data := range monthlyData {
+			months[month.String()] = newMonthlySummaryJSON(data)
+		}
+		yearlyData[strconv.FormatUint(uint64(year), 10)] = months
+	}
+
+	var weeklyData map[string]map[string]monthlySummaryJSON
+	if s.WeeklyData != nil {
+		weeklyData = make(map[string]map[string]monthlySummaryJSON, len(s.WeeklyData))
+		for year, weekData := range s.WeeklyData {
+			weeks := make(map[string]monthlySummaryJSON, len(weekData))
+			for week, data := range weekData {
+				weeks[strconv.FormatUint(uint64(week), 10)] = newMonthlySummaryJSON(data)
+			}
+			weeklyData[strconv.FormatUint(uint64(year), 10)] = weeks
+		}
+	}
+
+	dailyBalances := make([]dailyBalanceJSON, 0, len(s.DailyBalances))
+	for _, daily := range s.DailyBalances {
+		dailyBalances = append(dailyBalances, dailyBalanceJSON{
+			Date:    daily.Date.Format("2006-01-02"),
+			Balance: roundMoney(daily.Balance),
+		})
+	}
+
+	return json.Marshal(summaryJSON{
+		TotalBalance:          roundMoney(s.TotalBalance),
+		TotalTransactionCount: s.TotalTransactionCount,
+		YearlyData:            yearlyData,
+		WeeklyData:            weeklyData,
+		DailyBalances:         dailyBalances,
+		Currency:              s.Currency,
+		Currencies:            s.Currencies,
+	})
+}
+
+// newMonthlySummaryJSON converts the aggregated data of a period into its JSON
+// representation, rounding monetary values to two decimals.
+func newMonthlySummaryJSON(data MonthlySummary) monthlySummaryJSON {
+	return monthlySummaryJSON{
+		TransactionCount: data.TransactionCount,
+		DebitCount:       data.DebitCount,
+		CreditCount:      data.CreditCount,
+		AverageDebit:     roundMoney(data.AverageDebit),
+		AverageCredit:    roundMoney(data.AverageCredit),
+		TotalDebit:       roundMoney(data.TotalDebit),
+		TotalCredit:      roundMoney(data.TotalCredit),
+		MedianDebit:      roundMoney(data.MedianDebit),
+		MedianCredit:     roundMoney(data.MedianCredit),
+		MinDebit:         roundMoney(data.MinDebit),
+		MinDebitID:       data.MinDebitID,
+		MaxCredit:        roundMoney(data.MaxCredit),
+		MaxCreditID:      data.MaxCreditID,
+	}
+}
+
+// roundMoney rounds a monetary value to two decimals.
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,176 @@
+package summaries
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummary_MarshalJSON(t *testing.T) {
+	// roundTripSummary mirrors the JSON representation of a Summary.
+	type roundTripSummary struct {
+		TotalBalance          float64 `json:"total_balance"`
+		TotalTransactionCount int     `json:"total_transaction_count"`
+		YearlyData            map[string]map[string]struct {
+			TransactionCount int     `json:"transaction_count"`
+			AverageDebit     float64 `json:"average_debit"`
+			AverageCredit    float64 `json:"average_credit"`
+			TotalDebit       float64 `json:"total_debit"`
+			TotalCredit      float64 `json:"total_credit"`
+			MedianDebit      float64 `json:"median_debit"`
+			MedianCredit     float64 `json:"median_credit"`
+			MinDebit         float64 `json:"min_debit"`
+			MinDebitID       uint    `json:"min_debit_id"`
+			MaxCredit        float64 `json:"max_credit"`
+			MaxCreditID      uint    `json:"max_credit_id"`
+		} `json:"yearly_data"`
+		DailyBalances []struct {
+			Date    string  `json:"date"`
+			Balance float64 `json:"balance"`
+		} `json:"daily_balances"`
+	}
+
+	t.Run("it should use years and month names as keys and round money", func(t *testing.T) {
+		// Arrange
+		summary := Summary{
+			TotalBalance:          39.74,
+			TotalTransactionCount: 4,
+			YearlyData: YearlyData{
+				SummaryYear(2023): MonthlyData{
+					time.July: MonthlySummary{
+						TransactionCount: 3,
+						AverageDebit:     -15.38,
+						AverageCredit:    60.5,
+						TotalDebit:       -30.76,
+						TotalCredit:      60.5,
+						MedianDebit:      -15.38,
+						MedianCredit:     60.5,
+						MinDebit:         -20.46,
+						MinDebitID:       3,
+						MaxCredit:        60.5,
+						MaxCreditID:      1,
+					},
+				},
+				SummaryYear(2024): MonthlyData{
+					time.January: MonthlySummary{
+						TransactionCount: 1,
+						AverageCredit:    10.0 / 3.0,
+						TotalCredit:      10.0 / 3.0,
+						MedianCredit:     10.0 / 3.0,
+						MaxCredit:        10.0 / 3.0,
+						MaxCreditID:      4,
+					},
+				},
+			},
+			DailyBalances: []DailyBalance{
+				{Date: time.Date(2023, time.July, 15, 0, 0, 0, 0, time.UTC), Balance: 60.5},
+				{Date: time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC), Balance: 39.736666},
+			},
+		}
+
+		// Act
+		data, err := json.Marshal(summary)
+
+		// Assert
+		require.NoError(t, err)
+
+		var decoded roundTripSummary
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, 39.74, decoded.TotalBalance)
+		assert.Equal(t, 4, decoded.TotalTransactionCount)
+		require.Contains(t, decoded.YearlyData, "2023")
+		require.Contains(t, decoded.YearlyData["2023"], "July")
+		july := decoded.YearlyData["2023"]["July"]
+		assert.Equal(t, 3, july.TransactionCount)
+		assert.Equal(t, -15.38, july.AverageDebit)
+		assert.Equal(t, -30.76, july.TotalDebit)
+		assert.Equal(t, -20.46, july.MinDebit)
+		assert.Equal(t, uint(3), july.MinDebitID)
+		assert.Equal(t, uint(1), july.MaxCreditID)
+		require.Contains(t, decoded.YearlyData, "2024")
+		january := decoded.YearlyData["2024"]["January"]
+		assert.Equal(t, 3.33, january.AverageCredit, "Money should be rounded to two decimals")
+		assert.Equal(t, 3.33, january.MaxCredit, "Money should be rounded to two decimals")
+		require.Len(t, decoded.DailyBalances, 2)
+		assert.Equal(t, "2023-07-15", decoded.DailyBalances[0].Date)
+		assert.Equal(t, 39.74, decoded.DailyBalances[1].Balance)
+	})
+
+	t.Run("it should produce a valid empty object for an empty summary", func(t *testing.T) {
+		// Arrange
+		summary := NewDefaultSummarizer().CalculateSummary(context.Background(), nil)
+
+		// Act
+		data, err := json.Marshal(summary)
+
+		// Assert
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"total_balance":0,"total_transaction_count":0,"yearly_data":{},"daily_balances":[]}`, string(data))
+	})
+
+	t.Run("it should produce a valid object for a zero-value summary", func(t *testing.T) {
+		// Act
+		data, err := json.Marshal(Summary{})
+
+		// Assert
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"total_balance":0,"total_transaction_count":0,"yearly_data":{},"daily_balances":[]}`, string(data))
+	})
+
+	t.Run("it should include the weekly data when present", func(t *testing.T) {
+		// Arrange
+		summary := Summary{
+			TotalTransactionCount: 1,
+			WeeklyData: WeeklyData{
+				SummaryYear(2025): {
+					SummaryWeek(1): MonthlySummary{TransactionCount: 1, AverageCredit: 10.0 / 3.0, TotalCredit: 10.0 / 3.0},
+				},
+			},
+		}
+
+		// Act
+		data, err := json.Marshal(summary)
+
+		// Assert
+		require.NoError(t, err)
+		var decoded struct {
+			WeeklyData map[string]map[string]struct {
+				TransactionCount int     `json:"transaction_count"`
+				TotalCredit      float64 `json:"total_credit"`
+			} `json:"weekly_data"`
+		}
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		require.Contains(t, decoded.WeeklyData, "2025")
+		require.Contains(t, decoded.WeeklyData["2025"], "1")
+		assert.Equal(t, 1, decoded.WeeklyData["2025"]["1"].TransactionCount)
+		assert.Equal(t, 3.33, decoded.WeeklyData["2025"]["1"].TotalCredit, "Money should be rounded to two decimals")
+	})
+
+	t.Run("it should include the currency segments", func(t *testing.T) {
+		// Arrange
+		summary := Summary{
+			TotalTransactionCount: 2,
+			Currencies: map[string]Summary{
+				"MXN": {TotalBalance: 100, TotalTransactionCount: 1, Currency: "MXN"},
+				"USD": {TotalBalance: -10.3, TotalTransactionCount: 1, Currency: "USD"},
+			},
+		}
+
+		// Act
+		data, err := json.Marshal(summary)
+
+		// Assert
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"total_balance":0,"total_transaction_count":2,"yearly_data":{},"daily_balances":[],
+			"currencies":{
+				"MXN":{"total_balance":100,"total_transaction_count":1,"yearly_data":{},"daily_balances":[],"currency":"MXN"},
+				"USD":{"total_balance":-10.3,"total_transaction_count":1,"yearly_data":{},"daily_balances":[],"currency":"USD"}
+			}
+		}`, string(data))
+	})
+}
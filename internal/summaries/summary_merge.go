@@ -0,0 +1,223 @@
+package summaries
+
+import (
+	"slices"
+	"stori-challenge/internal/transactions"
+	"time"
+)
+
+// MergeSummaries combines the summaries of several accounts into a single
+// portfolio summary: balances, counts and totals are added up, and the monthly
+// (and weekly) averages are re-derived from the combined totals and per-side
+// counts, so they are weighted by the number of transactions of each account
+// rather than averages of averages.
+//
+// Medians can't be derived from other medians: a period's median is kept when a
+// single summary has debits (or credits) in it, and left at zero otherwise.
+// Summaries of different currencies are merged per currency into the Currencies
+// segments of the result, as amounts of different currencies can't be added up.
+//
+// Amounts are added up in cents and the merged values are rounded as the
+// default summarizer does, see DefaultSummarizer.MergeSummaries.
+func MergeSummaries(summaries ...Summary) Summary {
+	return NewDefaultSummarizer().MergeSummaries(summaries...)
+}
+
+// MergeSummaries combines the summaries of several accounts into a single
+// portfolio summary, see MergeSummaries. Amounts are added up exactly, in
+// cents, and the merged values are rounded according to the summarizer's
+// configuration (see SummarizerConfig.RoundAmounts).
+func (ds *DefaultSummarizer) MergeSummaries(summaries ...Summary) Summary {
+	totalCount := 0
+	currencySummaries := make(map[string][]Summary)
+	for _, summary := range summaries {
+		if summary.TotalTransactionCount == 0 {
+			continue
+		}
+		totalCount += summary.TotalTransactionCount
+
+		if len(summary.Currencies) > 0 {
+			for currency, segment := range summary.Currencies {
+				currencySummaries[currency] = append(currencySummaries[currency], segment)
+			}
+			continue
+		}
+		currencySummaries[summary.Currency] = append(currencySummaries[summary.Currency], summary)
+	}
+
+	if len(currencySummaries) == 0 {
+		return Summary{
+			YearlyData:    make(YearlyData),
+			DailyBalances: make([]DailyBalance, 0),
+		}
+	}
+
+	if len(currencySummaries) == 1 {
+		for currency, currencyGroup := range currencySummaries {
+			return ds.mergeCurrencySummaries(currency, currencyGroup)
+		}
+	}
+
+	// Amounts of different currencies can't be added up, only counted
+	merged := Summary{
+		TotalTransactionCount: totalCount,
+		YearlyData:            make(YearlyData),
+		DailyBalances:         make([]DailyBalance, 0),
+		Currencies:            make(map[string]Summary, len(currencySummaries)),
+	}
+	for currency, currencyGroup := range currencySummaries {
+		merged.Currencies[currency] = ds.mergeCurrencySummaries(currency, currencyGroup)
+	}
+	return merged
+}
+
+// mergeCurrencySummaries merges summaries of a single currency.
+func (ds *DefaultSummarizer) mergeCurrencySummaries(currency string, summaries []Summary) Summary {
+	merged := Summary{
+		YearlyData: make(YearlyData),
+		Currency:   currency,
+	}
+
+	var totalBalance transactions.Money
+	monthParts := make(map[SummaryYear]map[time.Month][]MonthlySummary)
+	var weekParts map[SummaryYear]map[SummaryWeek][]MonthlySummary
+	for _, summary := range summaries {
+		totalBalance += transactions.MoneyFromFloat(summary.TotalBalance)
+		merged.TotalTransactionCount += summary.TotalTransactionCount
+
+		for year, monthlyData := range summary.YearlyData {
+			if monthParts[year] == nil {
+				monthParts[year] = make(map[time.Month][]MonthlySummary)
+			}
+			for month, data := range monthlyData {
+				monthParts[year][month] = append(monthParts[year][month], data)
+			}
+		}
+
+		if summary.WeeklyData == nil {
+			continue
+		}
+		if weekParts == nil {
+			weekParts = make(map[SummaryYear]map[SummaryWeek][]MonthlySummary)
+		}
+		for year, weekData := range summary.WeeklyData {
+			if weekParts[year] == nil {
+				weekParts[year] = make(map[SummaryWeek][]MonthlySummary)
+			}
+			for week, data := range weekData {
+				weekParts[year][week] = append(weekParts[year][week], data)
+			}
+		}
+	}
+
+	merged.TotalBalance = ds.round(totalBalance.Float64())
+
+	for year, months := range monthParts {
+		merged.YearlyData[year] = make(MonthlyData, len(months))
+		for month, parts := range months {
+			merged.YearlyData[year][month] = ds.mergePeriodSummaries(parts)
+		}
+	}
+
+	if weekParts != nil {
+		merged.WeeklyData = make(WeeklyData, len(weekParts))
+		for year, weeks := range weekParts {
+			merged.WeeklyData[year] = make(map[SummaryWeek]MonthlySummary, len(weeks))
+			for week, parts := range weeks {
+				merged.WeeklyData[year][week] = ds.mergePeriodSummaries(parts)
+			}
+		}
+	}
+
+	merged.DailyBalances = ds.mergeDailyBalances(summaries)
+	return merged
+}
+
+// mergePeriodSummaries merges the aggregated data of a period (a month or a
+// week) of several summaries. On ties of the extremes, the first one wins.
+func (ds *DefaultSummarizer) mergePeriodSummaries(parts []MonthlySummary) MonthlySummary {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	var merged MonthlySummary
+	var totalDebit, totalCredit transactions.Money
+	debitParts, creditParts := 0, 0
+	for _, part := range parts {
+		merged.TransactionCount += part.TransactionCount
+		merged.DebitCount += part.DebitCount
+		merged.CreditCount += part.CreditCount
+		totalDebit += transactions.MoneyFromFloat(part.TotalDebit)
+		totalCredit += transactions.MoneyFromFloat(part.TotalCredit)
+
+		if part.DebitCount > 0 {
+			if debitParts == 0 || part.MinDebit < merged.MinDebit {
+				merged.MinDebit, merged.MinDebitID = part.MinDebit, part.MinDebitID
+			}
+			merged.MedianDebit = part.MedianDebit
+			debitParts++
+		}
+		if part.CreditCount > 0 {
+			if creditParts == 0 || part.MaxCredit > merged.MaxCredit {
+				merged.MaxCredit, merged.MaxCreditID = part.MaxCredit, part.MaxCreditID
+			}
+			merged.MedianCredit = part.MedianCredit
+			creditParts++
+		}
+	}
+
+	merged.TotalDebit = ds.round(totalDebit.Float64())
+	merged.TotalCredit = ds.round(totalCredit.Float64())
+
+	// The combined total over the combined count weighs each average by its count
+	if merged.DebitCount > 0 {
+		merged.AverageDebit = ds.round(totalDebit.Float64() / float64(merged.DebitCount))
+	}
+	if merged.CreditCount > 0 {
+		merged.AverageCredit = ds.round(totalCredit.Float64() / float64(merged.CreditCount))
+	}
+
+	// The median of several sets can't be derived from their medians
+	if debitParts > 1 {
+		merged.MedianDebit = 0
+	}
+	if creditParts > 1 {
+		merged.MedianCredit = 0
+	}
+
+	return merged
+}
+
+// mergeDailyBalances merges the running balances of several summaries: the
+// balance at the end of a day is the sum of each summary's latest balance on
+// or before that day.
+func (ds *DefaultSummarizer) mergeDailyBalances(summaries []Summary) []DailyBalance {
+	seen := make(map[time.Time]bool)
+	days := make([]time.Time, 0)
+	for _, summary := range summaries {
+		for _, daily := range summary.DailyBalances {
+			if !seen[daily.Date] {
+				seen[daily.Date] = true
+				days = append(days, daily.Date)
+			}
+		}
+	}
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+
+	latest := make([]transactions.Money, len(summaries))
+	next := make([]int, len(summaries))
+	dailyBalances := make([]DailyBalance, 0, len(days))
+	for _, day := range days {
+		var balance transactions.Money
+		for i, summary := range summaries {
+			for next[i] < len(summary.DailyBalances) && !summary.DailyBalances[next[i]].Date.After(day) {
+				latest[i] = transactions.MoneyFromFloat(summary.DailyBalances[next[i]].Balance)
+				next[i]++
+			}
+			balance += latest[i]
+		}
+		dailyBalances = append(dailyBalances, DailyBalance{Date: day, Balance: ds.round(balance.Float64())})
+	}
+
+	return dailyBalances
+}
@@ -0,0 +1,218 @@
+package summaries
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/transactions"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeSummaries(t *testing.T) {
+	summarizer := NewDefaultSummarizer()
+	july := func(day int) time.Time { return time.Date(2023, time.July, day, 0, 0, 0, 0, time.UTC) }
+
+	accountA := []transactions.Transaction{
+		{ID: 1, Date: july(1), Amount: 100_00},
+		{ID: 2, Date: july(5), Amount: -50_00},
+		{ID: 3, Date: july(10), Amount: 200_00},
+		{ID: 4, Date: time.Date(2023, time.August, 2, 0, 0, 0, 0, time.UTC), Amount: -5_00},
+	}
+	accountB := []transactions.Transaction{
+		{ID: 11, Date: july(3), Amount: 600_00},
+		{ID: 12, Date: july(5), Amount: -10_00},
+		{ID: 13, Date: july(8), Amount: -20_00},
+		{ID: 14, Date: july(12), Amount: -80_00},
+	}
+
+	t.Run("it should weigh the averages by the counts of each side", func(t *testing.T) {
+		// Arrange
+		summaryA := summarizer.CalculateSummary(context.Background(), accountA)
+		summaryB := summarizer.CalculateSummary(context.Background(), accountB)
+
+		// Act
+		merged := MergeSummaries(summaryA, summaryB)
+
+		// Assert
+		assert.Equal(t, 735.00, merged.TotalBalance)
+		assert.Equal(t, 8, merged.TotalTransactionCount)
+
+		julyData := merged.YearlyData[SummaryYear(2023)][time.July]
+		assert.Equal(t, 7, julyData.TransactionCount)
+		assert.Equal(t, 4, julyData.DebitCount)
+		assert.Equal(t, 3, julyData.CreditCount)
+		// Naively, (150 + 600) / 2 = 375 and (-50 + -36.67) / 2 = -43.33
+		assert.Equal(t, 300.00, julyData.AverageCredit, "(100 + 200 + 600) / 3")
+		assert.Equal(t, -40.00, julyData.AverageDebit, "(-50 + -10 + -20 + -80) / 4")
+		assert.Equal(t, 900.00, julyData.TotalCredit)
+		assert.Equal(t, -160.00, julyData.TotalDebit)
+		assert.Equal(t, 600.00, julyData.MaxCredit)
+		assert.Equal(t, uint(11), julyData.MaxCreditID)
+		assert.Equal(t, -80.00, julyData.MinDebit)
+		assert.Equal(t, uint(14), julyData.MinDebitID)
+	})
+
+	t.Run("it should match the summary of all the transactions except for combined medians", func(t *testing.T) {
+		// Arrange
+		summaryA := summarizer.CalculateSummary(context.Background(), accountA)
+		summaryB := summarizer.CalculateSummary(context.Background(), accountB)
+		expected := summarizer.CalculateSummary(context.Background(), append(append([]transactions.Transaction{}, accountA...), accountB...))
+
+		// Both accounts have July debits and credits, so its medians can't be derived
+		julyData := expected.YearlyData[SummaryYear(2023)][time.July]
+		julyData.MedianDebit, julyData.MedianCredit = 0, 0
+		expected.YearlyData[SummaryYear(2023)][time.July] = julyData
+
+		// Act
+		merged := MergeSummaries(summaryA, summaryB)
+
+		// Assert
+		assert.Equal(t, expected, merged)
+	})
+
+	t.Run("it should keep the medians of a period with data from a single summary", func(t *testing.T) {
+		// Arrange
+		summaryA := summarizer.CalculateSummary(context.Background(), accountA)
+		summaryB := summarizer.CalculateSummary(context.Background(), accountB)
+
+		// Act
+		merged := MergeSummaries(summaryA, summaryB)
+
+		// Assert
+		assert.Equal(t, summaryA.YearlyData[SummaryYear(2023)][time.August], merged.YearlyData[SummaryYear(2023)][time.August])
+		assert.Equal(t, -5.00, merged.YearlyData[SummaryYear(2023)][time.August].MedianDebit)
+	})
+
+	t.Run("it should add up the latest balance of each summary day by day", func(t *testing.T) {
+		// Arrange
+		summaryA := Summary{TotalTransactionCount: 2, DailyBalances: []DailyBalance{
+			{Date: july(1), Balance: 100},
+			{Date: july(5), Balance: 50},
+		}}
+		summaryB := Summary{TotalTransactionCount: 2, DailyBalances: []DailyBalance{
+			{Date: july(3), Balance: 600},
+			{Date: july(5), Balance: 590},
+		}}
+
+		// Act
+		merged := MergeSummaries(summaryA, summaryB)
+
+		// Assert
+		assert.Equal(t, []DailyBalance{
+			{Date: july(1), Balance: 100},
+			{Date: july(3), Balance: 700},
+			{Date: july(5), Balance: 640},
+		}, merged.DailyBalances)
+	})
+
+	t.Run("it should merge each currency separately", func(t *testing.T) {
+		// Arrange
+		usd := summarizer.CalculateSummary(context.Background(), []transactions.Transaction{
+			{ID: 1, Date: july(1), Amount: 100_00, Currency: "USD"},
+		})
+		mixed := summarizer.CalculateSummary(context.Background(), []transactions.Transaction{
+			{ID: 2, Date: july(2), Amount: 50_00, Currency: "USD"},
+			{ID: 3, Date: july(3), Amount: 2000_00, Currency: "MXN"},
+		})
+
+		// Act
+		merged := MergeSummaries(usd, mixed)
+
+		// Assert
+		assert.Equal(t, 3, merged.TotalTransactionCount)
+		assert.Equal(t, 0.0, merged.TotalBalance, "Amounts of different currencies should not be added up")
+		assert.Empty(t, merged.YearlyData)
+		require.Len(t, merged.Currencies, 2)
+		assert.Equal(t, 150.00, merged.Currencies["USD"].TotalBalance)
+		assert.Equal(t, 75.00, merged.Currencies["USD"].YearlyData[SummaryYear(2023)][time.July].AverageCredit)
+		assert.Equal(t, "USD", merged.Currencies["USD"].Currency)
+		assert.Equal(t, 2000.00, merged.Currencies["MXN"].TotalBalance)
+	})
+
+	t.Run("it should merge weekly data when present", func(t *testing.T) {
+		// Arrange
+		config := DefaultSummarizerConfig()
+		config.Granularity = GranularityMonthly | GranularityWeekly
+		weekly := NewDefaultSummarizerWithConfig(config)
+		summaryA := weekly.CalculateSummary(context.Background(), accountA)
+		summaryB := weekly.CalculateSummary(context.Background(), accountB)
+
+		// Act
+		merged := MergeSummaries(summaryA, summaryB)
+
+		// Assert
+		// July 10 and July 12, 2023 belong to ISO week 28
+		week := merged.WeeklyData[SummaryYear(2023)][SummaryWeek(28)]
+		assert.Equal(t, 2, week.TransactionCount)
+		assert.Equal(t, 200.00, week.AverageCredit)
+		assert.Equal(t, -80.00, week.AverageDebit)
+	})
+
+	t.Run("it should return a single summary unchanged", func(t *testing.T) {
+		// Arrange
+		summary := summarizer.CalculateSummary(context.Background(), accountA)
+
+		// Act
+		merged := MergeSummaries(summary)
+
+		// Assert
+		assert.Equal(t, summary, merged)
+	})
+
+	t.Run("it should return an empty summary for no summaries with transactions", func(t *testing.T) {
+		// Act
+		merged := MergeSummaries(Summary{}, summarizer.CalculateSummary(context.Background(), nil))
+
+		// Assert
+		assert.Equal(t, Summary{YearlyData: YearlyData{}, DailyBalances: []DailyBalance{}}, merged)
+	})
+}
+
+func TestDefaultSummarizer_MergeSummaries(t *testing.T) {
+	july := func(day int) time.Time { return time.Date(2023, time.July, day, 0, 0, 0, 0, time.UTC) }
+	accountA := []transactions.Transaction{
+		{ID: 1, Date: july(1), Amount: 10},
+	}
+	accountB := []transactions.Transaction{
+		{ID: 11, Date: july(2), Amount: 20},
+		{ID: 12, Date: july(3), Amount: 1_01},
+	}
+
+	t.Run("it should add up the amounts exactly and round the averages", func(t *testing.T) {
+		// Arrange
+		summarizer := NewDefaultSummarizer()
+		summaryA := summarizer.CalculateSummary(context.Background(), accountA)
+		summaryB := summarizer.CalculateSummary(context.Background(), accountB)
+
+		// Act
+		merged := summarizer.MergeSummaries(summaryA, summaryB)
+
+		// Assert
+		julyData := merged.YearlyData[SummaryYear(2023)][time.July]
+		assert.Equal(t, 1.31, merged.TotalBalance)
+		assert.Equal(t, 1.31, julyData.TotalCredit, "0.10 + 0.20 + 1.01, without float drift")
+		assert.Equal(t, 0.44, julyData.AverageCredit, "1.31 / 3, rounded half-up")
+		require.Len(t, merged.DailyBalances, 3)
+		assert.Equal(t, 0.30, merged.DailyBalances[1].Balance)
+	})
+
+	t.Run("it should not round the averages when rounding is disabled", func(t *testing.T) {
+		// Arrange
+		config := DefaultSummarizerConfig()
+		config.RoundAmounts = false
+		summarizer := NewDefaultSummarizerWithConfig(config)
+		summaryA := summarizer.CalculateSummary(context.Background(), accountA)
+		summaryB := summarizer.CalculateSummary(context.Background(), accountB)
+
+		// Act
+		merged := summarizer.MergeSummaries(summaryA, summaryB)
+
+		// Assert
+		julyData := merged.YearlyData[SummaryYear(2023)][time.July]
+		assert.Equal(t, 1.31, julyData.TotalCredit)
+		assert.InDelta(t, 1.31/3, julyData.AverageCredit, 1e-9)
+	})
+}
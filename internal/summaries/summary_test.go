@@ -0,0 +1,243 @@
+package summaries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonthlySummary_SpendRatio(t *testing.T) {
+	tests := []struct {
+		name          string
+		summary       MonthlySummary
+		expectedRatio float64
+		expectedOK    bool
+	}{
+		{
+			name:          "it should calculate the ratio for a month with debits and credits",
+			summary:       MonthlySummary{TotalDebit: -150.00, TotalCredit: 100.00},
+			expectedRatio: 1.5,
+			expectedOK:    true,
+		},
+		{
+			name:          "it should return zero for a credit-only month",
+			summary:       MonthlySummary{TotalDebit: 0, TotalCredit: 100.00},
+			expectedRatio: 0,
+			expectedOK:    true,
+		},
+		{
+			name:          "it should not divide by zero for a debit-only month",
+			summary:       MonthlySummary{TotalDebit: -150.00, TotalCredit: 0},
+			expectedRatio: 0,
+			expectedOK:    false,
+		},
+		{
+			name:          "it should not divide by zero for an empty month",
+			summary:       MonthlySummary{},
+			expectedRatio: 0,
+			expectedOK:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			ratio, ok := tt.summary.SpendRatio()
+
+			// Assert
+			assert.Equal(t, tt.expectedRatio, ratio)
+			assert.Equal(t, tt.expectedOK, ok)
+		})
+	}
+}
+
+func TestMonthlyData_SortedMonths(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     MonthlyData
+		expected []time.Month
+	}{
+		{
+			name:     "it should return an empty slice for no months",
+			data:     MonthlyData{},
+			expected: []time.Month{},
+		},
+		{
+			name: "it should return months in ascending order",
+			data: MonthlyData{
+				time.December: MonthlySummary{},
+				time.January:  MonthlySummary{},
+				time.July:     MonthlySummary{},
+				time.March:    MonthlySummary{},
+			},
+			expected: []time.Month{time.January, time.March, time.July, time.December},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := tt.data.SortedMonths()
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+			for i := 0; i < 10; i++ {
+				assert.Equal(t, result, tt.data.SortedMonths(), "Order should be stable across calls")
+			}
+		})
+	}
+}
+
+func TestSummary_SortedYears(t *testing.T) {
+	tests := []struct {
+		name     string
+		summary  Summary
+		expected []SummaryYear
+	}{
+		{
+			name:     "it should return an empty slice for no years",
+			summary:  Summary{YearlyData: YearlyData{}},
+			expected: []SummaryYear{},
+		},
+		{
+			name: "it should return years in ascending order",
+			summary: Summary{YearlyData: YearlyData{
+				SummaryYear(2024): MonthlyData{},
+				SummaryYear(2021): MonthlyData{},
+				SummaryYear(2023): MonthlyData{},
+			}},
+			expected: []SummaryYear{2021, 2023, 2024},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := tt.summary.SortedYears()
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+			for i := 0; i < 10; i++ {
+				assert.Equal(t, result, tt.summary.SortedYears(), "Order should be stable across calls")
+			}
+		})
+	}
+}
+
+func TestSummary_SortedCurrencies(t *testing.T) {
+	t.Run("it should return the currencies in ascending order", func(t *testing.T) {
+		// Arrange
+		summary := Summary{Currencies: map[string]Summary{"USD": {}, "EUR": {}, "MXN": {}}}
+
+		// Act
+		result := summary.SortedCurrencies()
+
+		// Assert
+		assert.Equal(t, []string{"EUR", "MXN", "USD"}, result)
+	})
+}
+
+func TestMonthlySummary_Net(t *testing.T) {
+	tests := []struct {
+		name     string
+		summary  MonthlySummary
+		expected float64
+	}{
+		{
+			name:     "it should subtract the debits from the credits",
+			summary:  MonthlySummary{TotalDebit: -30.76, TotalCredit: 70.5},
+			expected: 39.74,
+		},
+		{
+			name:     "it should be negative when more was spent than received",
+			summary:  MonthlySummary{TotalDebit: -150.1, TotalCredit: 100.2},
+			expected: -49.9,
+		},
+		{
+			name:     "it should return zero for an empty month",
+			summary:  MonthlySummary{},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			net := tt.summary.Net()
+
+			// Assert
+			assert.Equal(t, tt.expected, net)
+		})
+	}
+}
+
+func TestSummary_YearOverYear(t *testing.T) {
+	tests := []struct {
+		name     string
+		summary  Summary
+		month    time.Month
+		expected []YoYPoint
+	}{
+		{
+			name: "it should return a point per year in ascending order",
+			summary: Summary{YearlyData: YearlyData{
+				2024: {time.December: {TransactionCount: 4, TotalDebit: -50.25, TotalCredit: 200}},
+				2022: {time.December: {TransactionCount: 2, TotalDebit: -10, TotalCredit: 60.5}},
+				2023: {
+					time.November: {TransactionCount: 7, TotalDebit: -1, TotalCredit: 1},
+					time.December: {TransactionCount: 3, TotalDebit: -120, TotalCredit: 20},
+				},
+			}},
+			month: time.December,
+			expected: []YoYPoint{
+				{Year: 2022, TransactionCount: 2, Net: 50.5},
+				{Year: 2023, TransactionCount: 3, Net: -100},
+				{Year: 2024, TransactionCount: 4, Net: 149.75},
+			},
+		},
+		{
+			name: "it should return a zero point for a year without data for the month",
+			summary: Summary{YearlyData: YearlyData{
+				2022: {time.December: {TransactionCount: 2, TotalDebit: -10, TotalCredit: 60.5}},
+				2023: {time.November: {TransactionCount: 7, TotalDebit: -1, TotalCredit: 1}},
+				2024: {time.December: {TransactionCount: 4, TotalDebit: -50.25, TotalCredit: 200}},
+			}},
+			month: time.December,
+			expected: []YoYPoint{
+				{Year: 2022, TransactionCount: 2, Net: 50.5},
+				{Year: 2023},
+				{Year: 2024, TransactionCount: 4, Net: 149.75},
+			},
+		},
+		{
+			name: "it should return a zero point for a year without transactions",
+			summary: Summary{YearlyData: YearlyData{
+				2022: {time.December: {TransactionCount: 2, TotalDebit: -10, TotalCredit: 60.5}},
+				2024: {time.December: {TransactionCount: 4, TotalDebit: -50.25, TotalCredit: 200}},
+			}},
+			month: time.December,
+			expected: []YoYPoint{
+				{Year: 2022, TransactionCount: 2, Net: 50.5},
+				{Year: 2023},
+				{Year: 2024, TransactionCount: 4, Net: 149.75},
+			},
+		},
+		{
+			name:     "it should return no points for an empty summary",
+			summary:  Summary{YearlyData: YearlyData{}},
+			month:    time.December,
+			expected: []YoYPoint{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			points := tt.summary.YearOverYear(tt.month)
+
+			// Assert
+			assert.Equal(t, tt.expected, points)
+		})
+	}
+}
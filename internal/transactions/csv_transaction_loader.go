@@ -2,24 +2,62 @@ package transactions
 
 import (
 	"bufio"
+	"compress/gzip"
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 )
 
+const (
+	// defaultDateLayout is the US M/D/YYYY layout used when none is configured
+	defaultDateLayout = "1/2/2006"
+
+	// yearToken is the Go time layout token for a four-digit year
+	yearToken = "2006"
+
+	// shortYearToken is the Go time layout token for a two-digit year
+	shortYearToken = "06"
+
+	// defaultDateSeparator separates the year added to layouts without any separator
+	defaultDateSeparator = "/"
+
+	// isoDateLayout is the ISO 8601 calendar date layout used as a parsing fallback
+	isoDateLayout = "2006-01-02"
+
+	// utf8BOM is the UTF-8 byte order mark that some editors write at the start of files
+	utf8BOM = "\xEF\xBB\xBF"
+
+	// defaultMaxValidationErrors is the number of errors listed in a ValidationReport when none is configured
+	defaultMaxValidationErrors = 10
+)
+
 // CSVTransactionLoader implements TransactionLoader for CSV data sources.
 // It provides high-performance streaming CSV processing with minimal memory allocation.
 type CSVTransactionLoader struct {
 	// dateParser is cached to avoid repeated time format parsing
 	dateLayout string
 
+	// dateSeparator is the separator next to the year in dateLayout (e.g. "/"
+	// or "."), and dateSeparators the number of them in a full date. It's empty
+	// when dates can't be completed with the current year (e.g. "1/2006/2")
+	dateSeparator  string
+	dateSeparators int
+
 	// currentYear is cached to avoid repeated time.Now() calls
 	currentYear int
 
+	// yearPrefix and yearSuffix complete dates without year, as "2023/" before
+	// the date for year-first layouts or "/2023" after it otherwise (or "/23"
+	// for two-digit year layouts). They are precomputed to avoid formatting the
+	// year for every record
+	yearPrefix, yearSuffix string
+
 	// csvConfig holds CSV parsing configuration
 	csvConfig CSVTransactionLoaderConfig
 }
@@ -32,16 +70,83 @@ type CSVTransactionLoaderConfig struct {
 	// ExpectedRecords provides hint for slice pre-allocation
 	ExpectedRecords int
 
-	// FieldsPerRecord expected number of fields per record for validation
+	// FieldsPerRecord expected number of fields per record for validation.
+	// 0 expects the number of fields of the first record (default: 0, i.e. the
+	// ID, Date and Transaction columns of the header, plus an optional Currency one)
 	FieldsPerRecord int
+
+	// HasHeader indicates whether the first line is a header row to be skipped (default: true)
+	HasHeader bool
+
+	// RejectDuplicateIDs fails the load when the same transaction ID appears more than once
+	RejectDuplicateIDs bool
+
+	// AutoDecompress transparently decompresses gzip content detected by its magic number (default: true)
+	AutoDecompress bool
+
+	// CollectErrors skips invalid records and reports them all as RecordErrors instead of failing on the first one
+	CollectErrors bool
+
+	// DateLayout is the Go time layout of the Date column (default: "1/2/2006").
+	// Dates without year are completed with the current year when the year
+	// leads or ends the layout; layouts without a year ("2006" or "06") are
+	// extended with a four-digit one so full dates are accepted as well.
+	DateLayout string
+
+	// MinAmount is the lowest accepted amount in major units, inclusive (0 disables the lower bound)
+	MinAmount float64
+
+	// MaxAmount is the highest accepted amount in major units, inclusive (0 disables the upper bound)
+	MaxAmount float64
+
+	// DefaultCurrency is the currency of the transactions of files without a
+	// Currency column, or with an empty one (default: "", i.e. unspecified)
+	DefaultCurrency string
+
+	// Delimiter is the field delimiter of the file, e.g. ';' or '\t' (default: ',')
+	Delimiter rune
+
+	// StripThousandsSeparators removes the thousands separators of amounts before
+	// parsing them, e.g. "1,250.00" (or "1.250,00" with DecimalComma). A comma
+	// delimiter requires such amounts to be quoted, so prefer another delimiter
+	StripThousandsSeparators bool
+
+	// DecimalComma parses amounts with a decimal comma and, when stripped, a
+	// thousands dot (e.g. "-10,30" or "1.250,00") instead of a decimal dot
+	DecimalComma bool
+
+	// StripCurrencySymbols are symbols removed from amounts before parsing them,
+	// e.g. '$' to accept "$60.50" and "-$10.30". The sign is preserved
+	// (default: none, i.e. strict parsing)
+	StripCurrencySymbols []rune
+
+	// LazyQuotes tolerates imperfectly quoted fields, e.g. a quote in an unquoted
+	// field or a non-doubled quote in a quoted field (see csv.Reader.LazyQuotes).
+	// Properly quoted fields, including ones with embedded commas, are always accepted
+	LazyQuotes bool
+
+	// NormalizeLineEndings translates bare carriage returns (classic Mac OS line
+	// endings) into line feeds, which the CSV reader would otherwise read as a
+	// single record. Windows (CRLF) and Unix (LF) line endings are always accepted
+	// (default: false)
+	NormalizeLineEndings bool
+
+	// MaxValidationErrors is the maximum number of invalid rows listed in the
+	// Errors of a ValidationReport; the rest are only counted (default: 10)
+	MaxValidationErrors int
 }
 
 // DefaultCSVConfig returns optimized default configuration.
 func DefaultCSVConfig() CSVTransactionLoaderConfig {
 	return CSVTransactionLoaderConfig{
-		BufferSize:      64 * 1024, // 64KB buffer for optimal I/O
-		ExpectedRecords: 100,       // Reasonable default for pre-allocation
-		FieldsPerRecord: 3,         // ID, Date, Transaction
+		BufferSize:          64 * 1024, // 64KB buffer for optimal I/O
+		ExpectedRecords:     100,       // Reasonable default for pre-allocation
+		FieldsPerRecord:     0,         // ID, Date, Transaction and optionally Currency, as the header
+		HasHeader:           true,      // Files are expected to start with a header row
+		AutoDecompress:      true,      // Accept both plain and gzip-compressed files
+		DateLayout:          defaultDateLayout,
+		Delimiter:           ',',
+		MaxValidationErrors: defaultMaxValidationErrors,
 	}
 }
 
@@ -53,40 +158,111 @@ func NewCSVTransactionLoader() *CSVTransactionLoader {
 
 // NewCSVTransactionLoaderWithConfig creates a loader with custom configuration.
 // Allows fine-tuning for specific use cases and performance requirements.
+// Boolean options that default to true (HasHeader, AutoDecompress) are false in
+// a zero-value config, so custom configurations should start from DefaultCSVConfig.
 func NewCSVTransactionLoaderWithConfig(config CSVTransactionLoaderConfig) *CSVTransactionLoader {
-	return &CSVTransactionLoader{
-		dateLayout:  "1/2/2006",
+	dateLayout := config.DateLayout
+	if dateLayout == "" {
+		dateLayout = defaultDateLayout
+	}
+
+	// Only add a year token when the layout doesn't have a year component already
+	year := layoutYearToken(dateLayout)
+	if year == "" {
+		year = yearToken
+		dateLayout += layoutSeparator(dateLayout) + yearToken
+	}
+
+	loader := &CSVTransactionLoader{
+		dateLayout:  dateLayout,
 		currentYear: time.Now().Year(),
 		csvConfig:   config,
 	}
+
+	currentYear := strconv.Itoa(loader.currentYear)
+	if year == shortYearToken {
+		currentYear = fmt.Sprintf("%02d", loader.currentYear%100)
+	}
+
+	// Dates can only be completed when the year leads or ends the layout
+	if rest, ok := strings.CutPrefix(dateLayout, year); ok && rest != "" && !isLayoutTokenByte(rest[0]) {
+		loader.dateSeparator = rest[:1]
+		loader.yearPrefix = currentYear + loader.dateSeparator
+	} else if rest, ok := strings.CutSuffix(dateLayout, year); ok && rest != "" && !isLayoutTokenByte(rest[len(rest)-1]) {
+		loader.dateSeparator = rest[len(rest)-1:]
+		loader.yearSuffix = loader.dateSeparator + currentYear
+	}
+	if loader.dateSeparator != "" {
+		loader.dateSeparators = strings.Count(dateLayout, loader.dateSeparator)
+	}
+
+	return loader
+}
+
+// layoutYearToken returns the year token of a date layout, "2006" or "06",
+// or an empty string when the layout has no year component.
+func layoutYearToken(layout string) string {
+	switch {
+	case strings.Contains(layout, yearToken):
+		return yearToken
+	case strings.Contains(layout, shortYearToken):
+		return shortYearToken
+	default:
+		return ""
+	}
+}
+
+// layoutSeparator returns the first separator of a date layout (e.g. "." for
+// "02.01"), or the default separator when the layout has none.
+func layoutSeparator(layout string) string {
+	for i := 0; i < len(layout); i++ {
+		if !isLayoutTokenByte(layout[i]) {
+			return layout[i : i+1]
+		}
+	}
+	return defaultDateSeparator
+}
+
+// isLayoutTokenByte reports whether a date layout byte is part of a token
+// (e.g. "01", "_2" or "Jan") rather than a separator.
+func isLayoutTokenByte(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
 }
 
 // LoadTransactions implements streaming CSV processing with optimal memory usage.
 // Uses buffered reading and context-aware processing for better performance.
+// When CollectErrors is enabled, invalid records are skipped and returned as
+// RecordErrors together with the valid transactions.
 func (loader *CSVTransactionLoader) LoadTransactions(ctx context.Context, reader io.Reader) ([]Transaction, error) {
 	// Early context validation
 	if err := ctx.Err(); err != nil {
 		return nil, fmt.Errorf("context error before processing: %w", err)
 	}
 
-	// Use buffered reader for better I/O performance
-	bufferedReader := bufio.NewReaderSize(reader, loader.csvConfig.BufferSize)
-	csvReader := csv.NewReader(bufferedReader)
-
-	// Configure CSV reader for strict validation
-	csvReader.FieldsPerRecord = loader.csvConfig.FieldsPerRecord
-	csvReader.TrimLeadingSpace = true
+	csvReader, closeReader, err := loader.newCSVReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	defer closeReader()
 
-	// Skip header row efficiently
-	if _, err := csvReader.Read(); err != nil {
-		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	lineNumber, err := loader.skipHeader(csvReader)
+	if err != nil {
+		return nil, err
 	}
 
 	// Pre-allocate slice with capacity hint for better memory efficiency
 	transactions := make([]Transaction, 0, loader.csvConfig.ExpectedRecords)
 
+	// Track seen IDs (and the line they were first seen at) only when duplicates must be rejected
+	var seenIDs map[uint]int
+	if loader.csvConfig.RejectDuplicateIDs {
+		seenIDs = make(map[uint]int, loader.csvConfig.ExpectedRecords)
+	}
+
+	// Invalid records are only accumulated when errors are collected
+	var recordErrors RecordErrors
+
 	// Stream processing with minimal allocations
-	lineNumber := 2 // Start from 2 (after header)
 	for {
 		select {
 		case <-ctx.Done():
@@ -94,32 +270,252 @@ func (loader *CSVTransactionLoader) LoadTransactions(ctx context.Context, reader
 		default:
 		}
 
-		record, err := csvReader.Read()
+		transaction, err := loader.readTransaction(csvReader, lineNumber, seenIDs)
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("CSV parsing error at line %d: %w", lineNumber, err)
-		}
-
-		transaction, err := loader.parseRecord(record, lineNumber)
-		if err != nil {
-			return nil, fmt.Errorf("record validation error at line %d: %w", lineNumber, err)
+			// Only invalid records can be skipped; a failing reader would fail every following read too
+			if !loader.csvConfig.CollectErrors || !isInvalidRecord(err) {
+				return nil, err
+			}
+			recordErrors = append(recordErrors, RecordError{Line: lineNumber, Err: err})
+			lineNumber++
+			continue
 		}
 
 		transactions = append(transactions, transaction)
 		lineNumber++
 	}
 
+	// Report every invalid record along with the valid transactions
+	if len(recordErrors) > 0 {
+		return transactions, recordErrors
+	}
+
 	return transactions, nil
 }
 
+// ValidateTransactions streams the file like LoadTransactions, validating every
+// row the same way, but only reports how many rows are valid and invalid instead
+// of building the transactions. The first MaxValidationErrors invalid rows are
+// listed in the report. Invalid rows are never an error: the error is only set
+// when the file can't be read (e.g. a missing header, a truncated gzip stream or
+// a cancelled context).
+func (loader *CSVTransactionLoader) ValidateTransactions(ctx context.Context, reader io.Reader) (ValidationReport, error) {
+	if err := ctx.Err(); err != nil {
+		return ValidationReport{}, fmt.Errorf("context error before processing: %w", err)
+	}
+
+	maxErrors := loader.csvConfig.MaxValidationErrors
+	if maxErrors <= 0 {
+		maxErrors = defaultMaxValidationErrors
+	}
+
+	csvReader, closeReader, err := loader.newCSVReader(reader)
+	if err != nil {
+		return ValidationReport{}, err
+	}
+	defer closeReader()
+
+	lineNumber, err := loader.skipHeader(csvReader)
+	if err != nil {
+		return ValidationReport{}, err
+	}
+
+	var seenIDs map[uint]int
+	if loader.csvConfig.RejectDuplicateIDs {
+		seenIDs = make(map[uint]int, loader.csvConfig.ExpectedRecords)
+	}
+
+	var report ValidationReport
+	for ; ; lineNumber++ {
+		select {
+		case <-ctx.Done():
+			return ValidationReport{}, fmt.Errorf("context cancelled at line %d: %w", lineNumber, ctx.Err())
+		default:
+		}
+
+		_, err := loader.readTransaction(csvReader, lineNumber, seenIDs)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && !isInvalidRecord(err) {
+			return ValidationReport{}, err
+		}
+
+		report.TotalRows++
+		if err != nil {
+			report.InvalidRows++
+			if len(report.Errors) < maxErrors {
+				report.Errors = append(report.Errors, RecordError{Line: lineNumber, Err: err})
+			}
+			continue
+		}
+		report.ValidRows++
+	}
+
+	return report, nil
+}
+
+// newCSVReader creates the CSV reader of the content, buffering it and
+// transparently decompressing gzip content when enabled. The returned function
+// releases the decompressor and must be called once done reading.
+func (loader *CSVTransactionLoader) newCSVReader(reader io.Reader) (*csv.Reader, func(), error) {
+	// Use buffered reader for better I/O performance
+	bufferedReader := bufio.NewReaderSize(reader, loader.csvConfig.BufferSize)
+
+	// Transparently decompress gzip content when enabled
+	source := bufferedReader
+	closeReader := func() {}
+	if loader.csvConfig.AutoDecompress && loader.isGzipCompressed(bufferedReader) {
+		gzipReader, err := gzip.NewReader(bufferedReader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open gzip stream: %w", err)
+		}
+		source = bufio.NewReaderSize(gzipReader, loader.csvConfig.BufferSize)
+		closeReader = func() { gzipReader.Close() }
+	}
+
+	// Strip the UTF-8 BOM that spreadsheet exports (e.g. Excel) start with
+	loader.skipBOM(source)
+
+	var content io.Reader = source
+	if loader.csvConfig.NormalizeLineEndings {
+		content = &lineEndingNormalizer{reader: source}
+	}
+
+	csvReader := csv.NewReader(content)
+	if loader.csvConfig.Delimiter != 0 {
+		csvReader.Comma = loader.csvConfig.Delimiter
+	}
+
+	// Configure CSV reader for strict validation
+	csvReader.FieldsPerRecord = loader.csvConfig.FieldsPerRecord
+	csvReader.TrimLeadingSpace = true
+	csvReader.LazyQuotes = loader.csvConfig.LazyQuotes
+
+	// Records are parsed right away, so the slice of fields can be reused across reads
+	csvReader.ReuseRecord = true
+
+	return csvReader, closeReader, nil
+}
+
+// skipHeader skips the header row (only when the file is expected to have one)
+// and returns the line number of the first data row.
+func (loader *CSVTransactionLoader) skipHeader(csvReader *csv.Reader) (int, error) {
+	if !loader.csvConfig.HasHeader {
+		return 1, nil
+	}
+	if _, err := csvReader.Read(); err != nil {
+		return 0, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	return 2, nil // Start from 2 (after header)
+}
+
+// readTransaction reads the next CSV record and converts it into a Transaction.
+// Returns io.EOF when there are no more records to read. Errors confined to the
+// record (see isInvalidRecord) are distinguished from failures of the underlying
+// reader, such as a truncated gzip stream.
+func (loader *CSVTransactionLoader) readTransaction(csvReader *csv.Reader, lineNumber int, seenIDs map[uint]int) (Transaction, error) {
+	record, err := csvReader.Read()
+	if err == io.EOF {
+		return Transaction{}, err
+	}
+	if err != nil {
+		// Malformed records, including ones with the wrong number of fields
+		// (csv.ErrFieldCount), are reported as a csv.ParseError
+		var parseErr *csv.ParseError
+		if !errors.As(err, &parseErr) {
+			return Transaction{}, fmt.Errorf("failed to read CSV content at line %d: %w", lineNumber, err)
+		}
+		return Transaction{}, invalidRecordError{fmt.Errorf("CSV parsing error at line %d: %w", lineNumber, err)}
+	}
+
+	transaction, err := loader.parseRecord(record, lineNumber)
+	if err != nil {
+		return Transaction{}, invalidRecordError{fmt.Errorf("record validation error at line %d: %w", lineNumber, err)}
+	}
+
+	// Reject IDs that were already seen (only when duplicate detection is enabled)
+	if seenIDs != nil {
+		if firstLine, exists := seenIDs[transaction.ID]; exists {
+			return Transaction{}, invalidRecordError{fmt.Errorf("duplicate transaction ID %d at line %d (first seen at line %d)", transaction.ID, lineNumber, firstLine)}
+		}
+		seenIDs[transaction.ID] = lineNumber
+	}
+
+	return transaction, nil
+}
+
+// invalidRecordError marks an error of readTransaction confined to a single
+// record (malformed CSV, an invalid field or a duplicate ID), after which the
+// following records can still be read.
+type invalidRecordError struct {
+	err error
+}
+
+// Error returns the reason why the record is invalid.
+func (e invalidRecordError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying reason, so it can be inspected with errors.Is/As.
+func (e invalidRecordError) Unwrap() error {
+	return e.err
+}
+
+// isInvalidRecord reports whether an error of readTransaction is confined to
+// its record, so reading can go on. Any other error comes from the underlying
+// reader (e.g. a truncated gzip stream or a reset connection), which would
+// return it again on every following read.
+func isInvalidRecord(err error) bool {
+	return errors.As(err, new(invalidRecordError))
+}
+
+// isGzipCompressed peeks the first two bytes looking for the gzip magic number (0x1f 0x8b).
+// Peeking does not consume the bytes, so the reader can still be parsed as plain text.
+func (loader *CSVTransactionLoader) isGzipCompressed(reader *bufio.Reader) bool {
+	magic, err := reader.Peek(2)
+	if err != nil {
+		return false
+	}
+	return magic[0] == 0x1f && magic[1] == 0x8b
+}
+
+// lineEndingNormalizer translates every carriage return into a line feed.
+// CRLF line endings then become empty lines, which the CSV reader skips.
+type lineEndingNormalizer struct {
+	reader io.Reader
+}
+
+// Read implements io.Reader, translating the carriage returns of the read bytes in place.
+func (n *lineEndingNormalizer) Read(p []byte) (int, error) {
+	count, err := n.reader.Read(p)
+	for i, b := range p[:count] {
+		if b == '\r' {
+			p[i] = '\n'
+		}
+	}
+	return count, err
+}
+
+// skipBOM discards a leading UTF-8 byte order mark (0xEF 0xBB 0xBF), which would
+// otherwise become part of the first header cell (or the first ID of headerless files).
+// Content without a BOM is left untouched.
+func (loader *CSVTransactionLoader) skipBOM(reader *bufio.Reader) {
+	bom, err := reader.Peek(len(utf8BOM))
+	if err == nil && string(bom) == utf8BOM {
+		reader.Discard(len(utf8BOM))
+	}
+}
+
 // parseRecord converts a raw CSV record to Transaction with zero-allocation string processing.
 // Optimized for performance with minimal string operations and direct parsing.
 func (loader *CSVTransactionLoader) parseRecord(record []string, lineNumber int) (Transaction, error) {
 	// Validate record length (already done by csv.Reader.FieldsPerRecord, but explicit for clarity)
-	if len(record) != 3 {
-		return Transaction{}, fmt.Errorf("expected 3 fields, got %d", len(record))
+	if len(record) != 3 && len(record) != 4 {
+		return Transaction{}, fmt.Errorf("expected 3 or 4 fields, got %d", len(record))
 	}
 
 	var transaction Transaction
@@ -135,11 +531,24 @@ func (loader *CSVTransactionLoader) parseRecord(record []string, lineNumber int)
 		return Transaction{}, fmt.Errorf("invalid date '%s': %w", record[1], err)
 	}
 
-	// Parse amount with optimized float parsing
+	// Parse amount exactly, in minor units
 	if transaction.Amount, err = loader.parseAmountOptimized(record[2]); err != nil {
 		return Transaction{}, fmt.Errorf("invalid amount '%s': %w", record[2], err)
 	}
 
+	// Reject amounts outside the configured sanity bounds
+	if err = loader.checkAmountBounds(transaction.Amount); err != nil {
+		return Transaction{}, fmt.Errorf("invalid amount '%s': %w", record[2], err)
+	}
+
+	// Parse the optional currency, falling back to the configured one
+	transaction.Currency = loader.csvConfig.DefaultCurrency
+	if len(record) == 4 && record[3] != "" {
+		if transaction.Currency, err = loader.parseCurrency(record[3]); err != nil {
+			return Transaction{}, fmt.Errorf("invalid currency '%s': %w", record[3], err)
+		}
+	}
+
 	return transaction, nil
 }
 
@@ -167,74 +576,123 @@ func (loader *CSVTransactionLoader) parseIDOptimized(idStr string) (uint, error)
 }
 
 // parseDateOptimized performs optimized date parsing with cached year and layout.
-// Supports both M/D and M/D/YYYY formats automatically.
+// Supports dates with and without year (e.g. M/D and M/D/YYYY) automatically,
+// falling back to ISO 8601 dates (YYYY-MM-DD) when the configured layout doesn't match.
 func (loader *CSVTransactionLoader) parseDateOptimized(dateStr string) (time.Time, error) {
 	if dateStr == "" {
 		return time.Time{}, fmt.Errorf("date cannot be empty")
 	}
 
-	// Count slashes to determine if year is already included
-	slashCount := strings.Count(dateStr, "/")
-	
-	switch slashCount {
-	case 1:
-		// Format: M/D - add current year
-		var dateBuilder strings.Builder
-		dateBuilder.Grow(len(dateStr) + 5) // Pre-allocate capacity
-		dateBuilder.WriteString(dateStr)
-		dateBuilder.WriteByte('/')
-		dateBuilder.WriteString(strconv.Itoa(loader.currentYear))
-
-		// Parse with cached layout
-		date, err := time.Parse(loader.dateLayout, dateBuilder.String())
+	date, err := loader.parseLayoutDate(dateStr)
+	if err == nil {
+		return date, nil
+	}
+
+	// Fallback: ISO 8601 dates always include the year, so none is appended
+	if isoDate, isoErr := time.Parse(isoDateLayout, dateStr); isoErr == nil {
+		return isoDate, nil
+	}
+
+	return time.Time{}, err
+}
+
+// parseLayoutDate parses a date with the configured layout, adding the
+// current year when the date doesn't include one.
+func (loader *CSVTransactionLoader) parseLayoutDate(dateStr string) (time.Time, error) {
+	// Dates that can't be completed are only accepted in full
+	if loader.dateSeparator == "" {
+		date, err := time.Parse(loader.dateLayout, dateStr)
 		if err != nil {
-			return time.Time{}, fmt.Errorf("must be in M/D format: %w", err)
+			return time.Time{}, fmt.Errorf("must match %q: %w", loader.dateLayout, err)
 		}
 		return date, nil
+	}
 
-	case 2:
-		// Format: M/D/YYYY - parse directly
+	// Count separators to determine if year is already included
+	separatorCount := strings.Count(dateStr, loader.dateSeparator)
+
+	switch separatorCount {
+	case loader.dateSeparators - 1:
+		// Date without year - add current year where the layout expects it
+		// (only one of the prefix and suffix is set)
+		date, err := time.Parse(loader.dateLayout, loader.yearPrefix+dateStr+loader.yearSuffix)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("must match %q without year: %w", loader.dateLayout, err)
+		}
+		return date, nil
+
+	case loader.dateSeparators:
+		// Full date - parse directly
 		date, err := time.Parse(loader.dateLayout, dateStr)
 		if err != nil {
-			return time.Time{}, fmt.Errorf("must be in M/D/YYYY format: %w", err)
+			return time.Time{}, fmt.Errorf("must match %q: %w", loader.dateLayout, err)
 		}
 		return date, nil
 
 	default:
-		return time.Time{}, fmt.Errorf("invalid date format - must be M/D or M/D/YYYY, got %s", dateStr)
+		return time.Time{}, fmt.Errorf("invalid date format - must match %q with or without year, got %s", loader.dateLayout, dateStr)
 	}
 }
 
-// parseAmountOptimized performs high-performance amount parsing with minimal string operations.
-func (loader *CSVTransactionLoader) parseAmountOptimized(amountStr string) (float64, error) {
+// parseAmountOptimized parses the amount exactly into minor units (see ParseMoney).
+func (loader *CSVTransactionLoader) parseAmountOptimized(amountStr string) (Money, error) {
 	if amountStr == "" {
 		return 0, fmt.Errorf("amount cannot be empty")
 	}
 
-	// Fast path: handle positive numbers without prefix
-	if amountStr[0] != '+' && amountStr[0] != '-' {
-		amount, err := strconv.ParseFloat(amountStr, 64)
-		if err != nil {
-			return 0, fmt.Errorf("must be a valid number: %w", err)
-		}
-		return amount, nil
+	amount, err := ParseMoney(loader.normalizeAmount(amountStr))
+	if err != nil {
+		return 0, fmt.Errorf("must be a valid number with at most 2 decimals: %w", err)
 	}
+	return amount, nil
+}
 
-	// Handle signed numbers
-	if amountStr[0] == '+' {
-		// Remove leading + and parse
-		amount, err := strconv.ParseFloat(amountStr[1:], 64)
-		if err != nil {
-			return 0, fmt.Errorf("must be a valid number: %w", err)
-		}
-		return amount, nil
+// normalizeAmount converts an amount into the format accepted by ParseMoney,
+// stripping its currency symbols and thousands separators, and converting a
+// decimal comma when configured.
+func (loader *CSVTransactionLoader) normalizeAmount(amountStr string) string {
+	if len(loader.csvConfig.StripCurrencySymbols) > 0 {
+		amountStr = strings.Map(func(r rune) rune {
+			if slices.Contains(loader.csvConfig.StripCurrencySymbols, r) {
+				return -1
+			}
+			return r
+		}, amountStr)
 	}
 
-	// Handle negative numbers (ParseFloat handles this automatically)
-	amount, err := strconv.ParseFloat(amountStr, 64)
-	if err != nil {
-		return 0, fmt.Errorf("must be a valid number: %w", err)
+	thousandsSeparator, decimalSeparator := ",", "."
+	if loader.csvConfig.DecimalComma {
+		thousandsSeparator, decimalSeparator = ".", ","
 	}
 
-	return amount, nil
+	if loader.csvConfig.StripThousandsSeparators {
+		amountStr = strings.ReplaceAll(amountStr, thousandsSeparator, "")
+	}
+	if decimalSeparator != "." {
+		amountStr = strings.Replace(amountStr, decimalSeparator, ".", 1)
+	}
+	return amountStr
+}
+
+// parseCurrency parses an ISO 4217 currency code (e.g. "usd" or "MXN"),
+// returning it in upper case.
+func (loader *CSVTransactionLoader) parseCurrency(currencyStr string) (string, error) {
+	currency := strings.ToUpper(strings.TrimSpace(currencyStr))
+	if len(currency) != 3 || strings.Trim(currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", fmt.Errorf("must be a 3-letter ISO 4217 code")
+	}
+	return currency, nil
+}
+
+// checkAmountBounds validates the amount against the configured MinAmount and MaxAmount.
+// Each bound is only enforced when it is non-zero. The amount is compared in
+// major units, as bounds beyond the range of Money would overflow in cents.
+func (loader *CSVTransactionLoader) checkAmountBounds(amount Money) error {
+	if loader.csvConfig.MinAmount != 0 && amount.Float64() < loader.csvConfig.MinAmount {
+		return fmt.Errorf("must be greater than or equal to %g", loader.csvConfig.MinAmount)
+	}
+	if loader.csvConfig.MaxAmount != 0 && amount.Float64() > loader.csvConfig.MaxAmount {
+		return fmt.Errorf("must be less than or equal to %g", loader.csvConfig.MaxAmount)
+	}
+	return nil
 }
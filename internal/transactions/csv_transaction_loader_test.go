@@ -1,7 +1,12 @@
 package transactions
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"strings"
 	"testing"
 	"time"
@@ -31,17 +36,17 @@ func TestCSVTransactionLoader_LoadTransactions(t *testing.T) {
 				{
 					ID:     1,
 					Date:   time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC),
-					Amount: 60.5,
+					Amount: 60_50,
 				},
 				{
 					ID:     2,
 					Date:   time.Date(currentYear, 7, 28, 0, 0, 0, 0, time.UTC),
-					Amount: -10.3,
+					Amount: -10_30,
 				},
 				{
 					ID:     3,
 					Date:   time.Date(currentYear, 8, 2, 0, 0, 0, 0, time.UTC),
-					Amount: -20.46,
+					Amount: -20_46,
 				},
 			},
 			description: "should parse valid CSV with positive and negative amounts",
@@ -56,17 +61,17 @@ func TestCSVTransactionLoader_LoadTransactions(t *testing.T) {
 				{
 					ID:     1,
 					Date:   time.Date(2022, 1, 5, 0, 0, 0, 0, time.UTC),
-					Amount: 1250.0,
+					Amount: 1250_00,
 				},
 				{
 					ID:     2,
 					Date:   time.Date(2023, 2, 14, 0, 0, 0, 0, time.UTC),
-					Amount: -85.5,
+					Amount: -85_50,
 				},
 				{
 					ID:     3,
 					Date:   time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
-					Amount: 300.0,
+					Amount: 300_00,
 				},
 			},
 			description: "should parse valid CSV with full year dates",
@@ -81,21 +86,46 @@ func TestCSVTransactionLoader_LoadTransactions(t *testing.T) {
 				{
 					ID:     1,
 					Date:   time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC),
-					Amount: 60.5,
+					Amount: 60_50,
 				},
 				{
 					ID:     2,
 					Date:   time.Date(2022, 1, 5, 0, 0, 0, 0, time.UTC),
-					Amount: 1250.0,
+					Amount: 1250_00,
 				},
 				{
 					ID:     3,
 					Date:   time.Date(currentYear, 8, 2, 0, 0, 0, 0, time.UTC),
-					Amount: -20.46,
+					Amount: -20_46,
 				},
 			},
 			description: "should handle mixed M/D and M/D/YYYY formats in same file",
 		},
+		{
+			name: "it should fall back to ISO 8601 dates",
+			csvContent: `ID,Date,Transaction
+1,7/15,+60.5
+2,1/5/2022,+1250.0
+3,2023-07-15,-20.46`,
+			expectedResult: []Transaction{
+				{
+					ID:     1,
+					Date:   time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC),
+					Amount: 60_50,
+				},
+				{
+					ID:     2,
+					Date:   time.Date(2022, 1, 5, 0, 0, 0, 0, time.UTC),
+					Amount: 1250_00,
+				},
+				{
+					ID:     3,
+					Date:   time.Date(2023, 7, 15, 0, 0, 0, 0, time.UTC),
+					Amount: -20_46,
+				},
+			},
+			description: "should handle M/D, M/D/YYYY and YYYY-MM-DD formats in same file",
+		},
 		{
 			name: "it should handle transactions without sign prefix",
 			csvContent: `ID,Date,Transaction
@@ -105,12 +135,12 @@ func TestCSVTransactionLoader_LoadTransactions(t *testing.T) {
 				{
 					ID:     1,
 					Date:   time.Date(currentYear, 1, 1, 0, 0, 0, 0, time.UTC),
-					Amount: 100.00,
+					Amount: 100_00,
 				},
 				{
 					ID:     2,
 					Date:   time.Date(currentYear, 2, 14, 0, 0, 0, 0, time.UTC),
-					Amount: 250.75,
+					Amount: 250_75,
 				},
 			},
 			description: "should handle amounts without explicit + sign",
@@ -256,6 +286,7 @@ func TestCSVTransactionLoader_CustomConfiguration(t *testing.T) {
 				BufferSize:      1024,
 				ExpectedRecords: 10,
 				FieldsPerRecord: 3,
+				HasHeader:       true,
 			},
 			csvContent: `ID,Date,Transaction
 1,7/15,+60.5`,
@@ -268,6 +299,7 @@ func TestCSVTransactionLoader_CustomConfiguration(t *testing.T) {
 				BufferSize:      64 * 1024,
 				ExpectedRecords: 1000,
 				FieldsPerRecord: 3,
+				HasHeader:       true,
 			},
 			csvContent: `ID,Date,Transaction
 1,7/15,+60.5`,
@@ -314,9 +346,14 @@ func TestDefaultCSVConfig(t *testing.T) {
 	t.Run("it should return correct default configuration", func(t *testing.T) {
 		// Arrange
 		expectedConfig := CSVTransactionLoaderConfig{
-			BufferSize:      64 * 1024,
-			ExpectedRecords: 100,
-			FieldsPerRecord: 3,
+			BufferSize:          64 * 1024,
+			ExpectedRecords:     100,
+			FieldsPerRecord:     0,
+			HasHeader:           true,
+			AutoDecompress:      true,
+			DateLayout:          "1/2/2006",
+			Delimiter:           ',',
+			MaxValidationErrors: 10,
 		}
 
 		// Act
@@ -343,7 +380,7 @@ func TestCSVTransactionLoader_EdgeCases(t *testing.T) {
 				{
 					ID:     1,
 					Date:   time.Date(time.Now().Year(), 7, 15, 0, 0, 0, 0, time.UTC),
-					Amount: 60.5,
+					Amount: 60_50,
 				},
 			},
 			description: "should trim leading whitespace from fields",
@@ -381,30 +418,202 @@ func TestCSVTransactionLoader_EdgeCases(t *testing.T) {
 				{
 					ID:     4294967295,
 					Date:   time.Date(time.Now().Year(), 1, 1, 0, 0, 0, 0, time.UTC),
-					Amount: 100.00,
+					Amount: 100_00,
 				},
 			},
 			description: "should handle maximum uint32 ID values",
 		},
 		{
-			name: "it should handle decimal amounts with many digits",
+			name: "it should reject decimal amounts with more than 2 decimals",
 			csvContent: `ID,Date,Transaction
 1,1/1,123.456789`,
+			expectedError: "record validation error at line 2",
+			description:   "should fail when the amount can't be represented exactly in cents",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			loader := NewCSVTransactionLoader()
+			reader := strings.NewReader(tc.csvContent)
+			ctx := context.Background()
+
+			// Act
+			result, err := loader.LoadTransactions(ctx, reader)
+
+			// Assert
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.description)
+				assert.Contains(t, err.Error(), tc.expectedError, tc.description)
+				assert.Nil(t, result, tc.description)
+			} else {
+				require.NoError(t, err, tc.description)
+				assert.Equal(t, tc.expectedResult, result, tc.description)
+			}
+		})
+	}
+}
+
+func TestCSVTransactionLoader_OptionalHeader(t *testing.T) {
+	currentYear := time.Now().Year()
+
+	testCases := []struct {
+		name           string
+		hasHeader      bool
+		csvContent     string
+		expectedResult []Transaction
+		expectedError  string
+		description    string
+	}{
+		{
+			name:      "it should parse the first line as a transaction when there is no header",
+			hasHeader: false,
+			csvContent: `1,7/15,+60.5
+2,7/28,-10.3`,
 			expectedResult: []Transaction{
 				{
 					ID:     1,
-					Date:   time.Date(time.Now().Year(), 1, 1, 0, 0, 0, 0, time.UTC),
-					Amount: 123.456789,
+					Date:   time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC),
+					Amount: 60_50,
+				},
+				{
+					ID:     2,
+					Date:   time.Date(currentYear, 7, 28, 0, 0, 0, 0, time.UTC),
+					Amount: -10_30,
 				},
 			},
-			description: "should handle high precision decimal amounts",
+			description: "should not skip the first line when HasHeader is false",
+		},
+		{
+			name:           "it should return empty slice for empty headerless content",
+			hasHeader:      false,
+			csvContent:     ``,
+			expectedResult: []Transaction{},
+			description:    "should not fail when there is no header to read",
+		},
+		{
+			name:      "it should report line 1 for an invalid first row without header",
+			hasHeader: false,
+			csvContent: `abc,7/15,+60.5
+2,7/28,-10.3`,
+			expectedError: "record validation error at line 1",
+			description:   "should number lines from 1 when there is no header",
+		},
+		{
+			name:      "it should report line 3 for an invalid second row without header",
+			hasHeader: false,
+			csvContent: `1,7/15,+60.5
+2,7/28,-10.3
+3,abc,-20.46`,
+			expectedError: "record validation error at line 3",
+			description:   "should keep line numbers aligned with the file when there is no header",
+		},
+		{
+			name:      "it should report line 3 for an invalid second row with header",
+			hasHeader: true,
+			csvContent: `ID,Date,Transaction
+1,7/15,+60.5
+2,abc,-10.3`,
+			expectedError: "record validation error at line 3",
+			description:   "should keep line numbers aligned with the file when there is a header",
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// Arrange
-			loader := NewCSVTransactionLoader()
+			config := DefaultCSVConfig()
+			config.HasHeader = tc.hasHeader
+			loader := NewCSVTransactionLoaderWithConfig(config)
+			reader := strings.NewReader(tc.csvContent)
+			ctx := context.Background()
+
+			// Act
+			result, err := loader.LoadTransactions(ctx, reader)
+
+			// Assert
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.description)
+				assert.Contains(t, err.Error(), tc.expectedError, tc.description)
+				assert.Nil(t, result, tc.description)
+			} else {
+				require.NoError(t, err, tc.description)
+				assert.Equal(t, tc.expectedResult, result, tc.description)
+			}
+		})
+	}
+}
+
+func TestCSVTransactionLoader_RejectDuplicateIDs(t *testing.T) {
+	currentYear := time.Now().Year()
+
+	testCases := []struct {
+		name               string
+		rejectDuplicateIDs bool
+		csvContent         string
+		expectedResult     []Transaction
+		expectedError      string
+		description        string
+	}{
+		{
+			name:               "it should load a file without duplicated IDs",
+			rejectDuplicateIDs: true,
+			csvContent: `ID,Date,Transaction
+1,7/15,+60.5
+2,7/28,-10.3`,
+			expectedResult: []Transaction{
+				{
+					ID:     1,
+					Date:   time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC),
+					Amount: 60_50,
+				},
+				{
+					ID:     2,
+					Date:   time.Date(currentYear, 7, 28, 0, 0, 0, 0, time.UTC),
+					Amount: -10_30,
+				},
+			},
+			description: "should accept unique IDs when duplicate detection is enabled",
+		},
+		{
+			name:               "it should reject a file with a repeated ID",
+			rejectDuplicateIDs: true,
+			csvContent: `ID,Date,Transaction
+42,7/15,+60.5
+2,7/28,-10.3
+42,8/2,-20.46`,
+			expectedError: "duplicate transaction ID 42 at line 4",
+			description:   "should fail and reference the line of the repeated ID",
+		},
+		{
+			name:               "it should keep repeated IDs when duplicate detection is disabled",
+			rejectDuplicateIDs: false,
+			csvContent: `ID,Date,Transaction
+42,7/15,+60.5
+42,7/28,-10.3`,
+			expectedResult: []Transaction{
+				{
+					ID:     42,
+					Date:   time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC),
+					Amount: 60_50,
+				},
+				{
+					ID:     42,
+					Date:   time.Date(currentYear, 7, 28, 0, 0, 0, 0, time.UTC),
+					Amount: -10_30,
+				},
+			},
+			description: "should preserve current behavior by default",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			config := DefaultCSVConfig()
+			config.RejectDuplicateIDs = tc.rejectDuplicateIDs
+			loader := NewCSVTransactionLoaderWithConfig(config)
 			reader := strings.NewReader(tc.csvContent)
 			ctx := context.Background()
 
@@ -423,3 +632,1185 @@ func TestCSVTransactionLoader_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestCSVTransactionLoader_AutoDecompress(t *testing.T) {
+	csvContent := `ID,Date,Transaction
+1,7/15,+60.5
+2,7/28,-10.3
+3,8/2,-20.46`
+
+	gzipContent := func(t *testing.T, content string) []byte {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		_, err := writer.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		return buf.Bytes()
+	}
+
+	t.Run("it should produce identical results for plain and gzipped content", func(t *testing.T) {
+		// Arrange
+		loader := NewCSVTransactionLoader()
+		ctx := context.Background()
+
+		// Act
+		plainResult, plainErr := loader.LoadTransactions(ctx, strings.NewReader(csvContent))
+		gzipResult, gzipErr := loader.LoadTransactions(ctx, bytes.NewReader(gzipContent(t, csvContent)))
+
+		// Assert
+		require.NoError(t, plainErr, "should parse plain content")
+		require.NoError(t, gzipErr, "should parse gzipped content")
+		assert.Len(t, plainResult, 3, "should parse every plain record")
+		assert.Equal(t, plainResult, gzipResult, "should produce identical transactions")
+	})
+
+	t.Run("it should not decompress gzipped content when disabled", func(t *testing.T) {
+		// Arrange
+		config := DefaultCSVConfig()
+		config.AutoDecompress = false
+		loader := NewCSVTransactionLoaderWithConfig(config)
+		ctx := context.Background()
+
+		// Act
+		result, err := loader.LoadTransactions(ctx, bytes.NewReader(gzipContent(t, csvContent)))
+
+		// Assert
+		assert.Error(t, err, "should fail to parse raw gzip bytes as CSV")
+		assert.Nil(t, result, "should return nil result on error")
+	})
+
+	t.Run("it should fail on a corrupted gzip stream", func(t *testing.T) {
+		// Arrange
+		loader := NewCSVTransactionLoader()
+		ctx := context.Background()
+
+		// Act
+		result, err := loader.LoadTransactions(ctx, bytes.NewReader([]byte{0x1f, 0x8b, 0x00}))
+
+		// Assert
+		assert.Error(t, err, "should fail when gzip header is invalid")
+		assert.Nil(t, result, "should return nil result on error")
+	})
+}
+
+func TestCSVTransactionLoader_CollectErrors(t *testing.T) {
+	currentYear := time.Now().Year()
+
+	t.Run("it should keep valid rows and report every invalid line", func(t *testing.T) {
+		// Arrange
+		config := DefaultCSVConfig()
+		config.CollectErrors = true
+		loader := NewCSVTransactionLoaderWithConfig(config)
+		csvContent := `ID,Date,Transaction
+1,7/15,+60.5
+abc,7/16,+10.0
+2,7/28,-10.3
+3,7/29
+4,bad,-1.0
+5,8/2,-20.46`
+		ctx := context.Background()
+
+		// Act
+		result, err := loader.LoadTransactions(ctx, strings.NewReader(csvContent))
+
+		// Assert
+		require.Error(t, err, "should report the invalid records")
+		assert.Equal(t, []Transaction{
+			{ID: 1, Date: time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50},
+			{ID: 2, Date: time.Date(currentYear, 7, 28, 0, 0, 0, 0, time.UTC), Amount: -10_30},
+			{ID: 5, Date: time.Date(currentYear, 8, 2, 0, 0, 0, 0, time.UTC), Amount: -20_46},
+		}, result, "should return every valid transaction")
+
+		var recordErrs RecordErrors
+		require.True(t, errors.As(err, &recordErrs), "should return a RecordErrors aggregate")
+		require.Len(t, recordErrs, 3, "should list every invalid line")
+		assert.Equal(t, 3, recordErrs[0].Line)
+		assert.Contains(t, recordErrs[0].Error(), "record validation error at line 3")
+		assert.Equal(t, 5, recordErrs[1].Line)
+		assert.Contains(t, recordErrs[1].Error(), "CSV parsing error at line 5")
+		assert.Equal(t, 6, recordErrs[2].Line)
+		assert.Contains(t, recordErrs[2].Error(), "record validation error at line 6")
+		assert.Len(t, recordErrs.Unwrap(), 3, "should unwrap into every record error")
+	})
+
+	t.Run("it should unwrap to the underlying record errors", func(t *testing.T) {
+		// Arrange
+		config := DefaultCSVConfig()
+		config.CollectErrors = true
+		loader := NewCSVTransactionLoaderWithConfig(config)
+		csvContent := `ID,Date,Transaction
+1,7/15`
+		ctx := context.Background()
+
+		// Act
+		result, err := loader.LoadTransactions(ctx, strings.NewReader(csvContent))
+
+		// Assert
+		assert.Empty(t, result, "should not return invalid transactions")
+		var recordErr RecordError
+		require.True(t, errors.As(err, &recordErr), "should expose each RecordError")
+		assert.Equal(t, 2, recordErr.Line)
+	})
+
+	t.Run("it should return no error when every row is valid", func(t *testing.T) {
+		// Arrange
+		config := DefaultCSVConfig()
+		config.CollectErrors = true
+		loader := NewCSVTransactionLoaderWithConfig(config)
+		csvContent := `ID,Date,Transaction
+1,7/15,+60.5`
+		ctx := context.Background()
+
+		// Act
+		result, err := loader.LoadTransactions(ctx, strings.NewReader(csvContent))
+
+		// Assert
+		assert.NoError(t, err, "should not report errors for a clean file")
+		assert.Len(t, result, 1, "should return the valid transaction")
+	})
+
+	t.Run("it should fail right away when the content can't be read", func(t *testing.T) {
+		// Arrange
+		var compressed bytes.Buffer
+		gzipWriter := gzip.NewWriter(&compressed)
+		_, err := gzipWriter.Write([]byte(strings.Repeat("1,7/15,+60.5\n", 1000)))
+		require.NoError(t, err)
+		require.NoError(t, gzipWriter.Close())
+		truncated := compressed.Bytes()[:compressed.Len()/2]
+
+		config := DefaultCSVConfig()
+		config.CollectErrors = true
+		config.HasHeader = false
+		loader := NewCSVTransactionLoaderWithConfig(config)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// Act
+		result, err := loader.LoadTransactions(ctx, bytes.NewReader(truncated))
+
+		// Assert
+		require.Error(t, err)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.NotErrorIs(t, err, context.DeadlineExceeded, "should not keep reading until the deadline")
+		assert.False(t, errors.As(err, new(RecordErrors)), "should not report the read failure as invalid records")
+		assert.Nil(t, result)
+	})
+
+	t.Run("it should fail on the first invalid row in strict mode", func(t *testing.T) {
+		// Arrange
+		loader := NewCSVTransactionLoader()
+		csvContent := `ID,Date,Transaction
+abc,7/15,+60.5
+2,bad,-10.3`
+		ctx := context.Background()
+
+		// Act
+		result, err := loader.LoadTransactions(ctx, strings.NewReader(csvContent))
+
+		// Assert
+		require.Error(t, err, "should fail in strict mode")
+		assert.Contains(t, err.Error(), "record validation error at line 2")
+		assert.False(t, errors.As(err, new(RecordErrors)), "should not aggregate errors in strict mode")
+		assert.Nil(t, result, "should return nil result in strict mode")
+	})
+}
+
+func TestCSVTransactionLoader_DateLayout(t *testing.T) {
+	currentYear := time.Now().Year()
+
+	testCases := []struct {
+		name           string
+		dateLayout     string
+		csvContent     string
+		expectedResult []Transaction
+		expectedError  string
+		description    string
+	}{
+		{
+			name:       "it should parse day-first dates without year",
+			dateLayout: "2/1/2006",
+			csvContent: `ID,Date,Transaction
+1,2/1,+60.5`,
+			expectedResult: []Transaction{
+				{ID: 1, Date: time.Date(currentYear, time.January, 2, 0, 0, 0, 0, time.UTC), Amount: 60_50},
+			},
+			description: "should parse 2/1 as the 2nd of January under a D/M layout",
+		},
+		{
+			name:       "it should parse day-first dates with year",
+			dateLayout: "2/1/2006",
+			csvContent: `ID,Date,Transaction
+1,2/1/2021,+60.5
+2,31/12/2020,-10.0`,
+			expectedResult: []Transaction{
+				{ID: 1, Date: time.Date(2021, time.January, 2, 0, 0, 0, 0, time.UTC), Amount: 60_50},
+				{ID: 2, Date: time.Date(2020, time.December, 31, 0, 0, 0, 0, time.UTC), Amount: -10_00},
+			},
+			description: "should not append a spurious year to full dates",
+		},
+		{
+			name:       "it should add a year token to layouts without one",
+			dateLayout: "2/1",
+			csvContent: `ID,Date,Transaction
+1,1/2,+60.5
+2,1/2/2021,-10.0`,
+			expectedResult: []Transaction{
+				{ID: 1, Date: time.Date(currentYear, time.February, 1, 0, 0, 0, 0, time.UTC), Amount: 60_50},
+				{ID: 2, Date: time.Date(2021, time.February, 1, 0, 0, 0, 0, time.UTC), Amount: -10_00},
+			},
+			description: "should accept dates with and without year for a year-less layout",
+		},
+		{
+			name:       "it should prepend the current year for year-first layouts",
+			dateLayout: "2006/1/2",
+			csvContent: `ID,Date,Transaction
+1,7/15,+60.5
+2,2021/7/15,-10.0`,
+			expectedResult: []Transaction{
+				{ID: 1, Date: time.Date(currentYear, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50},
+				{ID: 2, Date: time.Date(2021, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: -10_00},
+			},
+			description: "should add the current year where the layout expects it",
+		},
+		{
+			name:       "it should complete dates of dot-separated layouts",
+			dateLayout: "02.01",
+			csvContent: `ID,Date,Transaction
+1,15.07,+60.5
+2,15.07.2021,-10.0`,
+			expectedResult: []Transaction{
+				{ID: 1, Date: time.Date(currentYear, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50},
+				{ID: 2, Date: time.Date(2021, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: -10_00},
+			},
+			description: "should add the year with the separator of the layout",
+		},
+		{
+			name:       "it should complete dates of dash-separated year-first layouts",
+			dateLayout: "2006-01-02",
+			csvContent: `ID,Date,Transaction
+1,07-15,+60.5
+2,2021-07-15,-10.0`,
+			expectedResult: []Transaction{
+				{ID: 1, Date: time.Date(currentYear, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50},
+				{ID: 2, Date: time.Date(2021, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: -10_00},
+			},
+			description: "should prepend the year with the separator of the layout",
+		},
+		{
+			name:       "it should not append a four-digit year to two-digit year layouts",
+			dateLayout: "1/2/06",
+			csvContent: `ID,Date,Transaction
+1,7/15,+60.5
+2,7/15/21,-10.0`,
+			expectedResult: []Transaction{
+				{ID: 1, Date: time.Date(currentYear, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50},
+				{ID: 2, Date: time.Date(2021, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: -10_00},
+			},
+			description: "should treat 06 as the year component of the layout",
+		},
+		{
+			name:       "it should reject month-first dates under a day-first layout",
+			dateLayout: "2/1/2006",
+			csvContent: `ID,Date,Transaction
+1,7/15,+60.5`,
+			expectedError: "record validation error at line 2",
+			description:   "should fail when the month is out of range for the layout",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			config := DefaultCSVConfig()
+			config.DateLayout = tc.dateLayout
+			loader := NewCSVTransactionLoaderWithConfig(config)
+			reader := strings.NewReader(tc.csvContent)
+			ctx := context.Background()
+
+			// Act
+			result, err := loader.LoadTransactions(ctx, reader)
+
+			// Assert
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.description)
+				assert.Contains(t, err.Error(), tc.expectedError, tc.description)
+				assert.Nil(t, result, tc.description)
+			} else {
+				require.NoError(t, err, tc.description)
+				assert.Equal(t, tc.expectedResult, result, tc.description)
+			}
+		})
+	}
+}
+
+func TestCSVTransactionLoader_AmountBounds(t *testing.T) {
+	currentYear := time.Now().Year()
+
+	testCases := []struct {
+		name           string
+		minAmount      float64
+		maxAmount      float64
+		csvContent     string
+		expectedResult []Transaction
+		expectedError  string
+		description    string
+	}{
+		{
+			name:      "it should reject an amount above the maximum",
+			minAmount: -1000,
+			maxAmount: 1000,
+			csvContent: `ID,Date,Transaction
+1,7/15,+60.5
+2,7/16,1000.01`,
+			expectedError: "record validation error at line 3",
+			description:   "should fail when the amount is greater than MaxAmount",
+		},
+		{
+			name:      "it should reject an amount below the minimum",
+			minAmount: -1000,
+			maxAmount: 1000,
+			csvContent: `ID,Date,Transaction
+1,7/15,-1000.01`,
+			expectedError: "record validation error at line 2",
+			description:   "should fail when the amount is lower than MinAmount",
+		},
+		{
+			name:      "it should accept amounts on the boundaries",
+			minAmount: -1000,
+			maxAmount: 1000,
+			csvContent: `ID,Date,Transaction
+1,7/15,-1000
+2,7/16,+1000`,
+			expectedResult: []Transaction{
+				{ID: 1, Date: time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC), Amount: -1000_00},
+				{ID: 2, Date: time.Date(currentYear, 7, 16, 0, 0, 0, 0, time.UTC), Amount: 1000_00},
+			},
+			description: "should treat both bounds as inclusive",
+		},
+		{
+			name:      "it should accept amounts on fractional boundaries",
+			minAmount: -10.01,
+			maxAmount: 10.01,
+			csvContent: `ID,Date,Transaction
+1,7/15,-10.01
+2,7/16,+10.01`,
+			expectedResult: []Transaction{
+				{ID: 1, Date: time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC), Amount: -10_01},
+				{ID: 2, Date: time.Date(currentYear, 7, 16, 0, 0, 0, 0, time.UTC), Amount: 10_01},
+			},
+			description: "should treat fractional bounds as inclusive",
+		},
+		{
+			name:      "it should accept bounds beyond the range of Money",
+			minAmount: -1e18,
+			maxAmount: 1e18,
+			csvContent: `ID,Date,Transaction
+1,7/15,-5000
+2,7/16,+5000`,
+			expectedResult: []Transaction{
+				{ID: 1, Date: time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC), Amount: -5000_00},
+				{ID: 2, Date: time.Date(currentYear, 7, 16, 0, 0, 0, 0, time.UTC), Amount: 5000_00},
+			},
+			description: "should not overflow when converting huge bounds",
+		},
+		{
+			name:      "it should only enforce the configured bound",
+			maxAmount: 1000,
+			csvContent: `ID,Date,Transaction
+1,7/15,-5000`,
+			expectedResult: []Transaction{
+				{ID: 1, Date: time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC), Amount: -5000_00},
+			},
+			description: "should not enforce a lower bound when MinAmount is zero",
+		},
+		{
+			name: "it should not enforce bounds when both are zero",
+			csvContent: `ID,Date,Transaction
+1,7/15,90000000000`,
+			expectedResult: []Transaction{
+				{ID: 1, Date: time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC), Amount: 90000000000_00},
+			},
+			description: "should behave as before when no bounds are configured",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			config := DefaultCSVConfig()
+			config.MinAmount = tc.minAmount
+			config.MaxAmount = tc.maxAmount
+			loader := NewCSVTransactionLoaderWithConfig(config)
+			reader := strings.NewReader(tc.csvContent)
+			ctx := context.Background()
+
+			// Act
+			result, err := loader.LoadTransactions(ctx, reader)
+
+			// Assert
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.description)
+				assert.Contains(t, err.Error(), tc.expectedError, tc.description)
+				assert.Nil(t, result, tc.description)
+			} else {
+				require.NoError(t, err, tc.description)
+				assert.Equal(t, tc.expectedResult, result, tc.description)
+			}
+		})
+	}
+}
+
+func TestCSVTransactionLoader_Currency(t *testing.T) {
+	currentYear := time.Now().Year()
+
+	testCases := []struct {
+		name            string
+		defaultCurrency string
+		csvContent      string
+		expectedResult  []Transaction
+		expectedError   string
+		description     string
+	}{
+		{
+			name: "it should parse the currency column when present",
+			csvContent: `ID,Date,Transaction,Currency
+1,7/15,+60.5,USD
+2,7/16,-10.3,mxn`,
+			expectedResult: []Transaction{
+				{ID: 1, Date: time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50, Currency: "USD"},
+				{ID: 2, Date: time.Date(currentYear, 7, 16, 0, 0, 0, 0, time.UTC), Amount: -10_30, Currency: "MXN"},
+			},
+			description: "should read the currency of each record, in upper case",
+		},
+		{
+			name:            "it should default to the configured currency when the column is absent",
+			defaultCurrency: "MXN",
+			csvContent: `ID,Date,Transaction
+1,7/15,+60.5`,
+			expectedResult: []Transaction{
+				{ID: 1, Date: time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50, Currency: "MXN"},
+			},
+			description: "should use DefaultCurrency for files without a currency column",
+		},
+		{
+			name:            "it should default to the configured currency when the column is empty",
+			defaultCurrency: "MXN",
+			csvContent: `ID,Date,Transaction,Currency
+1,7/15,+60.5,`,
+			expectedResult: []Transaction{
+				{ID: 1, Date: time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50, Currency: "MXN"},
+			},
+			description: "should use DefaultCurrency for records without a currency",
+		},
+		{
+			name: "it should reject an invalid currency",
+			csvContent: `ID,Date,Transaction,Currency
+1,7/15,+60.5,dollars`,
+			expectedError: "record validation error at line 2",
+			description:   "should fail when the currency isn't an ISO 4217 code",
+		},
+		{
+			name: "it should reject records without the currency column of the header",
+			csvContent: `ID,Date,Transaction,Currency
+1,7/15,+60.5`,
+			expectedError: "CSV parsing error at line 2",
+			description:   "should fail when records have fewer fields than the header",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			config := DefaultCSVConfig()
+			config.DefaultCurrency = tc.defaultCurrency
+			loader := NewCSVTransactionLoaderWithConfig(config)
+			reader := strings.NewReader(tc.csvContent)
+			ctx := context.Background()
+
+			// Act
+			result, err := loader.LoadTransactions(ctx, reader)
+
+			// Assert
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.description)
+				assert.Contains(t, err.Error(), tc.expectedError, tc.description)
+				assert.Nil(t, result, tc.description)
+			} else {
+				require.NoError(t, err, tc.description)
+				assert.Equal(t, tc.expectedResult, result, tc.description)
+			}
+		})
+	}
+}
+
+func TestCSVTransactionLoader_ValidateTransactions(t *testing.T) {
+	t.Run("it should report every row as valid for a clean file", func(t *testing.T) {
+		// Arrange
+		loader := NewCSVTransactionLoader()
+		csvContent := `ID,Date,Transaction
+1,7/15,+60.5
+2,7/28,-10.3
+3,8/2,-20.46`
+		ctx := context.Background()
+
+		// Act
+		report, err := loader.ValidateTransactions(ctx, strings.NewReader(csvContent))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, ValidationReport{TotalRows: 3, ValidRows: 3}, report)
+		assert.True(t, report.Valid())
+	})
+
+	t.Run("it should count and list the invalid rows", func(t *testing.T) {
+		// Arrange
+		loader := NewCSVTransactionLoader()
+		csvContent := `ID,Date,Transaction
+1,7/15,+60.5
+abc,7/16,+10.0
+2,7/28,-10.3
+3,7/29
+4,bad,-1.0
+5,8/2,-20.46`
+		ctx := context.Background()
+
+		// Act
+		report, err := loader.ValidateTransactions(ctx, strings.NewReader(csvContent))
+
+		// Assert
+		require.NoError(t, err, "invalid rows should be reported, not returned as an error")
+		assert.False(t, report.Valid())
+		assert.Equal(t, 6, report.TotalRows)
+		assert.Equal(t, 3, report.ValidRows)
+		assert.Equal(t, 3, report.InvalidRows)
+		require.Len(t, report.Errors, 3)
+		assert.Equal(t, 3, report.Errors[0].Line)
+		assert.Contains(t, report.Errors[0].Error(), "record validation error at line 3")
+		assert.Equal(t, 5, report.Errors[1].Line)
+		assert.Contains(t, report.Errors[1].Error(), "CSV parsing error at line 5")
+		assert.Equal(t, 6, report.Errors[2].Line)
+	})
+
+	t.Run("it should only list the first configured number of errors", func(t *testing.T) {
+		// Arrange
+		config := DefaultCSVConfig()
+		config.MaxValidationErrors = 2
+		loader := NewCSVTransactionLoaderWithConfig(config)
+		csvContent := `ID,Date,Transaction
+abc,7/15,+60.5
+def,7/16,+10.0
+2,7/28,-10.3
+ghi,7/29,-1.0`
+		ctx := context.Background()
+
+		// Act
+		report, err := loader.ValidateTransactions(ctx, strings.NewReader(csvContent))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 4, report.TotalRows)
+		assert.Equal(t, 1, report.ValidRows)
+		assert.Equal(t, 3, report.InvalidRows, "should count every invalid row")
+		require.Len(t, report.Errors, 2, "should only list the first errors")
+		assert.Equal(t, 2, report.Errors[0].Line)
+		assert.Equal(t, 3, report.Errors[1].Line)
+	})
+
+	t.Run("it should report duplicate IDs when they are rejected", func(t *testing.T) {
+		// Arrange
+		config := DefaultCSVConfig()
+		config.RejectDuplicateIDs = true
+		loader := NewCSVTransactionLoaderWithConfig(config)
+		csvContent := `ID,Date,Transaction
+1,7/15,+60.5
+1,7/16,+10.0`
+		ctx := context.Background()
+
+		// Act
+		report, err := loader.ValidateTransactions(ctx, strings.NewReader(csvContent))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.InvalidRows)
+		require.Len(t, report.Errors, 1)
+		assert.Contains(t, report.Errors[0].Error(), "duplicate transaction ID 1")
+	})
+
+	t.Run("it should validate gzipped content", func(t *testing.T) {
+		// Arrange
+		var compressed bytes.Buffer
+		gzipWriter := gzip.NewWriter(&compressed)
+		_, err := gzipWriter.Write([]byte("ID,Date,Transaction\n1,7/15,+60.5\n"))
+		require.NoError(t, err)
+		require.NoError(t, gzipWriter.Close())
+		loader := NewCSVTransactionLoader()
+
+		// Act
+		report, err := loader.ValidateTransactions(context.Background(), &compressed)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, ValidationReport{TotalRows: 1, ValidRows: 1}, report)
+	})
+
+	t.Run("it should fail right away when the content can't be read", func(t *testing.T) {
+		// Arrange
+		var compressed bytes.Buffer
+		gzipWriter := gzip.NewWriter(&compressed)
+		_, err := gzipWriter.Write([]byte("ID,Date,Transaction\n" + strings.Repeat("1,7/15,+60.5\n", 1000)))
+		require.NoError(t, err)
+		require.NoError(t, gzipWriter.Close())
+		truncated := compressed.Bytes()[:compressed.Len()/2]
+		loader := NewCSVTransactionLoader()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// Act
+		report, err := loader.ValidateTransactions(ctx, bytes.NewReader(truncated))
+
+		// Assert
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.NotErrorIs(t, err, context.DeadlineExceeded, "should not keep reading until the deadline")
+		assert.Equal(t, ValidationReport{}, report)
+	})
+
+	t.Run("it should fail when the header can't be read", func(t *testing.T) {
+		// Arrange
+		loader := NewCSVTransactionLoader()
+
+		// Act
+		_, err := loader.ValidateTransactions(context.Background(), strings.NewReader(""))
+
+		// Assert
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read CSV header")
+	})
+
+	t.Run("it should fail when the context is cancelled", func(t *testing.T) {
+		// Arrange
+		loader := NewCSVTransactionLoader()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Act
+		_, err := loader.ValidateTransactions(ctx, strings.NewReader("ID,Date,Transaction\n1,7/15,+60.5"))
+
+		// Assert
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestCSVTransactionLoader_QuotedFields(t *testing.T) {
+	currentYear := time.Now().Year()
+
+	t.Run("it should accept quoted fields containing the delimiter", func(t *testing.T) {
+		// Arrange
+		loader := NewCSVTransactionLoader()
+		csvContent := `ID,Date,"Transaction, in USD"
+"1","7/15","+60.5"
+2,7/28,"-10.3"`
+		ctx := context.Background()
+
+		// Act
+		result, err := loader.LoadTransactions(ctx, strings.NewReader(csvContent))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []Transaction{
+			{ID: 1, Date: time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50},
+			{ID: 2, Date: time.Date(currentYear, 7, 28, 0, 0, 0, 0, time.UTC), Amount: -10_30},
+		}, result)
+	})
+
+	t.Run("it should keep an embedded delimiter within its field", func(t *testing.T) {
+		// Arrange
+		loader := NewCSVTransactionLoader()
+		csvContent := `ID,Date,Transaction
+1,7/15,"1,000.50"`
+		ctx := context.Background()
+
+		// Act
+		_, err := loader.LoadTransactions(ctx, strings.NewReader(csvContent))
+
+		// Assert
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid amount '1,000.50'", "the quoted field should not be split")
+	})
+
+	malformedContent := `ID,Date,Transaction "USD"
+1,7/15,+60.5`
+
+	t.Run("it should reject malformed quotes in strict mode", func(t *testing.T) {
+		// Arrange
+		loader := NewCSVTransactionLoader()
+		ctx := context.Background()
+
+		// Act
+		result, err := loader.LoadTransactions(ctx, strings.NewReader(malformedContent))
+
+		// Assert
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read CSV header")
+		assert.Nil(t, result)
+	})
+
+	t.Run("it should tolerate malformed quotes in lazy mode", func(t *testing.T) {
+		// Arrange
+		config := DefaultCSVConfig()
+		config.LazyQuotes = true
+		loader := NewCSVTransactionLoaderWithConfig(config)
+		ctx := context.Background()
+
+		// Act
+		result, err := loader.LoadTransactions(ctx, strings.NewReader(malformedContent))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []Transaction{
+			{ID: 1, Date: time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50},
+		}, result)
+	})
+}
+
+func TestCSVTransactionLoader_ByteOrderMark(t *testing.T) {
+	currentYear := time.Now().Year()
+	expected := []Transaction{
+		{ID: 1, Date: time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50},
+		{ID: 2, Date: time.Date(currentYear, 7, 28, 0, 0, 0, 0, time.UTC), Amount: -10_30},
+	}
+
+	testCases := []struct {
+		name       string
+		hasHeader  bool
+		csvContent string
+	}{
+		{
+			name:       "it should strip a leading BOM before the header",
+			hasHeader:  true,
+			csvContent: "\xEF\xBB\xBFID,Date,Transaction\n1,7/15,+60.5\n2,7/28,-10.3",
+		},
+		{
+			name:       "it should strip a leading BOM before the first ID of a headerless file",
+			hasHeader:  false,
+			csvContent: "\xEF\xBB\xBF1,7/15,+60.5\n2,7/28,-10.3",
+		},
+		{
+			name:       "it should load a file without BOM unchanged",
+			hasHeader:  true,
+			csvContent: "ID,Date,Transaction\n1,7/15,+60.5\n2,7/28,-10.3",
+		},
+		{
+			name:       "it should load a headerless file without BOM unchanged",
+			hasHeader:  false,
+			csvContent: "1,7/15,+60.5\n2,7/28,-10.3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			config := DefaultCSVConfig()
+			config.HasHeader = tc.hasHeader
+			loader := NewCSVTransactionLoaderWithConfig(config)
+			ctx := context.Background()
+
+			// Act
+			result, err := loader.LoadTransactions(ctx, strings.NewReader(tc.csvContent))
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, expected, result)
+		})
+	}
+
+	t.Run("it should strip a BOM from gzipped content", func(t *testing.T) {
+		// Arrange
+		var compressed bytes.Buffer
+		gzipWriter := gzip.NewWriter(&compressed)
+		_, err := gzipWriter.Write([]byte("\xEF\xBB\xBF1,7/15,+60.5\n2,7/28,-10.3"))
+		require.NoError(t, err)
+		require.NoError(t, gzipWriter.Close())
+		config := DefaultCSVConfig()
+		config.HasHeader = false
+		loader := NewCSVTransactionLoaderWithConfig(config)
+
+		// Act
+		result, err := loader.LoadTransactions(context.Background(), &compressed)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+}
+
+func TestCSVTransactionLoader_NormalizeLineEndings(t *testing.T) {
+	currentYear := time.Now().Year()
+	expected := []Transaction{
+		{ID: 1, Date: time.Date(currentYear, 7, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50},
+		{ID: 2, Date: time.Date(currentYear, 7, 28, 0, 0, 0, 0, time.UTC), Amount: -10_30},
+		{ID: 3, Date: time.Date(currentYear, 8, 2, 0, 0, 0, 0, time.UTC), Amount: -20_46},
+	}
+	lines := []string{"ID,Date,Transaction", "1,7/15,+60.5", "2,7/28,-10.3", "3,8/2,-20.46"}
+
+	testCases := []struct {
+		name       string
+		lineEnding string
+	}{
+		{
+			name:       "it should load files with bare CR line endings",
+			lineEnding: "\r",
+		},
+		{
+			name:       "it should load files with CRLF line endings",
+			lineEnding: "\r\n",
+		},
+		{
+			name:       "it should load files with LF line endings",
+			lineEnding: "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			config := DefaultCSVConfig()
+			config.NormalizeLineEndings = true
+			loader := NewCSVTransactionLoaderWithConfig(config)
+			csvContent := strings.Join(lines, tc.lineEnding) + tc.lineEnding
+			ctx := context.Background()
+
+			// Act
+			result, err := loader.LoadTransactions(ctx, strings.NewReader(csvContent))
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, expected, result)
+		})
+	}
+
+	t.Run("it should report the line of an invalid record with bare CR line endings", func(t *testing.T) {
+		// Arrange
+		config := DefaultCSVConfig()
+		config.NormalizeLineEndings = true
+		loader := NewCSVTransactionLoaderWithConfig(config)
+		csvContent := "ID,Date,Transaction\r1,7/15,+60.5\rabc,7/28,-10.3\r"
+		ctx := context.Background()
+
+		// Act
+		_, err := loader.LoadTransactions(ctx, strings.NewReader(csvContent))
+
+		// Assert
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "record validation error at line 3")
+	})
+
+	t.Run("it should not normalize line endings by default", func(t *testing.T) {
+		// Arrange
+		loader := NewCSVTransactionLoader()
+		csvContent := strings.Join(lines, "\r")
+		ctx := context.Background()
+
+		// Act
+		result, err := loader.LoadTransactions(ctx, strings.NewReader(csvContent))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, result, "the whole file should be read as the header")
+	})
+}
+
+func TestCSVTransactionLoader_AmountFormats(t *testing.T) {
+	currentYear := time.Now().Year()
+
+	testCases := []struct {
+		name             string
+		configure        func(config *CSVTransactionLoaderConfig)
+		csvContent       string
+		expectedAmounts  []Money
+		expectedErrorMsg string
+	}{
+		{
+			name: "it should strip thousands commas with a non-comma delimiter",
+			configure: func(config *CSVTransactionLoaderConfig) {
+				config.Delimiter = ';'
+				config.StripThousandsSeparators = true
+			},
+			csvContent: `ID;Date;Transaction
+1;7/15;1,250.00
+2;7/16;-12,345,678.9
+3;7/17;+60.5`,
+			expectedAmounts: []Money{1250_00, -12345678_90, 60_50},
+		},
+		{
+			name: "it should strip thousands dots with a decimal comma",
+			configure: func(config *CSVTransactionLoaderConfig) {
+				config.Delimiter = ';'
+				config.StripThousandsSeparators = true
+				config.DecimalComma = true
+			},
+			csvContent: `ID;Date;Transaction
+1;7/15;1.250,00
+2;7/16;-12.345.678,9
+3;7/17;+60,5`,
+			expectedAmounts: []Money{1250_00, -12345678_90, 60_50},
+		},
+		{
+			name: "it should parse a decimal comma without thousands separators",
+			configure: func(config *CSVTransactionLoaderConfig) {
+				config.Delimiter = '\t'
+				config.DecimalComma = true
+			},
+			csvContent:      "ID\tDate\tTransaction\n1\t7/15\t-10,30",
+			expectedAmounts: []Money{-10_30},
+		},
+		{
+			name: "it should strip thousands separators of quoted amounts with a comma delimiter",
+			configure: func(config *CSVTransactionLoaderConfig) {
+				config.StripThousandsSeparators = true
+			},
+			csvContent: `ID,Date,Transaction
+1,7/15,"1,250.00"`,
+			expectedAmounts: []Money{1250_00},
+		},
+		{
+			name: "it should reject thousands separators unless stripped",
+			configure: func(config *CSVTransactionLoaderConfig) {
+				config.Delimiter = ';'
+			},
+			csvContent: `ID;Date;Transaction
+1;7/15;1,250.00`,
+			expectedErrorMsg: "invalid amount '1,250.00'",
+		},
+		{
+			name: "it should reject thousands dots with a decimal comma unless stripped",
+			configure: func(config *CSVTransactionLoaderConfig) {
+				config.Delimiter = ';'
+				config.DecimalComma = true
+			},
+			csvContent: `ID;Date;Transaction
+1;7/15;1.250,00`,
+			expectedErrorMsg: "invalid amount '1.250,00'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			config := DefaultCSVConfig()
+			tc.configure(&config)
+			loader := NewCSVTransactionLoaderWithConfig(config)
+			ctx := context.Background()
+
+			// Act
+			result, err := loader.LoadTransactions(ctx, strings.NewReader(tc.csvContent))
+
+			// Assert
+			if tc.expectedErrorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErrorMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result, len(tc.expectedAmounts))
+			for i, amount := range tc.expectedAmounts {
+				assert.Equal(t, amount, result[i].Amount)
+				assert.Equal(t, currentYear, result[i].Date.Year())
+			}
+		})
+	}
+}
+
+func TestCSVTransactionLoader_StripCurrencySymbols(t *testing.T) {
+	testCases := []struct {
+		name             string
+		symbols          []rune
+		amount           string
+		expectedAmount   Money
+		expectedErrorMsg string
+	}{
+		{
+			name:           "it should strip a currency symbol prefix",
+			symbols:        []rune{'$'},
+			amount:         "$60.50",
+			expectedAmount: 60_50,
+		},
+		{
+			name:           "it should preserve the sign before the currency symbol",
+			symbols:        []rune{'$'},
+			amount:         "-$10.30",
+			expectedAmount: -10_30,
+		},
+		{
+			name:           "it should preserve an explicit positive sign",
+			symbols:        []rune{'$'},
+			amount:         "+$0",
+			expectedAmount: 0,
+		},
+		{
+			name:           "it should strip any of the configured symbols",
+			symbols:        []rune{'$', '€'},
+			amount:         "€15.25",
+			expectedAmount: 15_25,
+		},
+		{
+			name:           "it should accept amounts without symbol",
+			symbols:        []rune{'$'},
+			amount:         "-20.46",
+			expectedAmount: -20_46,
+		},
+		{
+			name:             "it should reject currency symbols by default",
+			amount:           "$60.50",
+			expectedErrorMsg: "invalid amount '$60.50'",
+		},
+		{
+			name:             "it should reject symbols that aren't configured",
+			symbols:          []rune{'$'},
+			amount:           "€15.25",
+			expectedErrorMsg: "invalid amount '€15.25'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			config := DefaultCSVConfig()
+			config.StripCurrencySymbols = tc.symbols
+			loader := NewCSVTransactionLoaderWithConfig(config)
+			csvContent := "ID,Date,Transaction\n1,7/15," + tc.amount
+			ctx := context.Background()
+
+			// Act
+			result, err := loader.LoadTransactions(ctx, strings.NewReader(csvContent))
+
+			// Assert
+			if tc.expectedErrorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErrorMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+			assert.Equal(t, tc.expectedAmount, result[0].Amount)
+		})
+	}
+}
+
+// benchmarkRows is the number of rows of the synthetic file used by the benchmarks.
+const benchmarkRows = 100_000
+
+// syntheticCSV builds a file with the given number of rows, alternating credits
+// and debits, and dates with and without year.
+func syntheticCSV(rows int) []byte {
+	var builder strings.Builder
+	builder.WriteString("ID,Date,Transaction\n")
+	for i := 1; i <= rows; i++ {
+		month, day := i%12+1, i%28+1
+		sign := "+"
+		if i%2 == 0 {
+			sign = "-"
+		}
+		if i%3 == 0 {
+			fmt.Fprintf(&builder, "%d,%d/%d/2023,%s%d.%02d\n", i, month, day, sign, i%1000, i%100)
+		} else {
+			fmt.Fprintf(&builder, "%d,%d/%d,%s%d.%02d\n", i, month, day, sign, i%1000, i%100)
+		}
+	}
+	return []byte(builder.String())
+}
+
+func BenchmarkLoadTransactions(b *testing.B) {
+	content := syntheticCSV(benchmarkRows)
+	loader := NewCSVTransactionLoader()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(content)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := loader.LoadTransactions(ctx, bytes.NewReader(content)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCSVTransactionLoader_Allocations(t *testing.T) {
+	loader := NewCSVTransactionLoader()
+
+	t.Run("it should parse a record with a full date without allocating", func(t *testing.T) {
+		// Arrange
+		record := []string{"1", "7/15/2023", "+60.5"}
+
+		// Act
+		allocs := testing.AllocsPerRun(100, func() {
+			if _, err := loader.parseRecord(record, 2); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		// Assert
+		assert.Zero(t, allocs)
+	})
+
+	t.Run("it should allocate once to parse a record with a date without year", func(t *testing.T) {
+		// Arrange
+		record := []string{"1", "7/15", "+60.5"}
+
+		// Act
+		allocs := testing.AllocsPerRun(100, func() {
+			if _, err := loader.parseRecord(record, 2); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		// Assert
+		// At most the completed date is allocated; the year used to be formatted into a builder per record
+		assert.LessOrEqual(t, allocs, 1.0)
+	})
+
+	t.Run("it should parse amounts without allocating", func(t *testing.T) {
+		// Act
+		allocs := testing.AllocsPerRun(100, func() {
+			if _, err := ParseMoney("-1250.3"); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		// Assert
+		assert.Zero(t, allocs, "intermediate strings used to be built for every amount")
+	})
+
+	t.Run("it should allocate at most once per row with full dates", func(t *testing.T) {
+		// Arrange
+		const rows = 1000
+		var builder strings.Builder
+		builder.WriteString("ID,Date,Transaction\n")
+		for i := 1; i <= rows; i++ {
+			fmt.Fprintf(&builder, "%d,7/15/2023,-%d.50\n", i, i)
+		}
+		content := []byte(builder.String())
+		ctx := context.Background()
+
+		// Act
+		allocs := testing.AllocsPerRun(5, func() {
+			if _, err := loader.LoadTransactions(ctx, bytes.NewReader(content)); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		// Assert
+		// Only the fields of each record are allocated; the record slice used to be allocated too
+		assert.LessOrEqual(t, allocs/rows, 1.1)
+	})
+}
+
+func BenchmarkParseDateWithoutYear(b *testing.B) {
+	loader := NewCSVTransactionLoader()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := loader.parseDateOptimized("7/15"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -2,86 +2,515 @@ package transactions
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/google/uuid"
 )
 
+// legacyDateFormat is the layout dates were stored with before DateFormat
+// existed. It always ends in "Z", even for non-UTC times.
+const legacyDateFormat = "2006-01-02T15:04:05Z"
+
+// maxBatchWriteItems is the maximum number of items of a DynamoDB BatchWriteItem request.
+const maxBatchWriteItems = 25
+
+// transactionNamespace is the UUID namespace used to derive deterministic
+// primary keys for transactions.
+var transactionNamespace = uuid.MustParse("4f2b8a6e-93c1-4d7a-b5e0-8c1f2d3a9e47")
+
+// dynamoDBClient abstracts the DynamoDB operations used by the repository
+// so the client can be replaced in tests.
+type dynamoDBClient interface {
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+}
+
 // DynamoTransactionsRepository implements the TransactionsRepository interface
 // using AWS DynamoDB as the persistent storage backend.
+//
+// The table must be keyed by the account and the transaction, so writes spread
+// across partitions and each account's transactions can be queried directly:
+//
+//	partition key (HASH): account_id (S)
+//	sort key (RANGE):     id (S), the deterministic key of the transaction (see transactionKey)
 type DynamoTransactionsRepository struct {
-	client    *dynamodb.Client
+	client    dynamoDBClient
 	tableName string
+	config    DynamoTransactionsRepositoryConfig
+
+	// wait sleeps for the given delay, returning early with the context error
+	wait func(ctx context.Context, delay time.Duration) error
+
+	// now returns the current time, used to compute expiration times
+	now func() time.Time
+}
+
+// DynamoTransactionsRepositoryConfig holds configuration for DynamoDB writes.
+type DynamoTransactionsRepositoryConfig struct {
+	// MaxRetries is the maximum number of retries of unprocessed items (default: 3)
+	MaxRetries int
+
+	// RetryBaseDelay is the delay before the first retry of unprocessed items,
+	// doubled on every following retry (default: 50ms)
+	RetryBaseDelay time.Duration
+
+	// DateFormat is the Go time layout dates are stored with (default: time.RFC3339).
+	// Dates stored with RFC 3339 or the legacy layout are read back as well.
+	DateFormat string
+
+	// Concurrency is the maximum number of batches written at once (default: 1,
+	// i.e. batches are written sequentially)
+	Concurrency int
+
+	// BatchSize is the number of items written (or deleted) per BatchWriteItem
+	// request, e.g. lower for tables with a small provisioned throughput.
+	// Values above the DynamoDB limit of 25 are capped to it (default: 25)
+	BatchSize int
+
+	// OnlyIfNotExists writes items one by one with a conditional PutItem, skipping
+	// transactions that are already stored instead of overwriting them. Useful for
+	// incremental files that overlap previous ones, at the cost of one request per item.
+	OnlyIfNotExists bool
+
+	// TTL is the retention period of stored transactions. When positive, each
+	// item gets an expires_at attribute (Unix epoch seconds) TTL after it's written,
+	// for DynamoDB's Time to Live feature to delete it; the table's TTL must be
+	// enabled on that attribute. Zero disables expiration (default: 0)
+	TTL time.Duration
+}
+
+// DefaultDynamoTransactionsRepositoryConfig returns the default configuration.
+func DefaultDynamoTransactionsRepositoryConfig() DynamoTransactionsRepositoryConfig {
+	return DynamoTransactionsRepositoryConfig{
+		MaxRetries:     3,
+		RetryBaseDelay: 50 * time.Millisecond,
+		DateFormat:     time.RFC3339,
+		Concurrency:    1,
+		BatchSize:      maxBatchWriteItems,
+	}
 }
 
 // NewDynamoTransactionsRepository creates a new instance of DynamoTransactionsRepository.
 func NewDynamoTransactionsRepository(client *dynamodb.Client, tableName string) *DynamoTransactionsRepository {
+	return NewDynamoTransactionsRepositoryWithConfig(client, tableName, DefaultDynamoTransactionsRepositoryConfig())
+}
+
+// NewDynamoTransactionsRepositoryWithConfig creates a repository with custom configuration.
+// Non-positive or empty values fall back to the defaults.
+func NewDynamoTransactionsRepositoryWithConfig(client *dynamodb.Client, tableName string, config DynamoTransactionsRepositoryConfig) *DynamoTransactionsRepository {
+	return newDynamoTransactionsRepository(client, tableName, config)
+}
+
+// newDynamoTransactionsRepository creates a repository on top of any dynamoDBClient.
+func newDynamoTransactionsRepository(client dynamoDBClient, tableName string, config DynamoTransactionsRepositoryConfig) *DynamoTransactionsRepository {
+	defaults := DefaultDynamoTransactionsRepositoryConfig()
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = defaults.MaxRetries
+	}
+	if config.RetryBaseDelay <= 0 {
+		config.RetryBaseDelay = defaults.RetryBaseDelay
+	}
+	if config.DateFormat == "" {
+		config.DateFormat = defaults.DateFormat
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaults.Concurrency
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	config.BatchSize = min(config.BatchSize, maxBatchWriteItems)
+
 	return &DynamoTransactionsRepository{
 		client:    client,
 		tableName: tableName,
+		config:    config,
+		wait:      waitContext,
+		now:       time.Now,
 	}
 }
 
 // DynamoTransaction represents the structure of a transaction as stored in DynamoDB.
+// AccountID and ID make up the composite primary key of the item.
 type DynamoTransaction struct {
-	ID         string  `dynamodbav:"id"`
+	AccountID  string  `dynamodbav:"account_id"` // Partition key
+	ID         string  `dynamodbav:"id"`         // Sort key
 	InternalID uint    `dynamodbav:"internal_id"`
 	Date       string  `dynamodbav:"date"`
-	Amount     float64 `dynamodbav:"amount"`
-	AccountID  string  `dynamodbav:"account_id"`
+	Amount     float64 `dynamodbav:"amount"` // In major units, as previously stored items
+	Currency   string  `dynamodbav:"currency,omitempty"`
+	ExpiresAt  int64   `dynamodbav:"expires_at,omitempty"` // Unix epoch seconds, only with a TTL
+}
+
+// SaveResult holds the item counts of a save operation.
+type SaveResult struct {
+	// Written is the number of items written
+	Written int
+
+	// Retried is the number of item writes retried because DynamoDB left them
+	// unprocessed (an item retried twice counts twice)
+	Retried int
+
+	// Failed is the number of items that were not written, including the items
+	// of batches not dispatched after a failure or cancellation
+	Failed int
+
+	// Skipped is the number of items not written because they were already
+	// stored (only with OnlyIfNotExists)
+	Skipped int
+
+	// Committed is the number of leading transactions whose batches were
+	// fully saved: after a failure, the first Committed transactions are
+	// stored and saving can resume from there (batches saved concurrently
+	// past that boundary may be stored too, and are overwritten on a re-save)
+	Committed int
+}
+
+// add accumulates the counts of another result.
+func (sr *SaveResult) add(other SaveResult) {
+	sr.Written += other.Written
+	sr.Retried += other.Retried
+	sr.Failed += other.Failed
+	sr.Skipped += other.Skipped
 }
 
 // Save persists the given transactions to DynamoDB.
-// It uses batch write operations for efficiency when saving multiple transactions.
+// It uses batch write operations for efficiency when saving multiple transactions
+// (see SaveWithStats).
 func (r *DynamoTransactionsRepository) Save(ctx context.Context, transactions []Transaction) error {
+	_, err := r.SaveWithStats(ctx, transactions)
+	return err
+}
+
+// SaveWithStats persists the given transactions like Save, and reports how many
+// items were written, retried and failed. Batches are dispatched up to
+// Concurrency at once. The first failure stops the dispatch of further batches;
+// the errors of batches already in flight are joined with it, and the result
+// reports the recovery boundary (see SaveResult.Committed).
+func (r *DynamoTransactionsRepository) SaveWithStats(ctx context.Context, transactions []Transaction) (SaveResult, error) {
+	var result SaveResult
 	if len(transactions) == 0 {
-		return nil
+		return result, nil
 	}
 
-	// DynamoDB BatchWriteItem has a limit of 25 items per request
-	const batchSize = 25
+	batchSize := r.config.BatchSize
 
-	for i := 0; i < len(transactions); i += batchSize {
-		end := i + batchSize
-		if end > len(transactions) {
-			end = len(transactions)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		failures = make(map[int]error)
+		saved    = make(map[int]bool)
+		wg       sync.WaitGroup
+	)
+	starts := make(chan int)
+
+	// Start the workers
+	for range r.config.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range starts {
+				end := min(start+batchSize, len(transactions))
+				batchResult, err := r.saveBatch(ctx, transactions[start:end])
+
+				mu.Lock()
+				result.add(batchResult)
+				if err != nil {
+					failures[start] = err
+				} else {
+					saved[start] = true
+				}
+				mu.Unlock()
+
+				// Stop dispatching further batches
+				if err != nil {
+					cancel()
+				}
+			}
+		}()
+	}
+
+	// Dispatch the batches until done or the context is cancelled
+	start := 0
+dispatch:
+	for ; start < len(transactions); start += batchSize {
+		// Check first, as select picks randomly when both cases are ready
+		if ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case starts <- start:
 		}
+	}
+	close(starts)
+	wg.Wait()
+
+	// The batches saved up to the first one that wasn't are committed
+	for committed := 0; committed < len(transactions) && saved[committed]; committed += batchSize {
+		result.Committed = min(committed+batchSize, len(transactions))
+	}
+
+	// Transactions never dispatched were not written either
+	var dispatchErr error
+	if start < len(transactions) {
+		result.Failed += len(transactions) - start
+		dispatchErr = fmt.Errorf("stopped saving at index %d: %w", start, ctx.Err())
+	}
 
-		batch := transactions[i:end]
-		if err := r.saveBatch(ctx, batch); err != nil {
-			return fmt.Errorf("failed to save batch starting at index %d: %w", i, err)
+	// Batch failures explain why the dispatch was stopped
+	if len(failures) > 0 {
+		errs := make([]error, 0, len(failures))
+		for _, start := range slices.Sorted(maps.Keys(failures)) {
+			errs = append(errs, fmt.Errorf("failed to save batch starting at index %d: %w", start, failures[start]))
 		}
+		return result, errors.Join(errs...)
+	}
+
+	return result, dispatchErr
+}
+
+// GetByAccount returns the transactions stored for the given account, sorted by
+// date. It queries the account's partition, following pagination until all
+// pages are read.
+func (r *DynamoTransactionsRepository) GetByAccount(ctx context.Context, accountID string) ([]Transaction, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("account_id = :account_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":account_id": &types.AttributeValueMemberS{Value: accountID},
+		},
 	}
 
+	var transactions []Transaction
+	for page := 1; ; page++ {
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query transactions (page %d): %w", page, err)
+		}
+
+		var dynamoTxs []DynamoTransaction
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &dynamoTxs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal transactions (page %d): %w", page, err)
+		}
+
+		for _, dynamoTx := range dynamoTxs {
+			transaction, err := r.toTransaction(dynamoTx)
+			if err != nil {
+				return nil, err
+			}
+			transactions = append(transactions, transaction)
+		}
+
+		// The last page has no LastEvaluatedKey
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	// Items come back sorted by their key, which isn't chronological
+	sort.SliceStable(transactions, func(i, j int) bool {
+		if !transactions[i].Date.Equal(transactions[j].Date) {
+			return transactions[i].Date.Before(transactions[j].Date)
+		}
+		return transactions[i].ID < transactions[j].ID
+	})
+
+	return transactions, nil
+}
+
+// Ping checks the connectivity with DynamoDB (and the permissions on the
+// table) by describing the table, which must be active to be written to.
+// It doesn't read or write any item.
+func (r *DynamoTransactionsRepository) Ping(ctx context.Context) error {
+	output, err := r.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(r.tableName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe table %s: %w", r.tableName, err)
+	}
+
+	if output.Table != nil {
+		switch status := output.Table.TableStatus; status {
+		case types.TableStatusActive, types.TableStatusUpdating:
+		default:
+			return fmt.Errorf("table %s is not active (status %s)", r.tableName, status)
+		}
+	}
 	return nil
 }
 
-// saveBatch saves a batch of transactions using DynamoDB BatchWriteItem.
-func (r *DynamoTransactionsRepository) saveBatch(ctx context.Context, transactions []Transaction) error {
-	writeRequests := make([]types.WriteRequest, 0, len(transactions))
+// DeleteByAccount deletes every transaction stored for the given account (e.g.
+// to honor a data deletion request) and returns how many were deleted. It
+// queries the account's partition page by page, deleting the items of each page
+// in batches and retrying unprocessed items like Save does. On failure, the
+// number of items deleted so far is returned along with the error.
+func (r *DynamoTransactionsRepository) DeleteByAccount(ctx context.Context, accountID string) (deleted int, err error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("#account_id = :account_id"),
+		// Only the keys are needed to delete the items
+		ProjectionExpression: aws.String("#account_id, #id"),
+		ExpressionAttributeNames: map[string]string{
+			"#account_id": "account_id",
+			"#id":         "id",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":account_id": &types.AttributeValueMemberS{Value: accountID},
+		},
+	}
+
+	for page := 1; ; page++ {
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to query transactions (page %d): %w", page, err)
+		}
+
+		for start := 0; start < len(result.Items); start += r.config.BatchSize {
+			end := min(start+r.config.BatchSize, len(result.Items))
+			batchResult, err := r.deleteBatch(ctx, result.Items[start:end])
+			deleted += batchResult.Written
+			if err != nil {
+				return deleted, fmt.Errorf("failed to delete transactions (page %d): %w", page, err)
+			}
+		}
+
+		// The last page has no LastEvaluatedKey
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	return deleted, nil
+}
+
+// deleteBatch deletes a batch of items using DynamoDB BatchWriteItem, retrying
+// unprocessed items. Deleted items are counted as Written.
+func (r *DynamoTransactionsRepository) deleteBatch(ctx context.Context, items []map[string]types.AttributeValue) (SaveResult, error) {
+	writeRequests := make([]types.WriteRequest, 0, len(items))
+	for _, item := range items {
+		writeRequests = append(writeRequests, types.WriteRequest{
+			DeleteRequest: &types.DeleteRequest{
+				Key: map[string]types.AttributeValue{
+					"account_id": item["account_id"],
+					"id":         item["id"],
+				},
+			},
+		})
+	}
+
+	input := &dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{
+			r.tableName: writeRequests,
+		},
+	}
+
+	output, err := r.client.BatchWriteItem(ctx, input)
+	if err != nil {
+		return SaveResult{Failed: len(writeRequests)}, fmt.Errorf("failed to execute batch delete: %w", err)
+	}
+
+	unprocessed := countWriteRequests(output.UnprocessedItems)
+	result := SaveResult{Written: len(writeRequests) - unprocessed}
+
+	// Handle unprocessed items (DynamoDB may not process all items in one request)
+	if unprocessed > 0 {
+		err = r.handleUnprocessedItems(ctx, output.UnprocessedItems, &result)
+	}
+
+	return result, err
+}
+
+// toTransaction converts a stored DynamoTransaction back to a Transaction.
+func (r *DynamoTransactionsRepository) toTransaction(dt DynamoTransaction) (Transaction, error) {
+	date, err := r.parseDate(dt.Date)
+	if err != nil {
+		return Transaction{}, fmt.Errorf("failed to parse date of transaction %s: %w", dt.ID, err)
+	}
+
+	return Transaction{
+		ID:        dt.InternalID,
+		Date:      date,
+		Amount:    MoneyFromFloat(dt.Amount),
+		Currency:  dt.Currency,
+		AccountID: dt.AccountID,
+	}, nil
+}
+
+// formatDate formats a transaction date with the configured layout.
+func (r *DynamoTransactionsRepository) formatDate(date time.Time) string {
+	return date.Format(r.config.DateFormat)
+}
+
+// parseDate parses a stored date with the configured layout, falling back to
+// RFC 3339 and the legacy layout so previously stored items still read.
+func (r *DynamoTransactionsRepository) parseDate(value string) (time.Time, error) {
+	date, err := time.Parse(r.config.DateFormat, value)
+	if err == nil {
+		return date, nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, legacyDateFormat} {
+		if date, fallbackErr := time.Parse(layout, value); fallbackErr == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, err
+}
+
+// saveBatch saves a batch of transactions using DynamoDB BatchWriteItem, or
+// conditional PutItem requests with OnlyIfNotExists. Identical transactions of
+// the batch are written, and counted, once.
+func (r *DynamoTransactionsRepository) saveBatch(ctx context.Context, transactions []Transaction) (SaveResult, error) {
+	items := make([]map[string]types.AttributeValue, 0, len(transactions))
+	seenKeys := make(map[string]bool, len(transactions))
 
 	for _, transaction := range transactions {
-		// Generate UUID v4 for primary key
-		primaryID := uuid.New().String()
+		// Derive the primary key from the transaction, so re-saves overwrite
+		primaryID := transactionKey(transaction)
 
-		// Convert Transaction to DynamoTransaction
-		dynamoTx := DynamoTransaction{
-			ID:         primaryID,      // UUID v4 as primary key
-			InternalID: transaction.ID, // Original numeric ID
-			Date:       transaction.Date.Format("2006-01-02T15:04:05Z"),
-			Amount:     transaction.Amount,
-			AccountID:  transaction.AccountID,
+		// BatchWriteItem rejects duplicate keys, and identical transactions
+		// would overwrite each other anyway
+		if seenKeys[primaryID] {
+			continue
 		}
+		seenKeys[primaryID] = true
 
-		// Marshal to DynamoDB attribute values
-		item, err := attributevalue.MarshalMap(dynamoTx)
+		item, err := r.marshalTransaction(transaction, primaryID)
 		if err != nil {
-			return fmt.Errorf("failed to marshal transaction %d: %w", transaction.ID, err)
+			return SaveResult{Failed: len(transactions)}, fmt.Errorf("failed to marshal transaction %d: %w", transaction.ID, err)
 		}
 
+		items = append(items, item)
+	}
+
+	if r.config.OnlyIfNotExists {
+		return r.putItemsIfNotExist(ctx, items)
+	}
+
+	writeRequests := make([]types.WriteRequest, 0, len(items))
+	for _, item := range items {
 		writeRequest := types.WriteRequest{
 			PutRequest: &types.PutRequest{
 				Item: item,
@@ -98,42 +527,147 @@ func (r *DynamoTransactionsRepository) saveBatch(ctx context.Context, transactio
 		},
 	}
 
-	result, err := r.client.BatchWriteItem(ctx, input)
+	output, err := r.client.BatchWriteItem(ctx, input)
 	if err != nil {
-		return fmt.Errorf("failed to execute batch write: %w", err)
+		return SaveResult{Failed: len(writeRequests)}, fmt.Errorf("failed to execute batch write: %w", err)
 	}
 
+	unprocessed := countWriteRequests(output.UnprocessedItems)
+	result := SaveResult{Written: len(writeRequests) - unprocessed}
+
 	// Handle unprocessed items (DynamoDB may not process all items in one request)
-	if len(result.UnprocessedItems) > 0 {
-		return r.handleUnprocessedItems(ctx, result.UnprocessedItems)
+	if unprocessed > 0 {
+		err = r.handleUnprocessedItems(ctx, output.UnprocessedItems, &result)
 	}
 
-	return nil
+	return result, err
+}
+
+// marshalTransaction converts a transaction into the DynamoDB item stored for
+// it, keyed by its account and the given deterministic transaction key.
+func (r *DynamoTransactionsRepository) marshalTransaction(transaction Transaction, key string) (map[string]types.AttributeValue, error) {
+	dynamoTx := DynamoTransaction{
+		AccountID:  transaction.AccountID, // Partition key
+		ID:         key,                   // Deterministic UUID v5 as sort key
+		InternalID: transaction.ID,        // Original numeric ID
+		Date:       r.formatDate(transaction.Date),
+		Amount:     transaction.AmountFloat(),
+		Currency:   transaction.Currency,
+	}
+
+	// Let DynamoDB delete the item once the retention period is over
+	if r.config.TTL > 0 {
+		dynamoTx.ExpiresAt = r.now().Add(r.config.TTL).Unix()
+	}
+
+	return attributevalue.MarshalMap(dynamoTx)
+}
+
+// putItemsIfNotExist writes the items one by one, only when no item with the
+// same key is stored yet. Items that already exist are counted as skipped.
+func (r *DynamoTransactionsRepository) putItemsIfNotExist(ctx context.Context, items []map[string]types.AttributeValue) (SaveResult, error) {
+	var result SaveResult
+	for i, item := range items {
+		input := &dynamodb.PutItemInput{
+			TableName:           aws.String(r.tableName),
+			Item:                item,
+			ConditionExpression: aws.String("attribute_not_exists(id)"),
+		}
+
+		_, err := r.client.PutItem(ctx, input)
+
+		var conditionErr *types.ConditionalCheckFailedException
+		switch {
+		case err == nil:
+			result.Written++
+		case errors.As(err, &conditionErr):
+			result.Skipped++
+		default:
+			result.Failed += len(items) - i
+			return result, fmt.Errorf("failed to put item: %w", err)
+		}
+	}
+
+	return result, nil
 }
 
-// handleUnprocessedItems retries unprocessed items from a batch write operation.
-func (r *DynamoTransactionsRepository) handleUnprocessedItems(ctx context.Context, unprocessedItems map[string][]types.WriteRequest) error {
-	maxRetries := 3
-	retryCount := 0
+// countWriteRequests counts the write requests of all tables.
+func countWriteRequests(requestItems map[string][]types.WriteRequest) int {
+	count := 0
+	for _, requests := range requestItems {
+		count += len(requests)
+	}
+	return count
+}
+
+// transactionKey derives a deterministic key (UUID v5), used as the sort key of
+// the item, from the transaction's account, ID, date and amount, so saving the
+// same transaction twice (e.g. when a Lambda invocation is retried) overwrites the first item
+// instead of duplicating it.
+func transactionKey(transaction Transaction) string {
+	name := fmt.Sprintf("%s|%d|%s|%s",
+		transaction.AccountID,
+		transaction.ID,
+		transaction.Date.UTC().Format(time.RFC3339Nano),
+		// Formatted in major units, so keys of previously stored items are stable
+		strconv.FormatFloat(transaction.AmountFloat(), 'f', -1, 64),
+	)
+	return uuid.NewSHA1(transactionNamespace, []byte(name)).String()
+}
 
-	for len(unprocessedItems) > 0 && retryCount < maxRetries {
-		retryCount++
+// handleUnprocessedItems retries unprocessed items from a batch write operation,
+// backing off exponentially with jitter between attempts so throttled tables
+// get time to recover. The context is respected between attempts. The outcome
+// of the retries is accumulated into result.
+func (r *DynamoTransactionsRepository) handleUnprocessedItems(ctx context.Context, unprocessedItems map[string][]types.WriteRequest, result *SaveResult) error {
+	for retryCount := 1; len(unprocessedItems) > 0 && retryCount <= r.config.MaxRetries; retryCount++ {
+		pending := countWriteRequests(unprocessedItems)
+
+		if err := r.wait(ctx, r.retryDelay(retryCount)); err != nil {
+			result.Failed += pending
+			return fmt.Errorf("stopped retrying unprocessed items (attempt %d): %w", retryCount, err)
+		}
 
 		input := &dynamodb.BatchWriteItemInput{
 			RequestItems: unprocessedItems,
 		}
 
-		result, err := r.client.BatchWriteItem(ctx, input)
+		result.Retried += pending
+		output, err := r.client.BatchWriteItem(ctx, input)
 		if err != nil {
+			result.Failed += pending
 			return fmt.Errorf("failed to retry unprocessed items (attempt %d): %w", retryCount, err)
 		}
 
-		unprocessedItems = result.UnprocessedItems
+		unprocessedItems = output.UnprocessedItems
+		result.Written += pending - countWriteRequests(unprocessedItems)
 	}
 
-	if len(unprocessedItems) > 0 {
-		return fmt.Errorf("failed to process all items after %d retries, %d items remain unprocessed", maxRetries, len(unprocessedItems[r.tableName]))
+	if remaining := countWriteRequests(unprocessedItems); remaining > 0 {
+		result.Failed += remaining
+		return fmt.Errorf("failed to process all items after %d retries, %d items remain unprocessed", r.config.MaxRetries, remaining)
 	}
 
 	return nil
 }
+
+// retryDelay returns the delay before the given retry. The delay doubles on
+// every retry, and half of it is randomized (jitter) to spread retries out.
+func (r *DynamoTransactionsRepository) retryDelay(retry int) time.Duration {
+	delay := r.config.RetryBaseDelay << (retry - 1)
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// waitContext sleeps for the given delay, returning early with the context
+// error when the context is done.
+func waitContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,1045 @@
+package transactions
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDynamoDBClient is a test DynamoDB client that records the written items
+// (both unmarshaled and as raw attribute values) and the keys of deleted items.
+// The first unprocessedCalls calls leave their first unprocessedItems items
+// (all of them when 0) unprocessed, queries return the given pages in order, and
+// conditional puts fail for the existing keys.
+type fakeDynamoDBClient struct {
+	mu               sync.Mutex
+	items            []DynamoTransaction
+	rawItems         []map[string]types.AttributeValue
+	deletedKeys      []map[string]types.AttributeValue
+	calls            int
+	unprocessedCalls int
+	unprocessedItems int
+	pages            [][]DynamoTransaction
+	queries          []*dynamodb.QueryInput
+	queryErr         error
+	pageErrs         map[int]error
+	writeErrs        map[int]error
+	onBatchWrite     func(call int)
+	existing         map[string]bool
+	putErr           error
+	tableStatus      types.TableStatus
+	describeErr      error
+}
+
+func (c *fakeDynamoDBClient) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	if c.describeErr != nil {
+		return nil, c.describeErr
+	}
+	status := c.tableStatus
+	if status == "" {
+		status = types.TableStatusActive
+	}
+	return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableName: params.TableName, TableStatus: status}}, nil
+}
+
+func (c *fakeDynamoDBClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	if c.putErr != nil {
+		return nil, c.putErr
+	}
+
+	var item DynamoTransaction
+	if err := attributevalue.UnmarshalMap(params.Item, &item); err != nil {
+		return nil, err
+	}
+	if params.ConditionExpression != nil && *params.ConditionExpression == "attribute_not_exists(id)" && c.existing[item.ID] {
+		return nil, &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+	}
+	c.items = append(c.items, item)
+	c.rawItems = append(c.rawItems, params.Item)
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (c *fakeDynamoDBClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Copy the input, the repository reuses it between pages
+	query := *params
+	c.queries = append(c.queries, &query)
+	if c.queryErr != nil {
+		return nil, c.queryErr
+	}
+
+	// Pages are keyed by their index
+	page := 0
+	if params.ExclusiveStartKey != nil {
+		key := params.ExclusiveStartKey["page"].(*types.AttributeValueMemberN).Value
+		page, _ = strconv.Atoi(key)
+	}
+	if err := c.pageErrs[page]; err != nil {
+		return nil, err
+	}
+
+	items := make([]map[string]types.AttributeValue, 0, len(c.pages[page]))
+	for _, dynamoTx := range c.pages[page] {
+		item, err := attributevalue.MarshalMap(dynamoTx)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	output := &dynamodb.QueryOutput{Items: items}
+	if page+1 < len(c.pages) {
+		output.LastEvaluatedKey = map[string]types.AttributeValue{
+			"page": &types.AttributeValueMemberN{Value: strconv.Itoa(page + 1)},
+		}
+	}
+	return output, nil
+}
+
+func (c *fakeDynamoDBClient) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	if c.onBatchWrite != nil {
+		c.onBatchWrite(c.calls)
+	}
+	if err := c.writeErrs[c.calls]; err != nil {
+		return nil, err
+	}
+	unprocessed := make(map[string][]types.WriteRequest)
+	for table, requests := range params.RequestItems {
+		if c.calls <= c.unprocessedCalls {
+			count := len(requests)
+			if c.unprocessedItems > 0 {
+				count = min(c.unprocessedItems, count)
+			}
+			unprocessed[table], requests = requests[:count], requests[count:]
+		}
+		for _, request := range requests {
+			if request.DeleteRequest != nil {
+				c.deletedKeys = append(c.deletedKeys, request.DeleteRequest.Key)
+				continue
+			}
+
+			var item DynamoTransaction
+			if err := attributevalue.UnmarshalMap(request.PutRequest.Item, &item); err != nil {
+				return nil, err
+			}
+			c.items = append(c.items, item)
+			c.rawItems = append(c.rawItems, request.PutRequest.Item)
+		}
+	}
+	return &dynamodb.BatchWriteItemOutput{UnprocessedItems: unprocessed}, nil
+}
+
+// newTestRepository creates a DynamoTransactionsRepository backed by the given
+// fake client that records the retry delays instead of sleeping.
+func newTestRepository(client dynamoDBClient) (*DynamoTransactionsRepository, *[]time.Duration) {
+	repository := newDynamoTransactionsRepository(client, "transactions", DefaultDynamoTransactionsRepositoryConfig())
+	delays := &[]time.Duration{}
+	repository.wait = func(ctx context.Context, delay time.Duration) error {
+		*delays = append(*delays, delay)
+		return ctx.Err()
+	}
+	return repository, delays
+}
+
+func TestDynamoTransactionsRepository_Save_DeterministicKey(t *testing.T) {
+	txns := []Transaction{
+		{ID: 0, Date: time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50, AccountID: "acc-1"},
+		{ID: 1, Date: time.Date(2024, time.July, 28, 0, 0, 0, 0, time.UTC), Amount: -10_30, AccountID: "acc-1"},
+	}
+
+	t.Run("it should yield the same ID when saving the same transactions twice", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{}
+		repository, _ := newTestRepository(client)
+
+		// Act
+		require.NoError(t, repository.Save(context.Background(), txns))
+		require.NoError(t, repository.Save(context.Background(), txns))
+
+		// Assert
+		require.Len(t, client.items, 4)
+		assert.Equal(t, client.items[0].ID, client.items[2].ID)
+		assert.Equal(t, client.items[1].ID, client.items[3].ID)
+		assert.NotEqual(t, client.items[0].ID, client.items[1].ID)
+	})
+
+	t.Run("it should write identical transactions of a batch once", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{}
+		repository, _ := newTestRepository(client)
+
+		// Act
+		err := repository.Save(context.Background(), []Transaction{txns[0], txns[1], txns[0]})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Len(t, client.items, 2)
+	})
+
+	t.Run("it should key the items by account and transaction", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{}
+		repository, _ := newTestRepository(client)
+
+		// Act
+		require.NoError(t, repository.Save(context.Background(), txns))
+
+		// Assert
+		require.Len(t, client.rawItems, 2)
+		for i, item := range client.rawItems {
+			assert.Equal(t, &types.AttributeValueMemberS{Value: "acc-1"}, item["account_id"], "the partition key should be the account")
+			assert.Equal(t, &types.AttributeValueMemberS{Value: transactionKey(txns[i])}, item["id"], "the sort key should be the transaction key")
+		}
+	})
+
+	t.Run("it should key the conditionally put items by account and transaction", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{}
+		config := DefaultDynamoTransactionsRepositoryConfig()
+		config.OnlyIfNotExists = true
+		repository := newDynamoTransactionsRepository(client, "transactions", config)
+
+		// Act
+		require.NoError(t, repository.Save(context.Background(), txns[:1]))
+
+		// Assert
+		require.Len(t, client.rawItems, 1)
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "acc-1"}, client.rawItems[0]["account_id"])
+		assert.Equal(t, &types.AttributeValueMemberS{Value: transactionKey(txns[0])}, client.rawItems[0]["id"])
+	})
+
+	t.Run("it should keep the original ID as the internal ID", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{}
+		repository, _ := newTestRepository(client)
+
+		// Act
+		require.NoError(t, repository.Save(context.Background(), txns))
+
+		// Assert
+		require.Len(t, client.items, 2)
+		assert.Equal(t, uint(0), client.items[0].InternalID)
+		assert.Equal(t, uint(1), client.items[1].InternalID)
+	})
+}
+
+// newTestTransactions creates count transactions of the same account.
+func newTestTransactions(count int) []Transaction {
+	txns := make([]Transaction, count)
+	for i := range txns {
+		txns[i] = Transaction{ID: uint(i), Date: time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 1_00, AccountID: "acc-1"}
+	}
+	return txns
+}
+
+func TestDynamoTransactionsRepository_Save_Concurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+	}{
+		{name: "it should write all items sequentially", concurrency: 1},
+		{name: "it should write all items concurrently", concurrency: 4},
+		{name: "it should write all items with more workers than batches", concurrency: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			client := &fakeDynamoDBClient{}
+			repository, _ := newTestRepository(client)
+			repository.config.Concurrency = tt.concurrency
+			txns := newTestTransactions(260)
+
+			// Act
+			err := repository.Save(context.Background(), txns)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, 11, client.calls, "should write 25-item batches")
+			require.Len(t, client.items, len(txns))
+			written := make(map[uint]bool, len(client.items))
+			for _, item := range client.items {
+				written[item.InternalID] = true
+			}
+			assert.Len(t, written, len(txns), "should write every transaction once")
+		})
+	}
+
+	t.Run("it should stop dispatching when the context is cancelled", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		client := &fakeDynamoDBClient{onBatchWrite: func(call int) {
+			if call == 3 {
+				cancel()
+			}
+		}}
+		repository, _ := newTestRepository(client)
+		repository.config.Concurrency = 2
+
+		// Act
+		err := repository.Save(ctx, newTestTransactions(1000))
+
+		// Assert
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, client.calls, 10, "should not dispatch the remaining batches")
+	})
+
+	t.Run("it should stop dispatching and report failed batches", func(t *testing.T) {
+		// Arrange
+		writeErr := errors.New("validation error")
+		client := &fakeDynamoDBClient{writeErrs: map[int]error{2: writeErr}}
+		repository, _ := newTestRepository(client)
+
+		// Act
+		err := repository.Save(context.Background(), newTestTransactions(1000))
+
+		// Assert
+		require.Error(t, err)
+		assert.ErrorIs(t, err, writeErr)
+		assert.Contains(t, err.Error(), "failed to save batch starting at index 25")
+		assert.Equal(t, 2, client.calls, "should not dispatch batches after a failure")
+	})
+}
+
+func TestDynamoTransactionsRepository_Save_BatchSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		batchSize     int
+		expectedCalls int
+	}{
+		{name: "it should write the items in batches of the configured size", batchSize: 10, expectedCalls: 3},
+		{name: "it should write up to 25 items per batch by default", batchSize: 0, expectedCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			client := &fakeDynamoDBClient{}
+			repository := newDynamoTransactionsRepository(client, "transactions", DynamoTransactionsRepositoryConfig{BatchSize: tt.batchSize})
+
+			// Act
+			result, err := repository.SaveWithStats(context.Background(), newTestTransactions(23))
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCalls, client.calls)
+			assert.Len(t, client.items, 23)
+			assert.Equal(t, SaveResult{Written: 23, Committed: 23}, result)
+		})
+	}
+}
+
+func TestDynamoTransactionsRepository_Save_UnprocessedItems(t *testing.T) {
+	txns := []Transaction{
+		{ID: 0, Date: time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50, AccountID: "acc-1"},
+	}
+
+	t.Run("it should back off and retry unprocessed items", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{unprocessedCalls: 2}
+		repository, delays := newTestRepository(client)
+
+		// Act
+		err := repository.Save(context.Background(), txns)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 3, client.calls)
+		assert.Len(t, client.items, 1)
+		require.Len(t, *delays, 2, "should back off before each retry")
+		assert.LessOrEqual(t, (*delays)[0], repository.config.RetryBaseDelay)
+		assert.GreaterOrEqual(t, (*delays)[1], repository.config.RetryBaseDelay)
+	})
+
+	t.Run("it should give up after the maximum retries", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{unprocessedCalls: 10}
+		repository, delays := newTestRepository(client)
+		repository.config.MaxRetries = 2
+
+		// Act
+		err := repository.Save(context.Background(), txns)
+
+		// Assert
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "after 2 retries, 1 items remain unprocessed")
+		assert.Equal(t, 3, client.calls)
+		assert.Len(t, *delays, 2)
+	})
+
+	t.Run("it should stop retrying when the context is cancelled", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{unprocessedCalls: 10}
+		repository, _ := newTestRepository(client)
+		repository.wait = waitContext
+		repository.config.RetryBaseDelay = time.Hour
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// Act
+		err := repository.Save(ctx, txns)
+
+		// Assert
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, client.calls)
+	})
+}
+
+func TestDynamoTransactionsRepository_SaveWithStats(t *testing.T) {
+	tests := []struct {
+		name           string
+		client         *fakeDynamoDBClient
+		maxRetries     int
+		count          int
+		expectedResult SaveResult
+		expectError    bool
+	}{
+		{
+			name:           "it should count written items",
+			client:         &fakeDynamoDBClient{},
+			count:          60,
+			expectedResult: SaveResult{Written: 60, Committed: 60},
+		},
+		{
+			name:           "it should count retried items",
+			client:         &fakeDynamoDBClient{unprocessedCalls: 1, unprocessedItems: 5},
+			count:          30,
+			expectedResult: SaveResult{Written: 30, Retried: 5, Committed: 30},
+		},
+		{
+			name:           "it should count items retried more than once",
+			client:         &fakeDynamoDBClient{unprocessedCalls: 3, unprocessedItems: 4},
+			count:          10,
+			expectedResult: SaveResult{Written: 10, Retried: 12, Committed: 10},
+		},
+		{
+			name:           "it should count items left unprocessed as failed",
+			client:         &fakeDynamoDBClient{unprocessedCalls: 10, unprocessedItems: 3},
+			maxRetries:     2,
+			count:          10,
+			expectedResult: SaveResult{Written: 7, Retried: 6, Failed: 3},
+			expectError:    true,
+		},
+		{
+			name:           "it should count failed and undispatched batches as failed",
+			client:         &fakeDynamoDBClient{writeErrs: map[int]error{2: errors.New("validation error")}},
+			count:          100,
+			expectedResult: SaveResult{Written: 25, Failed: 75, Committed: 25},
+			expectError:    true,
+		},
+		{
+			name:           "it should report the transactions committed before the failed batch",
+			client:         &fakeDynamoDBClient{writeErrs: map[int]error{3: errors.New("validation error")}},
+			count:          60,
+			expectedResult: SaveResult{Written: 50, Failed: 10, Committed: 50},
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			repository, _ := newTestRepository(tt.client)
+			if tt.maxRetries > 0 {
+				repository.config.MaxRetries = tt.maxRetries
+			}
+
+			// Act
+			result, err := repository.SaveWithStats(context.Background(), newTestTransactions(tt.count))
+
+			// Assert
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedResult, result)
+			assert.Len(t, tt.client.items, tt.expectedResult.Written, "should report the items actually written")
+		})
+	}
+}
+
+func TestDynamoTransactionsRepository_SaveWithStats_OnlyIfNotExists(t *testing.T) {
+	txns := []Transaction{
+		{ID: 0, Date: time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50, AccountID: "acc-1"},
+		{ID: 1, Date: time.Date(2024, time.July, 28, 0, 0, 0, 0, time.UTC), Amount: -10_30, AccountID: "acc-1"},
+	}
+
+	t.Run("it should skip existing transactions and write new ones", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{existing: map[string]bool{transactionKey(txns[0]): true}}
+		repository, _ := newTestRepository(client)
+		repository.config.OnlyIfNotExists = true
+
+		// Act
+		result, err := repository.SaveWithStats(context.Background(), txns)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, SaveResult{Written: 1, Skipped: 1, Committed: 2}, result)
+		require.Len(t, client.items, 1)
+		assert.Equal(t, uint(1), client.items[0].InternalID)
+		assert.Equal(t, 2, client.calls, "should put items one by one")
+	})
+
+	t.Run("it should fail on other errors", func(t *testing.T) {
+		// Arrange
+		putErr := errors.New("throttled")
+		client := &fakeDynamoDBClient{putErr: putErr}
+		repository, _ := newTestRepository(client)
+		repository.config.OnlyIfNotExists = true
+
+		// Act
+		result, err := repository.SaveWithStats(context.Background(), txns)
+
+		// Assert
+		assert.ErrorIs(t, err, putErr)
+		assert.Equal(t, SaveResult{Failed: 2}, result)
+	})
+}
+
+func TestDynamoTransactionsRepository_Save_TTL(t *testing.T) {
+	now := time.Date(2024, time.July, 15, 12, 0, 0, 0, time.UTC)
+	txns := []Transaction{
+		{ID: 0, Date: time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50, AccountID: "acc-1"},
+		{ID: 1, Date: time.Date(2024, time.July, 28, 0, 0, 0, 0, time.UTC), Amount: -10_30, AccountID: "acc-1"},
+	}
+
+	t.Run("it should set the expiration time when a TTL is configured", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{}
+		config := DefaultDynamoTransactionsRepositoryConfig()
+		config.TTL = 90 * 24 * time.Hour
+		repository := newDynamoTransactionsRepository(client, "transactions", config)
+		repository.now = func() time.Time { return now }
+
+		// Act
+		require.NoError(t, repository.Save(context.Background(), txns))
+
+		// Assert
+		expected := &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(90*24*time.Hour).Unix(), 10)}
+		require.Len(t, client.rawItems, 2)
+		for _, item := range client.rawItems {
+			assert.Equal(t, expected, item["expires_at"])
+		}
+	})
+
+	t.Run("it should set the expiration time of conditionally put items", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{}
+		config := DefaultDynamoTransactionsRepositoryConfig()
+		config.TTL = time.Hour
+		config.OnlyIfNotExists = true
+		repository := newDynamoTransactionsRepository(client, "transactions", config)
+		repository.now = func() time.Time { return now }
+
+		// Act
+		require.NoError(t, repository.Save(context.Background(), txns[:1]))
+
+		// Assert
+		require.Len(t, client.items, 1)
+		assert.Equal(t, now.Add(time.Hour).Unix(), client.items[0].ExpiresAt)
+	})
+
+	t.Run("it should not set the expiration time without a TTL", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{}
+		repository, _ := newTestRepository(client)
+
+		// Act
+		require.NoError(t, repository.Save(context.Background(), txns))
+
+		// Assert
+		require.Len(t, client.rawItems, 2)
+		for _, item := range client.rawItems {
+			assert.NotContains(t, item, "expires_at")
+		}
+	})
+}
+
+func TestDynamoTransactionsRepository_retryDelay(t *testing.T) {
+	// Arrange
+	repository, _ := newTestRepository(&fakeDynamoDBClient{})
+
+	for retry := 1; retry <= 4; retry++ {
+		// Act
+		delay := repository.retryDelay(retry)
+
+		// Assert
+		base := repository.config.RetryBaseDelay << (retry - 1)
+		assert.GreaterOrEqual(t, delay, base/2, "delay should be at least half of the exponential delay")
+		assert.LessOrEqual(t, delay, base, "delay should not exceed the exponential delay")
+	}
+}
+
+func TestNewDynamoTransactionsRepositoryWithConfig(t *testing.T) {
+	t.Run("it should fall back to the defaults for non-positive values", func(t *testing.T) {
+		// Act
+		repository := NewDynamoTransactionsRepositoryWithConfig(nil, "transactions", DynamoTransactionsRepositoryConfig{})
+
+		// Assert
+		assert.Equal(t, DefaultDynamoTransactionsRepositoryConfig(), repository.config)
+	})
+
+	t.Run("it should keep custom values", func(t *testing.T) {
+		// Arrange
+		config := DynamoTransactionsRepositoryConfig{MaxRetries: 5, RetryBaseDelay: time.Second, DateFormat: time.RFC3339Nano, Concurrency: 4, BatchSize: 10}
+
+		// Act
+		repository := NewDynamoTransactionsRepositoryWithConfig(nil, "transactions", config)
+
+		// Assert
+		assert.Equal(t, config, repository.config)
+	})
+
+	t.Run("it should cap the batch size to the DynamoDB limit", func(t *testing.T) {
+		// Act
+		repository := NewDynamoTransactionsRepositoryWithConfig(nil, "transactions", DynamoTransactionsRepositoryConfig{BatchSize: 100})
+
+		// Assert
+		assert.Equal(t, 25, repository.config.BatchSize)
+	})
+}
+
+func TestDynamoTransactionsRepository_GetByAccount(t *testing.T) {
+	t.Run("it should read every page sorted by date", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{pages: [][]DynamoTransaction{
+			{
+				{ID: "a", InternalID: 2, Date: "2024-08-02T00:00:00Z", Amount: -20.46, AccountID: "acc-1"},
+				{ID: "b", InternalID: 0, Date: "2024-07-15T00:00:00Z", Amount: 60.5, AccountID: "acc-1"},
+			},
+			{
+				{ID: "c", InternalID: 1, Date: "2024-07-28T00:00:00Z", Amount: -10.3, AccountID: "acc-1"},
+			},
+			{
+				{ID: "d", InternalID: 3, Date: "2024-08-13T00:00:00Z", Amount: 10, AccountID: "acc-1"},
+			},
+		}}
+		repository, _ := newTestRepository(client)
+
+		// Act
+		txns, err := repository.GetByAccount(context.Background(), "acc-1")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []Transaction{
+			{ID: 0, Date: time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50, AccountID: "acc-1"},
+			{ID: 1, Date: time.Date(2024, time.July, 28, 0, 0, 0, 0, time.UTC), Amount: -10_30, AccountID: "acc-1"},
+			{ID: 2, Date: time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC), Amount: -20_46, AccountID: "acc-1"},
+			{ID: 3, Date: time.Date(2024, time.August, 13, 0, 0, 0, 0, time.UTC), Amount: 10_00, AccountID: "acc-1"},
+		}, txns)
+
+		require.Len(t, client.queries, 3, "should query once per page")
+		assert.Nil(t, client.queries[0].ExclusiveStartKey)
+		assert.NotNil(t, client.queries[1].ExclusiveStartKey)
+		assert.NotNil(t, client.queries[2].ExclusiveStartKey)
+	})
+
+	t.Run("it should query the account partition", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{pages: [][]DynamoTransaction{{}}}
+		repository, _ := newTestRepository(client)
+
+		// Act
+		txns, err := repository.GetByAccount(context.Background(), "acc-1")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Empty(t, txns)
+		require.Len(t, client.queries, 1)
+		query := client.queries[0]
+		assert.Equal(t, "transactions", *query.TableName)
+		assert.Nil(t, query.IndexName, "should query the table rather than an index")
+		assert.Equal(t, "account_id = :account_id", *query.KeyConditionExpression)
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "acc-1"}, query.ExpressionAttributeValues[":account_id"])
+	})
+
+	t.Run("it should return query errors", func(t *testing.T) {
+		// Arrange
+		queryErr := errors.New("throttled")
+		client := &fakeDynamoDBClient{queryErr: queryErr}
+		repository, _ := newTestRepository(client)
+
+		// Act
+		txns, err := repository.GetByAccount(context.Background(), "acc-1")
+
+		// Assert
+		assert.ErrorIs(t, err, queryErr)
+		assert.Nil(t, txns)
+	})
+
+	t.Run("it should fail on malformed stored dates", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{pages: [][]DynamoTransaction{
+			{{ID: "a", InternalID: 0, Date: "15/07/2024", Amount: 60.5, AccountID: "acc-1"}},
+		}}
+		repository, _ := newTestRepository(client)
+
+		// Act
+		_, err := repository.GetByAccount(context.Background(), "acc-1")
+
+		// Assert
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse date of transaction a")
+	})
+}
+
+func TestDynamoTransactionsRepository_formatDate(t *testing.T) {
+	utcDate := time.Date(2024, time.July, 15, 13, 45, 30, 0, time.UTC)
+	offsetDate := time.Date(2024, time.July, 15, 13, 45, 30, 0, time.FixedZone("UTC-6", -6*60*60))
+
+	tests := []struct {
+		name       string
+		dateFormat string
+		date       time.Time
+		expected   string
+	}{
+		{name: "it should keep the legacy output for UTC times", dateFormat: "", date: utcDate, expected: "2024-07-15T13:45:30Z"},
+		{name: "it should keep the offset of non-UTC times", dateFormat: "", date: offsetDate, expected: "2024-07-15T13:45:30-06:00"},
+		{name: "it should use a custom format", dateFormat: "2006-01-02", date: offsetDate, expected: "2024-07-15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			repository := newDynamoTransactionsRepository(&fakeDynamoDBClient{}, "transactions", DynamoTransactionsRepositoryConfig{DateFormat: tt.dateFormat})
+
+			// Act
+			formatted := repository.formatDate(tt.date)
+
+			// Assert
+			assert.Equal(t, tt.expected, formatted)
+		})
+	}
+}
+
+func TestDynamoTransactionsRepository_parseDate(t *testing.T) {
+	tests := []struct {
+		name        string
+		dateFormat  string
+		value       string
+		expected    time.Time
+		expectError bool
+	}{
+		{
+			name:     "it should read legacy UTC dates",
+			value:    "2024-07-15T13:45:30Z",
+			expected: time.Date(2024, time.July, 15, 13, 45, 30, 0, time.UTC),
+		},
+		{
+			name:     "it should read dates with an offset",
+			value:    "2024-07-15T13:45:30-06:00",
+			expected: time.Date(2024, time.July, 15, 19, 45, 30, 0, time.UTC),
+		},
+		{
+			name:       "it should read dates with a custom format",
+			dateFormat: "2006-01-02",
+			value:      "2024-07-15",
+			expected:   time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "it should read RFC 3339 dates stored before a custom format",
+			dateFormat: "2006-01-02",
+			value:      "2024-07-15T13:45:30Z",
+			expected:   time.Date(2024, time.July, 15, 13, 45, 30, 0, time.UTC),
+		},
+		{
+			name:        "it should fail on malformed dates",
+			value:       "15/07/2024",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			repository := newDynamoTransactionsRepository(&fakeDynamoDBClient{}, "transactions", DynamoTransactionsRepositoryConfig{DateFormat: tt.dateFormat})
+
+			// Act
+			date, err := repository.parseDate(tt.value)
+
+			// Assert
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.expected.Equal(date), "expected %v, got %v", tt.expected, date)
+		})
+	}
+}
+
+func TestDynamoTransactionsRepository_DateRoundTrip(t *testing.T) {
+	// Arrange
+	client := &fakeDynamoDBClient{}
+	repository, _ := newTestRepository(client)
+	date := time.Date(2024, time.July, 15, 13, 45, 30, 0, time.FixedZone("UTC-6", -6*60*60))
+	require.NoError(t, repository.Save(context.Background(), []Transaction{{ID: 0, Date: date, Amount: 60_50, AccountID: "acc-1"}}))
+	client.pages = [][]DynamoTransaction{client.items}
+
+	// Act
+	txns, err := repository.GetByAccount(context.Background(), "acc-1")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, txns, 1)
+	assert.True(t, date.Equal(txns[0].Date), "should keep the instant")
+	_, offset := txns[0].Date.Zone()
+	assert.Equal(t, -6*60*60, offset, "should keep the offset")
+}
+
+func TestDynamoTransactionsRepository_CurrencyRoundTrip(t *testing.T) {
+	// Arrange
+	client := &fakeDynamoDBClient{}
+	repository, _ := newTestRepository(client)
+	date := time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, repository.Save(context.Background(), []Transaction{
+		{ID: 0, Date: date, Amount: 60_50, Currency: "MXN", AccountID: "acc-1"},
+		{ID: 1, Date: date, Amount: -10_30, AccountID: "acc-1"},
+	}))
+	client.pages = [][]DynamoTransaction{client.items}
+
+	// Act
+	txns, err := repository.GetByAccount(context.Background(), "acc-1")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, txns, 2)
+	assert.Equal(t, "MXN", txns[0].Currency)
+	assert.Equal(t, "", txns[1].Currency)
+}
+
+func TestTransactionKey(t *testing.T) {
+	base := Transaction{ID: 1, Date: time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50, AccountID: "acc-1"}
+
+	tests := []struct {
+		name         string
+		transaction  Transaction
+		expectedSame bool
+	}{
+		{
+			name:         "it should yield the same key for the same transaction",
+			transaction:  base,
+			expectedSame: true,
+		},
+		{
+			name:         "it should yield the same key for the same instant in another zone",
+			transaction:  Transaction{ID: 1, Date: base.Date.In(time.FixedZone("UTC-6", -6*60*60)), Amount: 60_50, AccountID: "acc-1"},
+			expectedSame: true,
+		},
+		{
+			name:         "it should yield a different key for another account",
+			transaction:  Transaction{ID: 1, Date: base.Date, Amount: 60_50, AccountID: "acc-2"},
+			expectedSame: false,
+		},
+		{
+			name:         "it should yield a different key for another ID",
+			transaction:  Transaction{ID: 2, Date: base.Date, Amount: 60_50, AccountID: "acc-1"},
+			expectedSame: false,
+		},
+		{
+			name:         "it should yield a different key for another date",
+			transaction:  Transaction{ID: 1, Date: base.Date.AddDate(0, 0, 1), Amount: 60_50, AccountID: "acc-1"},
+			expectedSame: false,
+		},
+		{
+			name:         "it should yield a different key for another amount",
+			transaction:  Transaction{ID: 1, Date: base.Date, Amount: 60_51, AccountID: "acc-1"},
+			expectedSame: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			key := transactionKey(tt.transaction)
+
+			// Assert
+			if tt.expectedSame {
+				assert.Equal(t, transactionKey(base), key)
+			} else {
+				assert.NotEqual(t, transactionKey(base), key)
+			}
+		})
+	}
+}
+
+// newAccountPage creates a page of count stored transactions of the account,
+// with IDs starting at first.
+func newAccountPage(accountID string, first, count int) []DynamoTransaction {
+	page := make([]DynamoTransaction, count)
+	for i := range page {
+		page[i] = DynamoTransaction{AccountID: accountID, ID: "key-" + strconv.Itoa(first+i), InternalID: uint(first + i), Date: "2024-07-15T00:00:00Z", Amount: 1}
+	}
+	return page
+}
+
+func TestDynamoTransactionsRepository_DeleteByAccount(t *testing.T) {
+	t.Run("it should delete every item of the account across pages", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{pages: [][]DynamoTransaction{
+			newAccountPage("acc-1", 0, 30),
+			newAccountPage("acc-1", 30, 2),
+		}}
+		repository, _ := newTestRepository(client)
+
+		// Act
+		deleted, err := repository.DeleteByAccount(context.Background(), "acc-1")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 32, deleted)
+		assert.Equal(t, 3, client.calls, "should split the first page into batches of 25")
+		require.Len(t, client.deletedKeys, 32)
+		for i, key := range client.deletedKeys {
+			assert.Equal(t, map[string]types.AttributeValue{
+				"account_id": &types.AttributeValueMemberS{Value: "acc-1"},
+				"id":         &types.AttributeValueMemberS{Value: "key-" + strconv.Itoa(i)},
+			}, key)
+		}
+		require.Len(t, client.queries, 2, "should query once per page")
+		assert.Nil(t, client.queries[0].ExclusiveStartKey)
+		assert.NotNil(t, client.queries[1].ExclusiveStartKey)
+	})
+
+	t.Run("it should only query the keys of the account partition", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{pages: [][]DynamoTransaction{{}}}
+		repository, _ := newTestRepository(client)
+
+		// Act
+		deleted, err := repository.DeleteByAccount(context.Background(), "acc-1")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+		assert.Zero(t, client.calls, "should not write anything for an account without items")
+		require.Len(t, client.queries, 1)
+		query := client.queries[0]
+		assert.Equal(t, "transactions", *query.TableName)
+		assert.Equal(t, "#account_id = :account_id", *query.KeyConditionExpression)
+		assert.Equal(t, "#account_id, #id", *query.ProjectionExpression)
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "acc-1"}, query.ExpressionAttributeValues[":account_id"])
+	})
+
+	t.Run("it should retry unprocessed deletes", func(t *testing.T) {
+		// Arrange
+		client := &fakeDynamoDBClient{
+			pages:            [][]DynamoTransaction{newAccountPage("acc-1", 0, 3)},
+			unprocessedCalls: 1,
+			unprocessedItems: 2,
+		}
+		repository, delays := newTestRepository(client)
+
+		// Act
+		deleted, err := repository.DeleteByAccount(context.Background(), "acc-1")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 3, deleted)
+		assert.Len(t, client.deletedKeys, 3)
+		assert.Len(t, *delays, 1, "should back off once before retrying")
+	})
+
+	t.Run("it should report the items deleted before a query failure", func(t *testing.T) {
+		// Arrange
+		queryErr := errors.New("throttled")
+		client := &fakeDynamoDBClient{
+			pages:    [][]DynamoTransaction{newAccountPage("acc-1", 0, 2), newAccountPage("acc-1", 2, 2)},
+			pageErrs: map[int]error{1: queryErr},
+		}
+		repository, _ := newTestRepository(client)
+
+		// Act
+		deleted, err := repository.DeleteByAccount(context.Background(), "acc-1")
+
+		// Assert
+		assert.ErrorIs(t, err, queryErr)
+		assert.Contains(t, err.Error(), "page 2")
+		assert.Equal(t, 2, deleted)
+	})
+
+	t.Run("it should report the items deleted before a delete failure", func(t *testing.T) {
+		// Arrange
+		writeErr := errors.New("access denied")
+		client := &fakeDynamoDBClient{
+			pages:     [][]DynamoTransaction{newAccountPage("acc-1", 0, 30)},
+			writeErrs: map[int]error{2: writeErr},
+		}
+		repository, _ := newTestRepository(client)
+
+		// Act
+		deleted, err := repository.DeleteByAccount(context.Background(), "acc-1")
+
+		// Assert
+		assert.ErrorIs(t, err, writeErr)
+		assert.Equal(t, 25, deleted)
+	})
+}
+
+func TestDynamoTransactionsRepository_Ping(t *testing.T) {
+	tests := []struct {
+		name        string
+		client      *fakeDynamoDBClient
+		expectedErr string
+	}{
+		{
+			name:   "it should succeed for an active table",
+			client: &fakeDynamoDBClient{},
+		},
+		{
+			name:   "it should succeed for a table being updated",
+			client: &fakeDynamoDBClient{tableStatus: types.TableStatusUpdating},
+		},
+		{
+			name:        "it should fail for a table being created",
+			client:      &fakeDynamoDBClient{tableStatus: types.TableStatusCreating},
+			expectedErr: "table transactions is not active (status CREATING)",
+		},
+		{
+			name:        "it should fail when the table can't be described",
+			client:      &fakeDynamoDBClient{describeErr: &types.ResourceNotFoundException{Message: aws.String("Requested resource not found")}},
+			expectedErr: "failed to describe table transactions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			repository, _ := newTestRepository(tt.client)
+
+			// Act
+			err := repository.Ping(context.Background())
+
+			// Assert
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectedErr)
+			}
+			assert.Zero(t, tt.client.calls, "should not read or write items")
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package transactions
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// MemoryTransactionsRepository implements the TransactionsRepository interface
+// keeping the transactions in memory. It is meant for local runs and tests.
+type MemoryTransactionsRepository struct {
+	mu           sync.Mutex
+	transactions []Transaction
+}
+
+// NewMemoryTransactionsRepository creates a new, empty MemoryTransactionsRepository.
+func NewMemoryTransactionsRepository() *MemoryTransactionsRepository {
+	return &MemoryTransactionsRepository{}
+}
+
+// Save appends the given transactions to the in-memory storage.
+// Nothing is saved when the context is already done.
+func (r *MemoryTransactionsRepository) Save(ctx context.Context, transactions []Transaction) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.transactions = append(r.transactions, transactions...)
+	return nil
+}
+
+// GetByAccount returns the saved transactions of the given account, in the
+// order they were saved.
+func (r *MemoryTransactionsRepository) GetByAccount(ctx context.Context, accountID string) ([]Transaction, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var transactions []Transaction
+	for _, transaction := range r.transactions {
+		if transaction.AccountID == accountID {
+			transactions = append(transactions, transaction)
+		}
+	}
+	return transactions, nil
+}
+
+// All returns a copy of all the saved transactions, in the order they were saved.
+func (r *MemoryTransactionsRepository) All() []Transaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.transactions)
+}
@@ -0,0 +1,142 @@
+package transactions
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryTransactionsRepository_Save(t *testing.T) {
+	t.Run("it should return the transactions in the order they were saved", func(t *testing.T) {
+		// Arrange
+		repository := NewMemoryTransactionsRepository()
+		first := []Transaction{
+			{ID: 0, Date: time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC), Amount: 60_50, AccountID: "acc-1"},
+			{ID: 1, Date: time.Date(2024, time.July, 28, 0, 0, 0, 0, time.UTC), Amount: -10_30, AccountID: "acc-1"},
+		}
+		second := []Transaction{
+			{ID: 2, Date: time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC), Amount: -20_46, AccountID: "acc-1"},
+		}
+
+		// Act
+		require.NoError(t, repository.Save(context.Background(), first))
+		require.NoError(t, repository.Save(context.Background(), second))
+
+		// Assert
+		assert.Equal(t, append(first, second...), repository.All())
+	})
+
+	t.Run("it should return an empty result when nothing was saved", func(t *testing.T) {
+		// Arrange
+		repository := NewMemoryTransactionsRepository()
+
+		// Act
+		all := repository.All()
+
+		// Assert
+		assert.Empty(t, all)
+	})
+
+	t.Run("it should not expose its storage", func(t *testing.T) {
+		// Arrange
+		repository := NewMemoryTransactionsRepository()
+		require.NoError(t, repository.Save(context.Background(), []Transaction{{ID: 0, Amount: 60_50}}))
+
+		// Act
+		all := repository.All()
+		all[0].Amount = 0
+
+		// Assert
+		assert.Equal(t, Money(60_50), repository.All()[0].Amount)
+	})
+
+	t.Run("it should not save with a cancelled context", func(t *testing.T) {
+		// Arrange
+		repository := NewMemoryTransactionsRepository()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Act
+		err := repository.Save(ctx, []Transaction{{ID: 0, Amount: 60_50}})
+
+		// Assert
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, repository.All())
+	})
+
+	t.Run("it should handle concurrent saves", func(t *testing.T) {
+		// Arrange
+		repository := NewMemoryTransactionsRepository()
+		const workers = 10
+		const perWorker = 100
+
+		// Act
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < perWorker; i++ {
+					txn := Transaction{ID: uint(w*perWorker + i), Amount: 1_00}
+					assert.NoError(t, repository.Save(context.Background(), []Transaction{txn}))
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		// Assert
+		all := repository.All()
+		require.Len(t, all, workers*perWorker)
+		seen := make(map[uint]bool, len(all))
+		lastByWorker := make(map[uint]int)
+		for _, txn := range all {
+			seen[txn.ID] = true
+
+			// Saves of the same worker should keep their order
+			worker, index := txn.ID/perWorker, int(txn.ID%perWorker)
+			if last, ok := lastByWorker[worker]; ok {
+				assert.Greater(t, index, last)
+			}
+			lastByWorker[worker] = index
+		}
+		assert.Len(t, seen, workers*perWorker, "should keep every saved transaction")
+	})
+}
+
+func TestMemoryTransactionsRepository_GetByAccount(t *testing.T) {
+	// Arrange
+	repository := NewMemoryTransactionsRepository()
+	require.NoError(t, repository.Save(context.Background(), []Transaction{
+		{ID: 0, Amount: 60_50, AccountID: "acc-1"},
+		{ID: 1, Amount: -10_30, AccountID: "acc-2"},
+		{ID: 2, Amount: -20_46, AccountID: "acc-1"},
+	}))
+
+	tests := []struct {
+		name        string
+		accountID   string
+		expectedIDs []uint
+	}{
+		{name: "it should return the transactions of the account", accountID: "acc-1", expectedIDs: []uint{0, 2}},
+		{name: "it should return nothing for an unknown account", accountID: "acc-3", expectedIDs: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			txns, err := repository.GetByAccount(context.Background(), tt.accountID)
+
+			// Assert
+			require.NoError(t, err)
+			var ids []uint
+			for _, txn := range txns {
+				ids = append(ids, txn.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package transactions
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// minorUnitsPerMajor is the number of minor units (cents) in a major unit (dollar).
+const minorUnitsPerMajor = 100
+
+// minorUnitDigits is the number of decimals represented by a minor unit.
+const minorUnitDigits = 2
+
+var (
+	// ErrInvalidMoney is returned when an amount isn't a valid decimal number.
+	ErrInvalidMoney = errors.New("invalid amount")
+
+	// ErrMoneyPrecision is returned when an amount has more decimals than a minor unit.
+	ErrMoneyPrecision = errors.New("amount has more than 2 decimals")
+
+	// ErrMoneyOverflow is returned when an amount doesn't fit in Money.
+	ErrMoneyOverflow = errors.New("amount out of range")
+)
+
+// Money is a monetary amount in minor units (cents), so that amounts can be
+// added up exactly, without the drift of float64 arithmetic.
+type Money int64
+
+// ParseMoney parses a decimal amount in major units, such as "+60.5", "-10.30"
+// or "1250", exactly into Money. Amounts with more than 2 decimals are rejected
+// with ErrMoneyPrecision, as they can't be represented exactly.
+func ParseMoney(value string) (Money, error) {
+	digits, negative := value, false
+	if digits != "" && (digits[0] == '+' || digits[0] == '-') {
+		digits, negative = digits[1:], digits[0] == '-'
+	}
+
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if (whole == "" && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, value)
+	}
+	if len(fraction) > minorUnitDigits {
+		return 0, fmt.Errorf("%w: %q", ErrMoneyPrecision, value)
+	}
+
+	// Accumulate the digits in minor units, padding the fraction (e.g. "60.5" is
+	// 6050 cents), without building intermediate strings
+	var minor int64
+	for i := 0; i < len(whole)+minorUnitDigits; i++ {
+		var digit int64
+		switch {
+		case i < len(whole):
+			digit = int64(whole[i] - '0')
+		case i-len(whole) < len(fraction):
+			digit = int64(fraction[i-len(whole)] - '0')
+		}
+		if minor > (math.MaxInt64-digit)/10 {
+			return 0, fmt.Errorf("%w: %q", ErrMoneyOverflow, value)
+		}
+		minor = minor*10 + digit
+	}
+	if negative {
+		minor = -minor
+	}
+	return Money(minor), nil
+}
+
+// isDigits reports whether the value only contains ASCII digits.
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MoneyFromFloat converts an amount in major units into Money, rounding it
+// half away from zero to the nearest minor unit.
+func MoneyFromFloat(amount float64) Money {
+	return Money(math.Round(amount * minorUnitsPerMajor))
+}
+
+// Float64 returns the amount in major units, e.g. 60.5 for 6050 cents.
+// It's meant for presentation and compatibility; add up Money values instead
+// to avoid float64 drift.
+func (m Money) Float64() float64 {
+	return float64(m) / minorUnitsPerMajor
+}
+
+// String returns the amount in major units with 2 decimals, e.g. "-10.30".
+func (m Money) String() string {
+	sign := ""
+	minor := int64(m)
+	if minor < 0 {
+		sign = "-"
+	}
+	// Avoid negating math.MinInt64, which overflows
+	whole, fraction := minor/minorUnitsPerMajor, minor%minorUnitsPerMajor
+	return fmt.Sprintf("%s%d.%02d", sign, absInt64(whole), absInt64(fraction))
+}
+
+// absInt64 returns the absolute value of n.
+func absInt64(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
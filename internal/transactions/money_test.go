@@ -0,0 +1,120 @@
+package transactions
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		expected      Money
+		expectedError error
+	}{
+		{name: "it should parse an amount with cents", value: "60.50", expected: 60_50},
+		{name: "it should pad an amount with one decimal", value: "60.5", expected: 60_50},
+		{name: "it should parse an amount without decimals", value: "1250", expected: 1250_00},
+		{name: "it should parse an explicitly positive amount", value: "+60.5", expected: 60_50},
+		{name: "it should parse a negative amount", value: "-10.3", expected: -10_30},
+		{name: "it should parse an amount below one", value: "-0.05", expected: -5},
+		{name: "it should parse an amount without whole part", value: ".5", expected: 50},
+		{name: "it should parse zero", value: "0", expected: 0},
+		{name: "it should reject an empty amount", value: "", expectedError: ErrInvalidMoney},
+		{name: "it should reject a lone sign", value: "-", expectedError: ErrInvalidMoney},
+		{name: "it should reject a lone point", value: ".", expectedError: ErrInvalidMoney},
+		{name: "it should reject letters", value: "abc", expectedError: ErrInvalidMoney},
+		{name: "it should reject a double sign", value: "+-5", expectedError: ErrInvalidMoney},
+		{name: "it should reject the exponent notation", value: "1e3", expectedError: ErrInvalidMoney},
+		{name: "it should reject more than 2 decimals", value: "123.456", expectedError: ErrMoneyPrecision},
+		{name: "it should reject an amount out of range", value: "100000000000000000000", expectedError: ErrMoneyOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual, err := ParseMoney(tt.value)
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestMoneyFromFloat(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		expected Money
+	}{
+		{name: "it should convert an amount with cents", amount: 60.5, expected: 60_50},
+		{name: "it should convert an amount that isn't exact in float64", amount: 0.29, expected: 29},
+		{name: "it should convert a negative amount", amount: -10.3, expected: -10_30},
+		{name: "it should round half away from zero", amount: -0.125, expected: -13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual := MoneyFromFloat(tt.amount)
+
+			// Assert
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   Money
+		expected string
+	}{
+		{name: "it should format cents", amount: 60_50, expected: "60.50"},
+		{name: "it should format a negative amount", amount: -10_30, expected: "-10.30"},
+		{name: "it should format a negative amount below one", amount: -5, expected: "-0.05"},
+		{name: "it should format zero", amount: 0, expected: "0.00"},
+		{name: "it should format the lowest amount", amount: math.MinInt64, expected: "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual := tt.amount.String()
+
+			// Assert
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestMoney_ExactSum(t *testing.T) {
+	t.Run("it should add up amounts exactly where float64 drifts", func(t *testing.T) {
+		// Arrange
+		var (
+			floatSum float64
+			moneySum Money
+		)
+
+		// Act
+		for range 1000 {
+			amount, err := ParseMoney("0.10")
+			require.NoError(t, err)
+			floatSum += 0.10
+			moneySum += amount
+		}
+
+		// Assert
+		assert.NotEqual(t, 100.0, floatSum, "float64 should drift")
+		assert.Equal(t, Money(100_00), moneySum)
+		assert.Equal(t, 100.0, moneySum.Float64())
+	})
+}
@@ -0,0 +1,48 @@
+package transactions
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RecordError describes a single CSV record that could not be loaded.
+type RecordError struct {
+	// Line is the line number of the invalid record within the source file.
+	Line int
+
+	// Err is the reason why the record was rejected.
+	Err error
+}
+
+// Error returns the reason why the record was rejected.
+func (e RecordError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying reason, so it can be inspected with errors.Is/As.
+func (e RecordError) Unwrap() error {
+	return e.Err
+}
+
+// RecordErrors aggregates every invalid record found while loading a file.
+// It is returned alongside the valid transactions when errors are collected
+// instead of aborting on the first invalid record.
+type RecordErrors []RecordError
+
+// Error returns a single message listing every invalid record.
+func (errs RecordErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("%d invalid records: %s", len(errs), strings.Join(messages, "; "))
+}
+
+// Unwrap returns every record error, so the aggregate can be inspected with errors.Is/As.
+func (errs RecordErrors) Unwrap() []error {
+	unwrapped := make([]error, 0, len(errs))
+	for _, err := range errs {
+		unwrapped = append(unwrapped, err)
+	}
+	return unwrapped
+}
@@ -10,10 +10,19 @@ type Transaction struct {
 	// Date is the date when the transaction occurred.
 	Date time.Time
 
-	// Amount is the monetary value of the transaction.
-	// In this case, for demo purposes, we don't care about currency or precision.
-	Amount float64
+	// Amount is the monetary value of the transaction, in minor units (cents),
+	// so amounts add up exactly. See AmountFloat for the value in major units.
+	Amount Money
+
+	// Currency is the ISO 4217 code of the currency of the amount (e.g. "USD").
+	Currency string
 
 	// AccountID is the identifier of the account associated with this transaction.
 	AccountID string
 }
+
+// AmountFloat returns the amount in major units (e.g. dollars), for
+// compatibility with code that works with float64 amounts.
+func (t Transaction) AmountFloat() float64 {
+	return t.Amount.Float64()
+}
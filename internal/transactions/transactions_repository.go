@@ -7,4 +7,7 @@ import "context"
 type TransactionsRepository interface {
 	// Save persists the given transactions to a persistent storage.
 	Save(ctx context.Context, transactions []Transaction) (err error)
+
+	// GetByAccount returns the transactions stored for the given account.
+	GetByAccount(ctx context.Context, accountID string) (transactions []Transaction, err error)
 }
@@ -0,0 +1,21 @@
+package transactions
+
+// ValidationReport describes the outcome of validating a file without loading it.
+type ValidationReport struct {
+	// TotalRows is the number of data rows of the file (excluding the header)
+	TotalRows int
+
+	// ValidRows is the number of rows that would be loaded as transactions
+	ValidRows int
+
+	// InvalidRows is the number of rows that would be rejected
+	InvalidRows int
+
+	// Errors lists the first invalid rows, up to the configured MaxValidationErrors
+	Errors RecordErrors
+}
+
+// Valid reports whether every row of the file is valid.
+func (r ValidationReport) Valid() bool {
+	return r.InvalidRows == 0
+}
@@ -0,0 +1,24 @@
+package blend
+
+import "context"
+
+// correlationIDKey is the context key under which the correlation ID is stored.
+type correlationIDKey struct{}
+
+// ContextWithCorrelationID returns a copy of the given context carrying the
+// given correlation ID. Every message logged with the returned context (or a
+// context derived from it) includes the correlation ID, so the log lines of a
+// single unit of work (e.g. an S3 record) can be told apart.
+func ContextWithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, correlationID)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in the given
+// context, and whether there was one.
+func CorrelationIDFromContext(ctx context.Context) (correlationID string, ok bool) {
+	if ctx == nil {
+		return "", false
+	}
+	correlationID, ok = ctx.Value(correlationIDKey{}).(string)
+	return correlationID, ok && correlationID != ""
+}
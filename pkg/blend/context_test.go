@@ -0,0 +1,47 @@
+package blend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelationIDFromContext(t *testing.T) {
+	tests := []struct {
+		name                  string
+		usedCtx               context.Context
+		expectedCorrelationID string
+		expectedOk            bool
+	}{
+		{
+			name:                  "it should return the stored correlation ID",
+			usedCtx:               ContextWithCorrelationID(context.Background(), "request-1/0"),
+			expectedCorrelationID: "request-1/0",
+			expectedOk:            true,
+		},
+		{
+			name:                  "it should return false when there is no correlation ID",
+			usedCtx:               context.Background(),
+			expectedCorrelationID: "",
+			expectedOk:            false,
+		},
+		{
+			name:                  "it should return false for an empty correlation ID",
+			usedCtx:               ContextWithCorrelationID(context.Background(), ""),
+			expectedCorrelationID: "",
+			expectedOk:            false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act.
+			actualCorrelationID, actualOk := CorrelationIDFromContext(tt.usedCtx)
+
+			// Assert.
+			assert.Equal(t, tt.expectedCorrelationID, actualCorrelationID)
+			assert.Equal(t, tt.expectedOk, actualOk)
+		})
+	}
+}
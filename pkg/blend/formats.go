@@ -0,0 +1,20 @@
+package blend
+
+// Format represents the output format of the log messages.
+type Format string
+
+// String returns the string representation of the output format.
+func (format Format) String() string {
+	return string(format)
+}
+
+const (
+	// JSON is an output format that writes each message as a JSON object,
+	// which is easy to parse and query (e.g. with CloudWatch Logs Insights).
+	// It's the default output format.
+	JSON Format = "json"
+
+	// Console is an output format that writes each message as a human-readable
+	// line, which is easier on the eyes during local development.
+	Console Format = "console"
+)
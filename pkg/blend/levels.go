@@ -1,5 +1,10 @@
 package blend
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Level represents a log level which can be used to filter log messages.
 type Level string
 
@@ -13,6 +18,37 @@ func (level Level) Equal(other Level) bool {
 	return string(level) == string(other)
 }
 
+// severity returns the rank of the log level, where higher levels are more
+// severe. Unknown levels rank as Debug, so they are never filtered out by
+// accident.
+func (level Level) severity() int {
+	switch level {
+	case Info:
+		return 1
+	case Warn:
+		return 2
+	case Error:
+		return 3
+	case Fatal:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// ParseLevel returns the log level matching the given name, ignoring case and
+// surrounding spaces (e.g. "INFO" returns Info). Useful for reading the log
+// level from environment variables.
+func ParseLevel(name string) (level Level, err error) {
+	level = Level(strings.ToLower(strings.TrimSpace(name)))
+	switch level {
+	case Debug, Info, Warn, Error, Fatal:
+		return level, nil
+	default:
+		return "", fmt.Errorf("unknown log level %q", name)
+	}
+}
+
 const (
 	// Debug is a log level that is used for debugging purposes, and is usually
 	// disabled in production environments. It is used to log messages that are
@@ -49,3 +49,33 @@ func TestLevel_Equal(t *testing.T) {
 		assert.False(t, actualResult)
 	})
 }
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name          string
+		usedName      string
+		expectedLevel Level
+		expectedErr   bool
+	}{
+		{name: "it should parse a lowercase level", usedName: "info", expectedLevel: Info},
+		{name: "it should parse an uppercase level", usedName: "DEBUG", expectedLevel: Debug},
+		{name: "it should ignore surrounding spaces", usedName: " warn ", expectedLevel: Warn},
+		{name: "it should return an error for an unknown level", usedName: "verbose", expectedErr: true},
+		{name: "it should return an error for an empty level", usedName: "", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act.
+			actualLevel, actualErr := ParseLevel(tt.usedName)
+
+			// Assert.
+			if tt.expectedErr {
+				assert.Error(t, actualErr)
+				return
+			}
+			assert.NoError(t, actualErr)
+			assert.Equal(t, tt.expectedLevel, actualLevel)
+		})
+	}
+}
@@ -16,6 +16,18 @@ func Default(output io.Writer) (logger Logger, err error) {
 	return NewZerologLogger(output)
 }
 
+// DefaultWithFormat returns a default Logger implementation for the given
+// output writer, writing the messages in the given format (JSON or Console).
+//
+// See Default for more information.
+func DefaultWithFormat(output io.Writer, format Format) (logger Logger, err error) {
+	zerologLogger, err := NewZerologLoggerWithFormat(output, format)
+	if err != nil {
+		return nil, err
+	}
+	return zerologLogger, nil
+}
+
 // Logger is an interface that exposes different methods for logging messages
 // in a variety of different levels.
 type Logger interface {
@@ -74,6 +86,12 @@ type Logger interface {
 	// which will be used to format the message string (similar to fmt.Printf verbs).
 	// See https://pkg.go.dev/fmt#hdr-Printing for more information.
 	Fatal(ctx context.Context, message string, args ...any) error
+
+	// Sync flushes any buffered log output to the underlying writer.
+	// It should be called before the application is frozen or terminated
+	// (e.g. at the end of a Lambda invocation), so no log output is lost.
+	// Writers without buffering are left untouched.
+	Sync(ctx context.Context) error
 }
 
 // DummyLogger is a dummy implementation of the Logger interface.
@@ -107,3 +125,7 @@ func (logger *DummyLogger) Error(ctx context.Context, message string, args ...an
 func (logger *DummyLogger) Fatal(ctx context.Context, message string, args ...any) error {
 	return nil
 }
+
+func (logger *DummyLogger) Sync(ctx context.Context) error {
+	return nil
+}
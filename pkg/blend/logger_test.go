@@ -0,0 +1,66 @@
+package blend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultWithFormat(t *testing.T) {
+	t.Run("it should write valid JSON with the JSON format", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx        context.Context = context.Background()
+			usedOutput *bytes.Buffer   = new(bytes.Buffer)
+		)
+		usedLogger, err := DefaultWithFormat(usedOutput, JSON)
+		require.NoError(t, err)
+
+		// Act.
+		actualErr := usedLogger.Info(ctx, "Hello, world! %s", ":D")
+
+		// Assert.
+		assert.NoError(t, actualErr)
+		assert.True(t, json.Valid(usedOutput.Bytes()), "output should be valid JSON: %s", usedOutput.String())
+		assert.Contains(t, usedOutput.String(), "Hello, world! :D")
+	})
+
+	t.Run("it should write human-readable lines with the console format", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx        context.Context = context.Background()
+			usedOutput *bytes.Buffer   = new(bytes.Buffer)
+		)
+		usedLogger, err := DefaultWithFormat(usedOutput, Console)
+		require.NoError(t, err)
+
+		// Act.
+		actualErr := usedLogger.Info(ctx, "Hello, world! %s", ":D")
+
+		// Assert.
+		assert.NoError(t, actualErr)
+		assert.False(t, json.Valid(usedOutput.Bytes()), "output should not be JSON: %s", usedOutput.String())
+		assert.Contains(t, usedOutput.String(), "Hello, world! :D")
+	})
+
+	t.Run("it should return an error for an unknown format", func(t *testing.T) {
+		// Act.
+		usedLogger, actualErr := DefaultWithFormat(new(bytes.Buffer), Format("xml"))
+
+		// Assert.
+		assert.Error(t, actualErr)
+		assert.True(t, usedLogger == nil, "logger should be a nil interface")
+	})
+
+	t.Run("it should return an error if the output writer is nil", func(t *testing.T) {
+		// Act.
+		_, actualErr := DefaultWithFormat(nil, Console)
+
+		// Assert.
+		assert.Error(t, actualErr)
+	})
+}
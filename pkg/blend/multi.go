@@ -0,0 +1,107 @@
+package blend
+
+import (
+	"context"
+	"errors"
+)
+
+// fatalLogger is implemented by loggers that can log a fatal message without
+// terminating the application right away, returning the function that does
+// it instead (nil if the logger doesn't terminate the application).
+type fatalLogger interface {
+	logFatal(ctx context.Context, message string, args ...any) (exit func(code int), err error)
+}
+
+// MultiLogger is a structure that implements the Logger interface, fanning
+// each message out to all the wrapped loggers. It's useful for writing the
+// same logs to several outputs, such as stdout and an in-memory buffer.
+type MultiLogger struct {
+	// loggers are the wrapped loggers, called in order.
+	loggers []Logger
+}
+
+// NewMultiLogger returns a Logger that fans each message out to all the
+// given loggers, with the same level and context. The errors of the wrapped
+// loggers are joined (see errors.Join).
+func NewMultiLogger(loggers ...Logger) Logger {
+	return &MultiLogger{loggers: loggers}
+}
+
+// each calls the given function with each wrapped logger, joining their errors.
+func (logger *MultiLogger) each(call func(wrapped Logger) error) error {
+	var errs []error
+	for _, wrapped := range logger.loggers {
+		if err := call(wrapped); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Debug logs a message at the debug level on all the wrapped loggers.
+func (logger *MultiLogger) Debug(ctx context.Context, message string, args ...any) error {
+	return logger.each(func(wrapped Logger) error {
+		return wrapped.Debug(ctx, message, args...)
+	})
+}
+
+// Info logs a message at the info level on all the wrapped loggers.
+func (logger *MultiLogger) Info(ctx context.Context, message string, args ...any) error {
+	return logger.each(func(wrapped Logger) error {
+		return wrapped.Info(ctx, message, args...)
+	})
+}
+
+// Warn logs a message at the warn level on all the wrapped loggers.
+func (logger *MultiLogger) Warn(ctx context.Context, message string, args ...any) error {
+	return logger.each(func(wrapped Logger) error {
+		return wrapped.Warn(ctx, message, args...)
+	})
+}
+
+// Error logs a message at the error level on all the wrapped loggers.
+func (logger *MultiLogger) Error(ctx context.Context, message string, args ...any) error {
+	return logger.each(func(wrapped Logger) error {
+		return wrapped.Error(ctx, message, args...)
+	})
+}
+
+// Fatal logs a message at the fatal level on all the wrapped loggers, and
+// then terminates the application.
+//
+// Wrapped loggers that terminate the application (like ZerologLogger) only
+// do so once every logger has logged the message and has been synced, so
+// no logger misses it.
+func (logger *MultiLogger) Fatal(ctx context.Context, message string, args ...any) error {
+	exit, err := logger.logFatal(ctx, message, args...)
+	if exit != nil {
+		_ = logger.Sync(ctx)
+		exit(1)
+	}
+	return err
+}
+
+// logFatal logs a message at the fatal level on all the wrapped loggers,
+// returning the first function that terminates the application.
+func (logger *MultiLogger) logFatal(ctx context.Context, message string, args ...any) (exit func(code int), err error) {
+	err = logger.each(func(wrapped Logger) error {
+		deferred, ok := wrapped.(fatalLogger)
+		if !ok {
+			return wrapped.Fatal(ctx, message, args...)
+		}
+
+		wrappedExit, wrappedErr := deferred.logFatal(ctx, message, args...)
+		if exit == nil {
+			exit = wrappedExit
+		}
+		return wrappedErr
+	})
+	return
+}
+
+// Sync flushes the buffered log output of all the wrapped loggers.
+func (logger *MultiLogger) Sync(ctx context.Context) error {
+	return logger.each(func(wrapped Logger) error {
+		return wrapped.Sync(ctx)
+	})
+}
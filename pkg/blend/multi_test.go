@@ -0,0 +1,134 @@
+package blend
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestZerologLogger returns a ZerologLogger writing to a buffer, with a
+// fixed time and a recorded exit.
+func newTestZerologLogger(exitCodes *[]int) (*ZerologLogger, *bytes.Buffer) {
+	output := new(bytes.Buffer)
+	logger, _ := NewZerologLogger(output)
+	logger.now = func() time.Time {
+		t, _ := time.Parse(time.RFC3339, "2003-05-01T00:00:00Z")
+		return t
+	}
+	logger.exit = func(code int) {
+		*exitCodes = append(*exitCodes, code)
+	}
+	return logger, output
+}
+
+func TestMultiLogger(t *testing.T) {
+	t.Run("it should send the same message to every wrapped logger", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx       context.Context = ContextWithCorrelationID(context.Background(), "req-1")
+			exitCodes []int
+
+			usedFirst, firstOutput   = newTestZerologLogger(&exitCodes)
+			usedSecond, secondOutput = newTestZerologLogger(&exitCodes)
+			usedLogger               = NewMultiLogger(usedFirst, usedSecond)
+
+			expectedOutput string = "{\"level\":\"warn\",\"correlation_id\":\"req-1\",\"time\":\"2003-05-01T00:00:00Z\",\"message\":\"Hello, world! :D\"}"
+		)
+
+		// Act.
+		actualErr := usedLogger.Warn(ctx, "Hello, world! %s", ":D")
+
+		// Assert.
+		assert.NoError(t, actualErr)
+		assert.JSONEq(t, expectedOutput, firstOutput.String())
+		assert.JSONEq(t, expectedOutput, secondOutput.String())
+	})
+
+	t.Run("it should keep the level filter of each wrapped logger", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx       context.Context = context.Background()
+			exitCodes []int
+
+			usedFirst, firstOutput   = newTestZerologLogger(&exitCodes)
+			usedSecond, secondOutput = newTestZerologLogger(&exitCodes)
+			usedLogger               = NewMultiLogger(usedFirst, usedSecond)
+		)
+		usedSecond.SetLevel(Info)
+
+		// Act.
+		actualErr := usedLogger.Debug(ctx, "Hello, world!")
+
+		// Assert.
+		assert.NoError(t, actualErr)
+		assert.NotEmpty(t, firstOutput.String())
+		assert.Empty(t, secondOutput.String())
+	})
+
+	t.Run("it should join the errors of the wrapped loggers", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx       context.Context = context.Background()
+			exitCodes []int
+
+			usedFirst, _  = newTestZerologLogger(&exitCodes)
+			usedSecond, _ = newTestZerologLogger(&exitCodes)
+			usedLogger    = NewMultiLogger(usedFirst, &ZerologLogger{}, usedSecond, &ZerologLogger{})
+		)
+
+		// Act.
+		actualErr := usedLogger.Error(ctx, "Hello, world!")
+
+		// Assert.
+		assert.ErrorIs(t, actualErr, io.ErrClosedPipe)
+		assert.Len(t, actualErr.(interface{ Unwrap() []error }).Unwrap(), 2)
+	})
+
+	t.Run("it should log on every wrapped logger before exiting once on Fatal", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx       context.Context = context.Background()
+			exitCodes []int
+
+			usedFirst, firstOutput   = newTestZerologLogger(&exitCodes)
+			usedSecond, secondOutput = newTestZerologLogger(&exitCodes)
+			usedLogger               = NewMultiLogger(usedFirst, usedSecond, NewMultiLogger(&DummyLogger{}))
+
+			expectedOutput string = "{\"level\":\"fatal\",\"time\":\"2003-05-01T00:00:00Z\",\"message\":\"Bye!\"}"
+		)
+
+		// Act.
+		actualErr := usedLogger.Fatal(ctx, "Bye!")
+
+		// Assert.
+		assert.NoError(t, actualErr)
+		assert.JSONEq(t, expectedOutput, firstOutput.String())
+		assert.JSONEq(t, expectedOutput, secondOutput.String())
+		assert.Equal(t, []int{1}, exitCodes)
+	})
+
+	t.Run("it should sync every wrapped logger", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx context.Context = context.Background()
+
+			usedFirstOutput  *syncRecorder = new(syncRecorder)
+			usedSecondOutput *syncRecorder = &syncRecorder{syncErr: io.ErrShortWrite}
+			usedFirst, _                   = NewZerologLogger(usedFirstOutput)
+			usedSecond, _                  = NewZerologLogger(usedSecondOutput)
+			usedLogger                     = NewMultiLogger(usedFirst, usedSecond)
+		)
+
+		// Act.
+		actualErr := usedLogger.Sync(ctx)
+
+		// Assert.
+		assert.True(t, usedFirstOutput.synced)
+		assert.True(t, usedSecondOutput.synced)
+		assert.ErrorIs(t, actualErr, io.ErrShortWrite)
+	})
+}
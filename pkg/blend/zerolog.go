@@ -2,8 +2,11 @@ package blend
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"syscall"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -26,6 +29,15 @@ type ZerologLogger struct {
 	// This is useful for testing purposes, as it allows us to mock the
 	// current time.
 	now func() time.Time
+
+	// minLevel is the lowest level that will be logged. Messages below
+	// this level are dropped (see SetLevel).
+	minLevel Level
+
+	// exit is a function that terminates the application with the given
+	// status code, called by Fatal. This is useful for testing purposes, as
+	// it allows us to test Fatal without terminating the tests.
+	exit func(code int)
 }
 
 // NewZerologLogger returns a new instance of ZerologLogger.
@@ -41,14 +53,53 @@ func NewZerologLogger(output io.Writer) (logger *ZerologLogger, err error) {
 
 	// Create a new instance of ZerologLogger.
 	logger = &ZerologLogger{
-		engine: &engine,
-		output: output,
-		now:    time.Now,
+		engine:   &engine,
+		output:   output,
+		now:      time.Now,
+		minLevel: Debug,
+		exit:     os.Exit,
 	}
 	return
 
 }
 
+// NewZerologLoggerWithFormat returns a new instance of ZerologLogger that
+// writes the messages in the given format. The JSON format is the same as
+// NewZerologLogger's, while the Console format uses zerolog.ConsoleWriter.
+func NewZerologLoggerWithFormat(output io.Writer, format Format) (logger *ZerologLogger, err error) {
+	// Check if the output writer is nil.
+	if output == nil {
+		err = io.ErrClosedPipe
+		return
+	}
+
+	switch format {
+	case JSON:
+		return NewZerologLogger(output)
+	case Console:
+		logger, err = NewZerologLogger(zerolog.ConsoleWriter{Out: output, TimeFormat: time.RFC3339})
+		if err != nil {
+			return
+		}
+
+		// Keep the original writer, so Sync can flush it.
+		logger.output = output
+		return
+	default:
+		err = fmt.Errorf("unknown log format %q", format)
+		return
+	}
+}
+
+// SetLevel sets the lowest level that will be logged, dropping the messages
+// below it. For example, when the level is Info, Debug messages are dropped.
+// By default, every message is logged.
+//
+// It's meant to be called once, before the logger is shared between goroutines.
+func (logger *ZerologLogger) SetLevel(level Level) {
+	logger.minLevel = level
+}
+
 // log is an internal method that logs a message at the specified level.
 func (logger *ZerologLogger) log(ctx context.Context, level Level, message string, args ...any) (err error) {
 	// If the logger engine or the output writer are not set, return an error.
@@ -57,15 +108,33 @@ func (logger *ZerologLogger) log(ctx context.Context, level Level, message strin
 		return
 	}
 
+	// Drop messages below the minimum level.
+	if level.severity() < logger.minLevel.severity() {
+		return
+	}
+
 	// Nice, we can log the message.
 	leveledLogger := logger.engine.WithLevel(zerolog.NoLevel)
-	leveledLogger.
+	WithContextFields(ctx, leveledLogger).
 		Time("time", logger.now()).
 		Str("level", level.String()).
 		Msgf(message, args...)
 	return
 }
 
+// WithContextFields adds the fields stored in the given context (such as the
+// correlation ID, see ContextWithCorrelationID) to the given zerolog event.
+// Fields missing from the context are not added.
+//
+// It's used on every message logged by ZerologLogger, and is exported for
+// callers that log through the underlying engine (see Engine).
+func WithContextFields(ctx context.Context, event *zerolog.Event) *zerolog.Event {
+	if correlationID, ok := CorrelationIDFromContext(ctx); ok {
+		event = event.Str("correlation_id", correlationID)
+	}
+	return event
+}
+
 // Debug logs a message at the debug level.
 // The debug level is used for debugging purposes, and is usually disabled
 // in production environments. It is used to log messages that are only
@@ -129,8 +198,51 @@ func (logger *ZerologLogger) Error(ctx context.Context, message string, args ...
 // which will be used to format the message string (similar to fmt.Printf verbs).
 // See https://pkg.go.dev/fmt#hdr-Printing for more information.
 func (logger *ZerologLogger) Fatal(ctx context.Context, message string, args ...any) (err error) {
+	exit, err := logger.logFatal(ctx, message, args...)
+	exit(1)
+	return
+}
+
+// logFatal logs a message at the fatal level without terminating the
+// application, and returns the function that terminates it.
+func (logger *ZerologLogger) logFatal(ctx context.Context, message string, args ...any) (exit func(code int), err error) {
 	err = logger.log(ctx, Fatal, message, args...)
-	os.Exit(1)
+
+	// Fall back to os.Exit for loggers not built with NewZerologLogger.
+	exit = logger.exit
+	if exit == nil {
+		exit = os.Exit
+	}
+	return
+}
+
+// syncer is implemented by writers that can flush buffered data to their
+// storage, such as *os.File.
+type syncer interface {
+	Sync() error
+}
+
+// flusher is implemented by writers that buffer data in memory, such as
+// *bufio.Writer.
+type flusher interface {
+	Flush() error
+}
+
+// Sync flushes any buffered log output to the underlying writer, when it
+// implements a Sync() error or Flush() error method. Other writers are left
+// untouched, and so are files that can't be synced (e.g. stdout on a pipe).
+func (logger *ZerologLogger) Sync(ctx context.Context) (err error) {
+	switch output := logger.output.(type) {
+	case syncer:
+		err = output.Sync()
+
+		// Pipes and terminals (like stdout) can't be synced, and don't need to.
+		if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTSUP) {
+			err = nil
+		}
+	case flusher:
+		err = output.Flush()
+	}
 	return
 }
 
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"os"
+	"syscall"
 	"testing"
 	"time"
 
@@ -145,5 +147,294 @@ func TestZerologLogger_log(t *testing.T) {
 		assert.JSONEq(t, expectedOutput, usedLogger.output.(*bytes.Buffer).String())
 		assert.NoError(t, actualErr)
 	})
-	// We are not going to test Fatal, as it terminates the application (I don't know how to test that :P).
+}
+
+func TestZerologLogger_Fatal(t *testing.T) {
+	t.Run("it should log the message before exiting with code 1", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx context.Context = context.Background()
+
+			usedOutput    io.Writer = bytes.NewBuffer([]byte{})
+			usedLogger, _           = NewZerologLogger(usedOutput)
+
+			expectedOutput string = "{\"level\":\"fatal\",\"time\":\"2003-05-01T00:00:00Z\",\"message\":\"Hello, world! :D\"}"
+			expectedCode   int    = 1
+
+			actualCode   int    = -1
+			outputOnExit string = ""
+		)
+		usedLogger.now = func() time.Time {
+			t, _ := time.Parse(time.RFC3339, "2003-05-01T00:00:00Z")
+			return t
+		}
+		usedLogger.exit = func(code int) {
+			actualCode = code
+			outputOnExit = usedLogger.output.(*bytes.Buffer).String()
+		}
+
+		// Act.
+		actualErr := usedLogger.Fatal(ctx, "Hello, world! %s", ":D")
+
+		// Assert.
+		assert.Equal(t, expectedCode, actualCode)
+		assert.JSONEq(t, expectedOutput, outputOnExit, "the message should be written before exiting")
+		assert.NoError(t, actualErr)
+	})
+}
+
+func TestZerologLogger_SetLevel(t *testing.T) {
+	t.Run("it should drop debug messages at the info level", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx context.Context = context.Background()
+
+			usedOutput    io.Writer = bytes.NewBuffer([]byte{})
+			usedLogger, _           = NewZerologLogger(usedOutput)
+
+			expectedOutput string = ""
+		)
+		usedLogger.SetLevel(Info)
+
+		// Act.
+		actualErr := usedLogger.Debug(ctx, "Hello, world! %s", ":D")
+
+		// Assert.
+		assert.Equal(t, expectedOutput, usedLogger.output.(*bytes.Buffer).String())
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("it should log debug messages at the debug level", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx context.Context = context.Background()
+
+			usedOutput    io.Writer = bytes.NewBuffer([]byte{})
+			usedLogger, _           = NewZerologLogger(usedOutput)
+
+			expectedOutput string = "{\"level\":\"debug\",\"time\":\"2003-05-01T00:00:00Z\",\"message\":\"Hello, world! :D\"}"
+		)
+		usedLogger.now = func() time.Time {
+			t, _ := time.Parse(time.RFC3339, "2003-05-01T00:00:00Z")
+			return t
+		}
+		usedLogger.SetLevel(Debug)
+
+		// Act.
+		actualErr := usedLogger.Debug(ctx, "Hello, world! %s", ":D")
+
+		// Assert.
+		assert.JSONEq(t, expectedOutput, usedLogger.output.(*bytes.Buffer).String())
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("it should log messages above the level", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx context.Context = context.Background()
+
+			usedOutput    io.Writer = bytes.NewBuffer([]byte{})
+			usedLogger, _           = NewZerologLogger(usedOutput)
+
+			expectedOutput string = "{\"level\":\"error\",\"time\":\"2003-05-01T00:00:00Z\",\"message\":\"Hello, world! :D\"}"
+		)
+		usedLogger.now = func() time.Time {
+			t, _ := time.Parse(time.RFC3339, "2003-05-01T00:00:00Z")
+			return t
+		}
+		usedLogger.SetLevel(Warn)
+
+		// Act.
+		_ = usedLogger.Info(ctx, "Dropped")
+		actualErr := usedLogger.Error(ctx, "Hello, world! %s", ":D")
+
+		// Assert.
+		assert.JSONEq(t, expectedOutput, usedLogger.output.(*bytes.Buffer).String())
+		assert.NoError(t, actualErr)
+	})
+}
+
+func TestZerologLogger_CorrelationID(t *testing.T) {
+	t.Run("it should include the correlation ID of the context", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx context.Context = ContextWithCorrelationID(context.Background(), "request-1/0")
+
+			usedOutput    io.Writer = bytes.NewBuffer([]byte{})
+			usedLogger, _           = NewZerologLogger(usedOutput)
+
+			expectedOutput string = "{\"level\":\"info\",\"correlation_id\":\"request-1/0\",\"time\":\"2003-05-01T00:00:00Z\",\"message\":\"Hello, world! :D\"}"
+		)
+		usedLogger.now = func() time.Time {
+			t, _ := time.Parse(time.RFC3339, "2003-05-01T00:00:00Z")
+			return t
+		}
+
+		// Act.
+		actualErr := usedLogger.Info(ctx, "Hello, world! %s", ":D")
+
+		// Assert.
+		assert.JSONEq(t, expectedOutput, usedLogger.output.(*bytes.Buffer).String())
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("it should include the correlation ID of derived contexts", func(t *testing.T) {
+		// Arrange.
+		var (
+			parentCtx   context.Context = ContextWithCorrelationID(context.Background(), "request-1/0")
+			ctx, cancel                 = context.WithCancel(parentCtx)
+
+			usedOutput    io.Writer = bytes.NewBuffer([]byte{})
+			usedLogger, _           = NewZerologLogger(usedOutput)
+		)
+		defer cancel()
+
+		// Act.
+		actualErr := usedLogger.Info(ctx, "Hello, world!")
+
+		// Assert.
+		assert.Contains(t, usedLogger.output.(*bytes.Buffer).String(), "\"correlation_id\":\"request-1/0\"")
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("it should not include a correlation ID when absent", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx context.Context = context.Background()
+
+			usedOutput    io.Writer = bytes.NewBuffer([]byte{})
+			usedLogger, _           = NewZerologLogger(usedOutput)
+
+			expectedOutput string = "{\"level\":\"info\",\"time\":\"2003-05-01T00:00:00Z\",\"message\":\"Hello, world!\"}"
+		)
+		usedLogger.now = func() time.Time {
+			t, _ := time.Parse(time.RFC3339, "2003-05-01T00:00:00Z")
+			return t
+		}
+
+		// Act.
+		actualErr := usedLogger.Info(ctx, "Hello, world!")
+
+		// Assert.
+		assert.JSONEq(t, expectedOutput, usedLogger.output.(*bytes.Buffer).String())
+		assert.NoError(t, actualErr)
+	})
+}
+
+// syncRecorder is a writer that records whether Sync was invoked.
+type syncRecorder struct {
+	bytes.Buffer
+	synced  bool
+	syncErr error
+}
+
+func (writer *syncRecorder) Sync() error {
+	writer.synced = true
+	return writer.syncErr
+}
+
+// flushRecorder is a writer that records whether Flush was invoked.
+type flushRecorder struct {
+	bytes.Buffer
+	flushed bool
+}
+
+func (writer *flushRecorder) Flush() error {
+	writer.flushed = true
+	return nil
+}
+
+func TestZerologLogger_Sync(t *testing.T) {
+	t.Run("it should sync writers that implement Sync", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx           context.Context = context.Background()
+			usedOutput    *syncRecorder   = new(syncRecorder)
+			usedLogger, _                 = NewZerologLogger(usedOutput)
+		)
+
+		// Act.
+		actualErr := usedLogger.Sync(ctx)
+
+		// Assert.
+		assert.True(t, usedOutput.synced)
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("it should flush writers that implement Flush", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx           context.Context = context.Background()
+			usedOutput    *flushRecorder  = new(flushRecorder)
+			usedLogger, _                 = NewZerologLogger(usedOutput)
+		)
+
+		// Act.
+		actualErr := usedLogger.Sync(ctx)
+
+		// Assert.
+		assert.True(t, usedOutput.flushed)
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("it should sync the original writer of the console format", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx           context.Context = context.Background()
+			usedOutput    *syncRecorder   = new(syncRecorder)
+			usedLogger, _                 = NewZerologLoggerWithFormat(usedOutput, Console)
+		)
+
+		// Act.
+		actualErr := usedLogger.Sync(ctx)
+
+		// Assert.
+		assert.True(t, usedOutput.synced)
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("it should return the sync error", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx           context.Context = context.Background()
+			usedOutput    *syncRecorder   = &syncRecorder{syncErr: io.ErrShortWrite}
+			usedLogger, _                 = NewZerologLogger(usedOutput)
+		)
+
+		// Act.
+		actualErr := usedLogger.Sync(ctx)
+
+		// Assert.
+		assert.ErrorIs(t, actualErr, io.ErrShortWrite)
+	})
+
+	t.Run("it should ignore writers that can't be synced", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx           context.Context = context.Background()
+			usedOutput    *syncRecorder   = &syncRecorder{syncErr: &os.PathError{Op: "sync", Path: "/dev/stdout", Err: syscall.EINVAL}}
+			usedLogger, _                 = NewZerologLogger(usedOutput)
+		)
+
+		// Act.
+		actualErr := usedLogger.Sync(ctx)
+
+		// Assert.
+		assert.True(t, usedOutput.synced)
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("it should do nothing for plain writers", func(t *testing.T) {
+		// Arrange.
+		var (
+			ctx           context.Context = context.Background()
+			usedLogger, _                 = NewZerologLogger(new(bytes.Buffer))
+		)
+
+		// Act.
+		actualErr := usedLogger.Sync(ctx)
+
+		// Assert.
+		assert.NoError(t, actualErr)
+	})
 }